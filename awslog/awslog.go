@@ -0,0 +1,208 @@
+package awslog
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Field names logged by the middleware.
+const (
+	FieldService   = "aws_service"
+	FieldOperation = "aws_operation"
+	FieldRegion    = "aws_region"
+	FieldRequestID = "aws_api_request_id" // lambdalog logs the invocation's as aws_request_id
+	FieldErrorCode = "aws_error_code"
+	FieldAttempts  = "attempts"
+	FieldBucket    = "bucket"
+	FieldKey       = "key"
+)
+
+// MiddlewareID is the ID of the logging middleware in the stack.
+const MiddlewareID = "zerowrap.awslog"
+
+// Options configures AWS API call logging.
+type Options struct {
+	// Level is the level of successful calls. Calls failing with a client
+	// error (access denied, not found, throttling...) or canceled log at
+	// warn, other failures at error.
+	// Defaults to debug if 0.
+	Level zerolog.Level
+
+	// SlowThreshold logs calls taking longer than this, retries included,
+	// at warn level. Disabled if 0.
+	SlowThreshold time.Duration
+}
+
+// Call describes a finished API call, as passed to Options.Log.
+type Call struct {
+	Service   string
+	Operation string
+	Region    string
+	RequestID string
+
+	// Bucket and Key are the Bucket and Key input fields of the
+	// operation, as for S3 objects. Empty for other operations.
+	Bucket string
+	Key    string
+
+	// Attempts is the number of attempts made, 1 without retries.
+	Attempts int
+
+	// StatusCode is the HTTP status of the last response; 0 if none was
+	// received.
+	StatusCode int
+
+	Err      error
+	Duration time.Duration
+}
+
+// AddMiddleware returns an API option adding the logging middleware to
+// the stack of every call of a client: each call is logged once, with
+// the logger of its context, after all its retries. The middleware is
+// added at the end of the initialize step, after the service metadata
+// is set and before the retry loop.
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	cfg.APIOptions = append(cfg.APIOptions, awslog.AddMiddleware(awslog.Options{}))
+//	s3Client := s3.NewFromConfig(cfg)
+//
+//	_, err = s3Client.GetObject(ctx, &s3.GetObjectInput{Bucket: aws.String("invoices"), Key: aws.String(key)})
+//	// "aws call completed" with aws_service=S3 aws_operation=GetObject bucket=invoices key=...
+//	// aws_api_request_id=... attempts=1 status=200 duration_ms=...
+func AddMiddleware(opts Options) func(*middleware.Stack) error {
+	return func(stack *middleware.Stack) error {
+		return stack.Initialize.Add(middleware.InitializeMiddlewareFunc(MiddlewareID,
+			func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+				start := time.Now()
+				out, metadata, err := next.HandleInitialize(ctx, in)
+
+				call := Call{
+					Service:   awsmiddleware.GetServiceID(ctx),
+					Operation: awsmiddleware.GetOperationName(ctx),
+					Region:    awsmiddleware.GetRegion(ctx),
+					Attempts:  1,
+					Err:       err,
+					Duration:  time.Since(start),
+				}
+				if call.Service == "" {
+					call.Service = middleware.GetServiceID(ctx)
+				}
+				if call.Operation == "" {
+					call.Operation = middleware.GetOperationName(ctx)
+				}
+				call.Bucket, call.Key = bucketKey(in.Parameters)
+				call.RequestID, _ = awsmiddleware.GetRequestIDMetadata(metadata)
+				if results, ok := retry.GetAttemptResults(metadata); ok && len(results.Results) > 0 {
+					call.Attempts = len(results.Results)
+				}
+				if resp, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response); ok && resp != nil {
+					call.StatusCode = resp.StatusCode
+				}
+				var re interface{ HTTPStatusCode() int }
+				if call.StatusCode == 0 && errors.As(err, &re) {
+					call.StatusCode = re.HTTPStatusCode()
+				}
+				var rid interface{ ServiceRequestID() string }
+				if call.RequestID == "" && errors.As(err, &rid) {
+					call.RequestID = rid.ServiceRequestID()
+				}
+
+				opts.Log(zerowrap.FromCtx(ctx), call)
+				return out, metadata, err
+			}), middleware.After)
+	}
+}
+
+// Log writes the completion entry of call c.
+func (o Options) Log(log zerowrap.Logger, c Call) {
+	e := log.WithLevel(o.level(c))
+	if e == nil {
+		return
+	}
+	e = e.Str(FieldService, c.Service).Str(FieldOperation, c.Operation)
+	if c.Region != "" {
+		e = e.Str(FieldRegion, c.Region)
+	}
+	if c.Bucket != "" {
+		e = e.Str(FieldBucket, c.Bucket)
+	}
+	if c.Key != "" {
+		e = e.Str(FieldKey, c.Key)
+	}
+	if c.RequestID != "" {
+		e = e.Str(FieldRequestID, c.RequestID)
+	}
+	e = e.Int(FieldAttempts, c.Attempts)
+	if c.StatusCode != 0 {
+		e = e.Int(zerowrap.FieldStatus, c.StatusCode)
+	}
+	if c.Err != nil {
+		var apiErr smithy.APIError
+		if errors.As(c.Err, &apiErr) {
+			e = e.Str(FieldErrorCode, apiErr.ErrorCode())
+		}
+		zerowrap.AddDuration(e.Err(c.Err), c.Duration).Msg("aws call failed")
+		return
+	}
+	zerowrap.AddDuration(e, c.Duration).Msg("aws call completed")
+}
+
+// level returns the log level of call c.
+func (o Options) level(c Call) zerolog.Level {
+	if c.Err != nil {
+		var apiErr smithy.APIError
+		switch {
+		case errors.Is(c.Err, context.Canceled):
+			return zerolog.WarnLevel
+		case errors.As(c.Err, &apiErr) && apiErr.ErrorFault() == smithy.FaultClient,
+			c.StatusCode >= 400 && c.StatusCode < 500:
+			return zerolog.WarnLevel
+		}
+		return zerolog.ErrorLevel
+	}
+	if o.SlowThreshold > 0 && c.Duration >= o.SlowThreshold {
+		return zerolog.WarnLevel
+	}
+	return o.Level
+}
+
+// bucketKey returns the Bucket and Key fields of an operation input, as
+// S3 object operations have.
+func bucketKey(params any) (bucket, key string) {
+	v := reflect.ValueOf(params)
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", ""
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return "", ""
+	}
+	return stringField(v, "Bucket"), stringField(v, "Key")
+}
+
+// stringField returns the value of the *string or string field name of
+// struct v, or "" if it has none.
+func stringField(v reflect.Value, name string) string {
+	f := v.FieldByName(name)
+	switch {
+	case !f.IsValid():
+		return ""
+	case f.Kind() == reflect.String:
+		return f.String()
+	case f.Kind() == reflect.Ptr && !f.IsNil() && f.Elem().Kind() == reflect.String:
+		return f.Elem().String()
+	}
+	return ""
+}
@@ -0,0 +1,43 @@
+// Package awslog logs AWS SDK for Go v2 API calls for zerowrap.
+//
+// This is an optional sub-package that adds the aws-sdk-go-v2 and
+// smithy-go dependencies. AddMiddleware adds a middleware to the smithy
+// stack of every call of a client, logging the call once it completes,
+// retries included, with the logger of its context: service, operation,
+// region, the AWS request ID, the number of attempts, the HTTP status,
+// the error code and the duration. For S3 object operations, the bucket
+// and key are logged as well.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/aws/aws-sdk-go-v2/config"
+//	    "github.com/aws/aws-sdk-go-v2/service/s3"
+//	    "github.com/bnema/zerowrap/awslog"
+//	)
+//
+//	cfg, err := config.LoadDefaultConfig(ctx)
+//	cfg.APIOptions = append(cfg.APIOptions, awslog.AddMiddleware(awslog.Options{
+//	    SlowThreshold: time.Second,
+//	}))
+//	client := s3.NewFromConfig(cfg)
+//
+//	_, err = client.HeadObject(ctx, &s3.HeadObjectInput{Bucket: aws.String("invoices"), Key: aws.String(key)})
+//	// {"level":"warn","aws_service":"S3","aws_operation":"HeadObject","aws_region":"eu-west-1",
+//	//  "bucket":"invoices","key":"2024/06/0042.pdf","aws_api_request_id":"...","attempts":1,
+//	//  "status":404,"aws_error_code":"NotFound","error":"...","message":"aws call failed"}
+//
+// To log the calls of one client only, pass the option to it:
+//
+//	client := s3.NewFromConfig(cfg, func(o *s3.Options) {
+//	    o.APIOptions = append(o.APIOptions, awslog.AddMiddleware(awslog.Options{}))
+//	})
+//
+// # Levels
+//
+// Successful calls log at Options.Level (debug by default), slow ones at
+// warn. Client errors (not found, access denied, throttling) and
+// canceled calls log at warn, other failures at error. Inside a Lambda
+// handler wrapped with lambdalog, entries also carry the invocation's
+// aws_request_id.
+package awslog
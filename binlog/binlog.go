@@ -0,0 +1,98 @@
+package binlog
+
+import (
+	"bufio"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/rs/zerolog"
+)
+
+// CBOR tags used by zerolog's binary encoder.
+const (
+	tagEmbeddedJSON = 262
+	tagHexString    = 263
+)
+
+// decMode decodes zerolog entries, keeping map keys as strings.
+var decMode, _ = cbor.DecOptions{
+	TimeTag: cbor.DecTagOptional,
+}.DecMode()
+
+// Decode converts zerolog binary (CBOR) output read from src to JSON
+// lines written to dst, until src is exhausted.
+//
+//	f, _ := os.Open("app.cbor")
+//	err := binlog.Decode(os.Stdout, f)
+func Decode(dst io.Writer, src io.Reader) error {
+	dec := decMode.NewDecoder(bufio.NewReader(src))
+	w := bufio.NewWriter(dst)
+	for {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			if errors.Is(err, io.EOF) {
+				return w.Flush()
+			}
+			_ = w.Flush()
+			return fmt.Errorf("binlog: %w", err)
+		}
+		line, err := json.Marshal(toJSON(v))
+		if err != nil {
+			_ = w.Flush()
+			return fmt.Errorf("binlog: %w", err)
+		}
+		_, _ = w.Write(line)
+		if err := w.WriteByte('\n'); err != nil {
+			return err
+		}
+	}
+}
+
+// DecodeEntry converts one CBOR-encoded zerolog entry to JSON.
+func DecodeEntry(p []byte) ([]byte, error) {
+	var v any
+	if err := decMode.Unmarshal(p, &v); err != nil {
+		return nil, fmt.Errorf("binlog: %w", err)
+	}
+	return json.Marshal(toJSON(v))
+}
+
+// toJSON converts a decoded CBOR value to a JSON-encodable value.
+func toJSON(v any) any {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, val := range v {
+			m[fmt.Sprint(k)] = toJSON(val)
+		}
+		return m
+	case []any:
+		for i, val := range v {
+			v[i] = toJSON(val)
+		}
+		return v
+	case []byte:
+		return string(v)
+	case time.Time:
+		return v.Format(zerolog.TimeFieldFormat)
+	case cbor.Tag:
+		switch v.Number {
+		case tagEmbeddedJSON:
+			if b, ok := v.Content.([]byte); ok && json.Valid(b) {
+				return json.RawMessage(b)
+			}
+		case tagHexString:
+			if b, ok := v.Content.([]byte); ok {
+				return hex.EncodeToString(b)
+			}
+		}
+		return toJSON(v.Content)
+	default:
+		return v
+	}
+}
@@ -0,0 +1,43 @@
+package binlog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/binlog"
+)
+
+func TestDecodeCBORFormat(t *testing.T) {
+	var bin bytes.Buffer
+	log := zerowrap.New(zerowrap.Config{Format: "cbor", Output: &bin})
+	log.Info().Str("user", "ada").Int("attempt", 2).Float64("ratio", 0.5).Msg("login")
+	log.Warn().Strs("tags", []string{"a", "b"}).Msg("retry")
+
+	var out bytes.Buffer
+	if err := binlog.Decode(&out, &bin); err != nil {
+		t.Fatal(err)
+	}
+	lines := bytes.Split(bytes.TrimSpace(out.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("decoded %d entries, want 2: %s", len(lines), out.Bytes())
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"level": "info", "user": "ada", "attempt": float64(2), "ratio": 0.5, "message": "login"}
+	for k, v := range want {
+		if first[k] != v {
+			t.Errorf("%s = %v, want %v", k, first[k], v)
+		}
+	}
+	if _, ok := first["time"].(string); !ok {
+		t.Errorf("time = %v, want the JSON timestamp", first["time"])
+	}
+	if !bytes.Contains(lines[1], []byte(`"tags":["a","b"]`)) {
+		t.Errorf("second entry = %s", lines[1])
+	}
+}
@@ -0,0 +1,18 @@
+// Package binlog decodes binary (CBOR) log output back to JSON.
+//
+// The "cbor" format of zerowrap.Config writes each entry as a CBOR map,
+// smaller than its JSON for I/O-bound services:
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "cbor", Output: f})
+//
+// Output of zerolog built with the binary_log tag, which encodes CBOR
+// itself, is decoded too.
+//
+// Binary logs are not human-readable; convert them with Decode, or with
+// the zerowrap-decode command:
+//
+//	go get -tool github.com/bnema/zerowrap/cmd/zerowrap-decode
+//	go tool zerowrap-decode < app.cbor
+//
+// This is an optional sub-package that adds the fxamacker/cbor dependency.
+package binlog
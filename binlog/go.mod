@@ -0,0 +1,19 @@
+module github.com/bnema/zerowrap/binlog
+
+go 1.25
+
+require (
+	github.com/bnema/zerowrap v0.0.0-00010101000000-000000000000
+	github.com/fxamacker/cbor/v2 v2.9.4
+	github.com/rs/zerolog v1.34.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+replace github.com/bnema/zerowrap => ../
@@ -0,0 +1,55 @@
+package zerowrap
+
+import "context"
+
+// Circuit breaker states, for BreakerTransition.
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half-open"
+)
+
+// BreakerTransition describes a circuit breaker state change.
+type BreakerTransition struct {
+	// Name identifies the breaker, logged as component.
+	Name string
+
+	// From and To are the previous and new states, such as BreakerClosed,
+	// BreakerOpen and BreakerHalfOpen.
+	From string
+	To   string
+
+	// Requests and Failures are the requests and consecutive failures
+	// counted in the state being left.
+	Requests int
+	Failures int
+
+	// LastErr is the last failure seen before the change, if any.
+	LastErr error
+}
+
+// LogBreakerTransition logs a circuit breaker state change with the
+// logger from ctx: at warn level when the breaker opens, at info level
+// otherwise. Adapters for resilience libraries call it from their state
+// change hooks (see the breakerlog sub-package for sony/gobreaker):
+//
+//	zerowrap.LogBreakerTransition(ctx, zerowrap.BreakerTransition{
+//	    Name: "payments-api", From: zerowrap.BreakerClosed, To: zerowrap.BreakerOpen,
+//	    Failures: 5, LastErr: err,
+//	})
+func LogBreakerTransition(ctx context.Context, t BreakerTransition) {
+	log := FromCtx(ctx)
+	e := log.Info()
+	if t.To == BreakerOpen {
+		e = log.Warn()
+	}
+	e = e.Str(FieldComponent, t.Name).
+		Str(FieldBreakerFrom, t.From).
+		Str(FieldBreakerState, t.To).
+		Int(FieldRequests, t.Requests).
+		Int(FieldFailures, t.Failures)
+	if t.LastErr != nil {
+		e = e.Err(t.LastErr)
+	}
+	e.Msg("circuit breaker state changed")
+}
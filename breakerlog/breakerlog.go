@@ -0,0 +1,91 @@
+package breakerlog
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bnema/zerowrap"
+	"github.com/sony/gobreaker/v2"
+)
+
+// Settings returns st with hooks that log every state change of the
+// breaker with zerowrap.LogBreakerTransition and the logger from ctx,
+// including the requests and consecutive failures counted before the
+// change and the last error. Hooks already set in st are still called.
+//
+//	cb := gobreaker.NewCircuitBreaker[[]byte](breakerlog.Settings(ctx, gobreaker.Settings{
+//	    Name:    "payments-api",
+//	    Timeout: 30 * time.Second,
+//	}))
+//
+// Use one Settings value per breaker, as it tracks that breaker's counts.
+func Settings(ctx context.Context, st gobreaker.Settings) gobreaker.Settings {
+	t := &tracker{ctx: ctx, next: st}
+	st.ReadyToTrip = t.readyToTrip
+	st.IsSuccessful = t.isSuccessful
+	st.OnStateChange = t.onStateChange
+	return st
+}
+
+// tracker mirrors the breaker counts between state changes. gobreaker
+// calls its hooks with the breaker locked, so the breaker itself cannot
+// be queried from onStateChange.
+type tracker struct {
+	ctx  context.Context
+	next gobreaker.Settings
+
+	mu       sync.Mutex
+	requests int
+	failures int
+	lastErr  error
+}
+
+func (t *tracker) isSuccessful(err error) bool {
+	ok := err == nil
+	if t.next.IsSuccessful != nil {
+		ok = t.next.IsSuccessful(err)
+	}
+	t.mu.Lock()
+	t.requests++
+	if ok {
+		t.failures = 0
+	} else {
+		t.failures++
+		t.lastErr = err
+	}
+	t.mu.Unlock()
+	return ok
+}
+
+func (t *tracker) readyToTrip(counts gobreaker.Counts) bool {
+	t.mu.Lock()
+	t.requests = int(counts.Requests)
+	t.failures = int(counts.ConsecutiveFailures)
+	t.mu.Unlock()
+	if t.next.ReadyToTrip != nil {
+		return t.next.ReadyToTrip(counts)
+	}
+	return counts.ConsecutiveFailures > 5
+}
+
+func (t *tracker) onStateChange(name string, from, to gobreaker.State) {
+	t.mu.Lock()
+	transition := zerowrap.BreakerTransition{
+		Name:     name,
+		From:     from.String(),
+		To:       to.String(),
+		Requests: t.requests,
+		Failures: t.failures,
+		LastErr:  t.lastErr,
+	}
+	t.requests, t.failures = 0, 0
+	if to == gobreaker.StateClosed {
+		t.lastErr = nil
+	}
+	t.mu.Unlock()
+
+	zerowrap.LogBreakerTransition(t.ctx, transition)
+	if t.next.OnStateChange != nil {
+		t.next.OnStateChange(name, from, to)
+	}
+}
@@ -0,0 +1,29 @@
+// Package breakerlog logs sony/gobreaker circuit breaker state changes
+// for zerowrap.
+//
+// This is an optional sub-package that adds the gobreaker dependency.
+// Settings wraps the breaker hooks so every transition (closed to open,
+// open to half-open, half-open to closed) is logged through
+// zerowrap.LogBreakerTransition with the breaker name as component, the
+// requests and consecutive failures counted before the change, and the
+// last error.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap/breakerlog"
+//	    "github.com/sony/gobreaker/v2"
+//	)
+//
+//	cb := gobreaker.NewCircuitBreaker[*http.Response](breakerlog.Settings(ctx, gobreaker.Settings{
+//	    Name:    "payments-api",
+//	    Timeout: 30 * time.Second,
+//	}))
+//
+// Opening logs at warn level, other transitions at info.
+//
+// # Other Libraries
+//
+// For other resilience libraries, call zerowrap.LogBreakerTransition from
+// their state change hook.
+package breakerlog
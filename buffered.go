@@ -0,0 +1,115 @@
+package zerowrap
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// bufferedWriter buffers entries in memory and writes them to the
+// underlying writer when the buffer fills, every flush interval, on an
+// entry at warn level or above, and on Close.
+type bufferedWriter struct {
+	mu  sync.Mutex
+	w   io.Writer
+	buf *bufio.Writer
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newBufferedWriter wraps w with a size byte buffer flushed every interval.
+func newBufferedWriter(w io.Writer, size int, interval time.Duration) *bufferedWriter {
+	b := &bufferedWriter{
+		w:    w,
+		buf:  bufio.NewWriterSize(w, size),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go b.run(interval)
+	return b
+}
+
+// Write implements io.Writer.
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	return b.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Entries are never split
+// across writes to the underlying writer, so a rotating file never cuts
+// a line in two.
+func (b *bufferedWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(p) > b.buf.Available() && b.buf.Buffered() > 0 {
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+	}
+	var n int
+	var err error
+	if len(p) > b.buf.Size() {
+		n, err = b.w.Write(p)
+	} else {
+		n, err = b.buf.Write(p)
+	}
+	if err != nil {
+		return n, err
+	}
+	if level >= zerolog.WarnLevel && level != zerolog.NoLevel && level != zerolog.Disabled {
+		if err := b.flush(); err != nil {
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+// Flush writes buffered entries to the underlying writer.
+func (b *bufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flush()
+}
+
+// flush flushes the buffer. A failed flush drops the buffered entries,
+// as bufio keeps failing after an error.
+func (b *bufferedWriter) flush() error {
+	if err := b.buf.Flush(); err != nil {
+		b.buf.Reset(b.w)
+		return err
+	}
+	return nil
+}
+
+// run flushes the buffer every interval until Close.
+func (b *bufferedWriter) run(interval time.Duration) {
+	defer close(b.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := b.Flush(); err != nil {
+				ReportError(fmt.Errorf("%w: %w", ErrWrite, err))
+			}
+		case <-b.stop:
+			return
+		}
+	}
+}
+
+// Close stops the periodic flush and flushes pending entries. It does
+// not close the underlying writer.
+func (b *bufferedWriter) Close() error {
+	b.once.Do(func() {
+		close(b.stop)
+		<-b.done
+	})
+	return b.Flush()
+}
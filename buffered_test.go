@@ -0,0 +1,120 @@
+package zerowrap
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// benchEntry is a typical JSON entry of a request log.
+var benchEntry = []byte(`{"level":"info","service":"api","request_id":"r-1","method":"GET","path":"/users/42","status":200,"duration_ms":1.25,"time":"2026-01-02T15:04:05Z","message":"request completed"}` + "\n")
+
+// benchFile returns a file in a temporary directory, closed at the end
+// of the benchmark.
+func benchFile(b *testing.B) *os.File {
+	f, err := os.Create(filepath.Join(b.TempDir(), "app.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.Cleanup(func() { _ = f.Close() })
+	return f
+}
+
+// BenchmarkFileWrite is the baseline of BenchmarkBufferedFileWrite: one
+// write syscall per entry.
+func BenchmarkFileWrite(b *testing.B) {
+	f := benchFile(b)
+	b.SetBytes(int64(len(benchEntry)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := f.Write(benchEntry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferedFileWrite(b *testing.B) {
+	w := newBufferedWriter(benchFile(b), 256<<10, time.Second)
+	defer w.Close()
+	b.SetBytes(int64(len(benchEntry)))
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := w.WriteLevel(zerolog.InfoLevel, benchEntry); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkBufferedFileWriteParallel(b *testing.B) {
+	w := newBufferedWriter(benchFile(b), 256<<10, time.Second)
+	defer w.Close()
+	b.SetBytes(int64(len(benchEntry)))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := w.WriteLevel(zerolog.InfoLevel, benchEntry); err != nil {
+				b.Error(err)
+				return
+			}
+		}
+	})
+}
+
+// writeRecorder records the writes it receives.
+type writeRecorder struct {
+	writes []string
+}
+
+func (r *writeRecorder) Write(p []byte) (int, error) {
+	r.writes = append(r.writes, string(p))
+	return len(p), nil
+}
+
+func TestBufferedWriterFlush(t *testing.T) {
+	rec := &writeRecorder{}
+	w := newBufferedWriter(rec, 64, time.Hour)
+	defer w.Close()
+
+	_, _ = w.WriteLevel(zerolog.InfoLevel, []byte(`{"n":1}`+"\n"))
+	_, _ = w.WriteLevel(zerolog.DebugLevel, []byte(`{"n":2}`+"\n"))
+	if len(rec.writes) != 0 {
+		t.Fatalf("info entries written before flush: %q", rec.writes)
+	}
+	_, _ = w.WriteLevel(zerolog.WarnLevel, []byte(`{"n":3}`+"\n"))
+	if len(rec.writes) != 1 || strings.Count(rec.writes[0], "\n") != 3 {
+		t.Fatalf("warn entry did not flush the buffer: %q", rec.writes)
+	}
+
+	_, _ = w.WriteLevel(zerolog.InfoLevel, []byte(`{"n":4}`+"\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(rec.writes) != 2 || rec.writes[1] != `{"n":4}`+"\n" {
+		t.Errorf("Close did not flush the buffer: %q", rec.writes)
+	}
+}
+
+func TestBufferedWriterKeepsEntriesWhole(t *testing.T) {
+	rec := &writeRecorder{}
+	w := newBufferedWriter(rec, 32, time.Hour)
+	defer w.Close()
+
+	entry := []byte(`{"message":"twenty bytes"}` + "\n")
+	for i := 0; i < 5; i++ {
+		_, _ = w.WriteLevel(zerolog.InfoLevel, entry)
+	}
+	large := bytes.Repeat([]byte("x"), 100)
+	_, _ = w.WriteLevel(zerolog.InfoLevel, append(large, '\n'))
+	_ = w.Flush()
+
+	for _, p := range rec.writes {
+		if !strings.HasSuffix(p, "\n") {
+			t.Errorf("write %q ends within an entry", p)
+		}
+	}
+}
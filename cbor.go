@@ -0,0 +1,143 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// CBOR major types.
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+)
+
+// cborWriter transcodes JSON entries to CBOR (RFC 8949), one data item
+// per entry with the fields in entry order, as read by the binlog
+// sub-package.
+type cborWriter struct {
+	out io.Writer
+}
+
+// newCBORWriter returns the writer of the "cbor" format.
+func newCBORWriter(out io.Writer) zerolog.LevelWriter {
+	return cborWriter{out: out}
+}
+
+// Write implements io.Writer.
+func (w cborWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Entries that are not JSON,
+// such as those zerolog encodes itself when built with binary_log, are
+// written unchanged.
+func (w cborWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	buf, err := jsonToCBOR(p)
+	if err != nil {
+		return writeLevel(w.out, level, p)
+	}
+	if _, err := writeLevel(w.out, level, buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// jsonToCBOR transcodes one JSON value. Objects and arrays are encoded
+// with indefinite lengths, so they are written as they are read.
+func jsonToCBOR(p []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch tok := tok.(type) {
+		case json.Delim:
+			switch tok {
+			case '{':
+				buf.WriteByte(cborMap | 31)
+				depth++
+			case '[':
+				buf.WriteByte(cborArray | 31)
+				depth++
+			default:
+				buf.WriteByte(0xff) // break
+				depth--
+			}
+		case string:
+			cborHead(&buf, cborText, uint64(len(tok)))
+			buf.WriteString(tok)
+		case json.Number:
+			cborNumber(&buf, tok)
+		case bool:
+			if tok {
+				buf.WriteByte(0xf5)
+			} else {
+				buf.WriteByte(0xf4)
+			}
+		case nil:
+			buf.WriteByte(0xf6)
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("zerowrap: trailing data after JSON entry")
+	}
+	return buf.Bytes(), nil
+}
+
+// cborNumber appends n as an integer when it is one, as a float64
+// otherwise.
+func cborNumber(buf *bytes.Buffer, n json.Number) {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		if i >= 0 {
+			cborHead(buf, cborUint, uint64(i))
+		} else {
+			cborHead(buf, cborNegInt, uint64(-1-i))
+		}
+		return
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		cborHead(buf, cborUint, u)
+		return
+	}
+	f, _ := n.Float64()
+	buf.WriteByte(0xfb)
+	_ = binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+}
+
+// cborHead appends the head of a data item: its major type and an
+// argument (value or length) in the shortest encoding.
+func cborHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 24)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	default:
+		buf.WriteByte(major | 27)
+		_ = binary.Write(buf, binary.BigEndian, n)
+	}
+}
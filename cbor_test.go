@@ -0,0 +1,43 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestJSONToCBOR(t *testing.T) {
+	tests := []struct {
+		json string
+		cbor string
+	}{
+		{`{"a":1}`, "bf616101ff"},
+		{`{"n":-1,"big":1000}`, "bf616e20636269671903e8ff"},
+		{`{"f":1.5}`, "bf6166fb3ff8000000000000ff"},
+		{`{"ok":true,"no":false,"x":null}`, "bf626f6bf5626e6ff46178f6ff"},
+		{`{"l":["a",{}]}`, "bf616c9f6161bfffffff"},
+	}
+	for _, tt := range tests {
+		got, err := jsonToCBOR([]byte(tt.json))
+		if err != nil {
+			t.Errorf("jsonToCBOR(%s): %v", tt.json, err)
+			continue
+		}
+		if hex.EncodeToString(got) != tt.cbor {
+			t.Errorf("jsonToCBOR(%s) = %x, want %s", tt.json, got, tt.cbor)
+		}
+	}
+}
+
+func TestCBORFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log := New(Config{Format: "cbor", Output: &buf})
+	log.Info().Str("user", "ada").Msg("login")
+
+	if buf.Len() == 0 || buf.Bytes()[0] != 0xbf {
+		t.Fatalf("cbor format wrote %q, want a CBOR map", buf.Bytes())
+	}
+	if bytes.Contains(buf.Bytes(), []byte(`"user"`)) {
+		t.Errorf("cbor format wrote JSON: %q", buf.Bytes())
+	}
+}
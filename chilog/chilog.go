@@ -0,0 +1,32 @@
+package chilog
+
+import (
+	"net/http"
+
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/go-chi/chi/v5"
+)
+
+// Middleware returns chi-compatible request logging middleware.
+// It behaves like httplog.Middleware, but logs chi's matched route
+// template (e.g. "/users/{id}") as the route field.
+// A RoutePattern set in opts takes precedence.
+//
+//	r := chi.NewRouter()
+//	r.Use(chilog.Middleware(httplog.Options{}))
+func Middleware(opts httplog.Options) func(http.Handler) http.Handler {
+	if opts.RoutePattern == nil {
+		opts.RoutePattern = RoutePattern
+	}
+	return httplog.Middleware(opts)
+}
+
+// RoutePattern returns the chi route template matched for r.
+// Returns an empty string if r was not routed by chi.
+func RoutePattern(r *http.Request) string {
+	rctx := chi.RouteContext(r.Context())
+	if rctx == nil {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
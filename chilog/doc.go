@@ -0,0 +1,28 @@
+// Package chilog provides chi router request logging for zerowrap.
+//
+// This is an optional sub-package that adds the chi dependency.
+// It wraps httplog.Middleware and resolves route templates from chi's
+// routing context, so logs carry "/users/{id}" rather than "/users/42".
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/chilog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	)
+//
+//	r := chi.NewRouter()
+//	r.Use(chilog.Middleware(httplog.Options{
+//	    SlowThreshold: 500 * time.Millisecond,
+//	}))
+//	r.Get("/users/{id}", getUser)
+//
+//	// The server's base context must carry the logger
+//	srv := &http.Server{
+//	    Handler: r,
+//	    BaseContext: func(net.Listener) context.Context {
+//	        return zerowrap.WithCtx(context.Background(), log)
+//	    },
+//	}
+package chilog
@@ -0,0 +1,19 @@
+module github.com/bnema/zerowrap/cmd/zerowrap-decode
+
+go 1.25
+
+require github.com/bnema/zerowrap/binlog v0.0.0-00010101000000-000000000000
+
+require (
+	github.com/fxamacker/cbor/v2 v2.9.4 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/rs/zerolog v1.34.0 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+)
+
+replace (
+	github.com/bnema/zerowrap => ../../
+	github.com/bnema/zerowrap/binlog => ../../binlog
+)
@@ -0,0 +1,40 @@
+// Command zerowrap-decode converts binary (CBOR) logs to JSON lines.
+//
+// Usage:
+//
+//	zerowrap-decode [file ...]
+//
+// Reads standard input when no file is given.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/bnema/zerowrap/binlog"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		decode(os.Stdin, "stdin")
+		return
+	}
+	for _, path := range os.Args[1:] {
+		f, err := os.Open(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "zerowrap-decode:", err)
+			os.Exit(1)
+		}
+		decode(f, path)
+		_ = f.Close()
+	}
+}
+
+// decode writes the JSON form of src to stdout, exiting on error.
+func decode(src io.Reader, name string) {
+	if err := binlog.Decode(os.Stdout, src); err != nil {
+		fmt.Fprintf(os.Stderr, "zerowrap-decode: %s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
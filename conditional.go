@@ -0,0 +1,100 @@
+package zerowrap
+
+import (
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// condState is the state of one conditional logging key.
+type condState struct {
+	mu    sync.Mutex
+	count uint64
+	last  time.Time
+	value any
+	set   bool
+}
+
+// condStates holds the state of every key used with Once, Every,
+// EveryDuration and OnChange. Keys are shared by all loggers and should
+// be constant strings, as states are never removed.
+var condStates sync.Map // string -> *condState
+
+// loadCondState returns the state of key, creating it if needed.
+func loadCondState(key string) *condState {
+	if s, ok := condStates.Load(key); ok {
+		return s.(*condState)
+	}
+	s, _ := condStates.LoadOrStore(key, &condState{})
+	return s.(*condState)
+}
+
+// nopLogger is returned when a condition is not met.
+var nopLogger = Logger{zerolog.Nop()}
+
+// Once returns the logger the first time it is called with key, and a
+// disabled logger afterwards.
+//
+//	log.Once("deprecated-config").Warn().Msg("LEGACY_MODE is deprecated")
+func (l Logger) Once(key string) Logger {
+	s := loadCondState(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	if s.count == 1 {
+		return l
+	}
+	return nopLogger
+}
+
+// Every returns the logger on the first call with key and then every nth
+// call, and a disabled logger otherwise.
+//
+//	for _, item := range items {
+//	    log.Every("import-progress", 1000).Info().Int("done", i).Msg("importing")
+//	}
+func (l Logger) Every(key string, n uint64) Logger {
+	s := loadCondState(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	if n <= 1 || (s.count-1)%n == 0 {
+		return l
+	}
+	return nopLogger
+}
+
+// EveryDuration returns the logger at most once per d for key, and a
+// disabled logger otherwise.
+//
+//	log.EveryDuration("queue-full", 10*time.Second).Warn().Msg("queue full, dropping")
+func (l Logger) EveryDuration(key string, d time.Duration) Logger {
+	s := loadCondState(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.last.IsZero() || now.Sub(s.last) >= d {
+		s.last = now
+		return l
+	}
+	return nopLogger
+}
+
+// OnChange returns the logger when value differs from the value of the
+// previous call with key (and on the first call), and a disabled logger
+// otherwise.
+//
+//	log.OnChange("leader", leaderID).Info().Str("leader", leaderID).Msg("leader changed")
+func (l Logger) OnChange(key string, value any) Logger {
+	s := loadCondState(key)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.set && reflect.DeepEqual(s.value, value) {
+		return nopLogger
+	}
+	s.value = value
+	s.set = true
+	return l
+}
@@ -0,0 +1,127 @@
+package zerowrap
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// DefaultSecretKeys lists name fragments marking config keys as secret.
+// A key is secret if its last segment contains one, ignoring case, so
+// "db.password" and "stripe_api_key" are both redacted.
+var DefaultSecretKeys = []string{
+	"password",
+	"secret",
+	"token",
+	"api_key",
+	"apikey",
+	"private_key",
+	"credential",
+}
+
+// ConfigChange is a config key whose value differs between two configs.
+// Old is nil for added keys and New is nil for removed ones. Secret
+// values are replaced by Redacted.
+type ConfigChange struct {
+	Key string
+	Old any
+	New any
+}
+
+// DiffConfig compares two config structs field by field, as extracted
+// for WithStruct (log and json tags, struct limits), and returns the
+// changed keys sorted by name. Nested structs and maps are compared by
+// their own keys, joined with dots ("db.host"); slices as a whole.
+// Values of keys matching DefaultSecretKeys are redacted.
+func DiffConfig(before, after any) []ConfigChange {
+	oldFields := flattenConfig(extractFields(before))
+	newFields := flattenConfig(extractFields(after))
+
+	keys := slices.Collect(maps.Keys(oldFields))
+	for k := range newFields {
+		if _, ok := oldFields[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	var changes []ConfigChange
+	for _, k := range keys {
+		o, inOld := oldFields[k]
+		n, inNew := newFields[k]
+		if inOld && inNew && reflect.DeepEqual(o, n) {
+			continue
+		}
+		if secretKey(k) {
+			ReportRedacted(1)
+			if inOld {
+				o = Redacted
+			}
+			if inNew {
+				n = Redacted
+			}
+		}
+		changes = append(changes, ConfigChange{Key: k, Old: o, New: n})
+	}
+	return changes
+}
+
+// LogConfigChange logs the keys that changed between two config structs
+// (see DiffConfig) at info level with their old and new values (changes
+// field), for auditing config reloads. Nothing is logged if the configs
+// are equal. It reports whether any key changed.
+//
+//	next, err := loadConfig()
+//	...
+//	zerowrap.LogConfigChange(ctx, current, next)
+func LogConfigChange(ctx context.Context, before, after any) bool {
+	changes := DiffConfig(before, after)
+	if len(changes) == 0 {
+		return false
+	}
+	fields := make(map[string]any, len(changes))
+	for _, c := range changes {
+		fields[c.Key] = map[string]any{"old": c.Old, "new": c.New}
+	}
+	log := FromCtx(ctx)
+	log.Info().
+		Int(FieldCount, len(changes)).
+		Fields(map[string]any{FieldChanges: fields}).
+		Msg("config changed")
+	return true
+}
+
+// flattenConfig flattens nested maps into dotted keys, dropping nil
+// values so a nil map and its later keys diff as added keys.
+func flattenConfig(fields map[string]any) map[string]any {
+	out := make(map[string]any, len(fields))
+	var walk func(prefix string, m map[string]any)
+	walk = func(prefix string, m map[string]any) {
+		for k, v := range m {
+			if v == nil {
+				continue
+			}
+			if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+				walk(prefix+k+".", nested)
+				continue
+			}
+			out[prefix+k] = v
+		}
+	}
+	walk("", fields)
+	return out
+}
+
+// secretKey reports whether the last segment of key contains one of
+// DefaultSecretKeys.
+func secretKey(key string) bool {
+	name := strings.ToLower(key[strings.LastIndexByte(key, '.')+1:])
+	for _, s := range DefaultSecretKeys {
+		if strings.Contains(name, s) {
+			return true
+		}
+	}
+	return false
+}
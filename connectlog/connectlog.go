@@ -0,0 +1,114 @@
+package connectlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/grpclog"
+	"google.golang.org/grpc/codes"
+)
+
+// interceptor implements connect.Interceptor on top of grpclog.Options.
+type interceptor struct {
+	opts grpclog.Options
+}
+
+// NewInterceptor returns a connect interceptor that attaches call fields
+// (request_id, grpc_service, grpc_method, client_ip) to the handler
+// context logger and logs completion with the status code and duration,
+// like the grpclog interceptors. Client calls are passed through.
+//
+//	path, handler := userv1connect.NewUserServiceHandler(svc,
+//	    connect.WithInterceptors(connectlog.NewInterceptor(grpclog.Options{})),
+//	)
+func NewInterceptor(opts grpclog.Options) connect.Interceptor {
+	return interceptor{opts: opts}
+}
+
+func (i interceptor) WrapUnary(next connect.UnaryFunc) connect.UnaryFunc {
+	return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+		if req.Spec().IsClient {
+			return next(ctx, req)
+		}
+		start := time.Now()
+		procedure := req.Spec().Procedure
+		ctx = grpclog.WithCall(ctx, procedure, req.Header().Get, req.Peer().Addr)
+		ctx = i.opts.WithDebug(ctx, req.Header().Get)
+
+		resp, err := next(ctx, req)
+
+		i.opts.Log(zerowrap.FromCtx(ctx), grpclog.Completion{
+			FullMethod: procedure,
+			Code:       Code(err),
+			Err:        err,
+			Duration:   time.Since(start),
+		})
+		return resp, err
+	}
+}
+
+func (i interceptor) WrapStreamingClient(next connect.StreamingClientFunc) connect.StreamingClientFunc {
+	return next
+}
+
+func (i interceptor) WrapStreamingHandler(next connect.StreamingHandlerFunc) connect.StreamingHandlerFunc {
+	return func(ctx context.Context, conn connect.StreamingHandlerConn) error {
+		start := time.Now()
+		procedure := conn.Spec().Procedure
+		ctx = grpclog.WithCall(ctx, procedure, conn.RequestHeader().Get, conn.Peer().Addr)
+		ctx = i.opts.WithDebug(ctx, conn.RequestHeader().Get)
+		log := zerowrap.FromCtx(ctx)
+		messages := i.opts.MessageLog(log)
+
+		err := next(ctx, &handlerConn{StreamingHandlerConn: conn, messages: messages})
+
+		i.opts.Log(log, grpclog.Completion{
+			FullMethod: procedure,
+			Code:       Code(err),
+			Err:        err,
+			Duration:   time.Since(start),
+			Messages:   messages,
+		})
+		return err
+	}
+}
+
+// Code returns the gRPC status code of a connect handler error: OK for
+// nil, the connect code otherwise (the numbering is shared with gRPC),
+// and Canceled or DeadlineExceeded for plain context errors.
+func Code(err error) codes.Code {
+	switch {
+	case err == nil:
+		return codes.OK
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return codes.DeadlineExceeded
+	}
+	return codes.Code(connect.CodeOf(err))
+}
+
+// handlerConn records the messages of a streaming handler.
+type handlerConn struct {
+	connect.StreamingHandlerConn
+	messages *grpclog.MessageLog
+}
+
+func (c *handlerConn) Send(m any) error {
+	err := c.StreamingHandlerConn.Send(m)
+	if err == nil {
+		c.messages.Sent(m)
+	}
+	return err
+}
+
+func (c *handlerConn) Receive(m any) error {
+	err := c.StreamingHandlerConn.Receive(m)
+	if err == nil {
+		c.messages.Received(m)
+	}
+	return err
+}
@@ -0,0 +1,31 @@
+// Package connectlog provides connect-go handler logging for zerowrap.
+//
+// This is an optional sub-package that adds the connect dependency.
+// Its interceptor applies grpclog.Options to connect handlers, so
+// services mixing gRPC, connect and HTTP log calls with the same fields
+// (grpc_service, grpc_method, grpc_code) and level rules.
+//
+// # Usage
+//
+//	import (
+//	    "connectrpc.com/connect"
+//	    "github.com/bnema/zerowrap/connectlog"
+//	    "github.com/bnema/zerowrap/grpclog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	)
+//
+//	interceptor := connectlog.NewInterceptor(grpclog.Options{LogMessages: true})
+//	path, handler := userv1connect.NewUserServiceHandler(svc, connect.WithInterceptors(interceptor))
+//
+//	mux := http.NewServeMux()
+//	mux.Handle(path, handler)
+//	srv := &http.Server{
+//	    Handler: httplog.Middleware(httplog.Options{})(mux), // HTTP status, size, client_ip
+//	    BaseContext: func(net.Listener) context.Context {
+//	        return zerowrap.WithCtx(context.Background(), log)
+//	    },
+//	}
+//
+// Behind httplog.Middleware, the request ID and client_ip bound by the
+// middleware are kept, and the interceptor adds the RPC fields.
+package connectlog
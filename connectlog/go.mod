@@ -0,0 +1,30 @@
+module github.com/bnema/zerowrap/connectlog
+
+go 1.25
+
+require (
+	connectrpc.com/connect v1.19.1
+	github.com/bnema/zerowrap v0.0.0-00010101000000-000000000000
+	github.com/bnema/zerowrap/grpclog v0.0.0-00010101000000-000000000000
+	google.golang.org/grpc v1.77.0
+)
+
+require (
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/rs/zerolog v1.34.0 // indirect
+	go.opentelemetry.io/otel/metric v1.39.0 // indirect
+	go.opentelemetry.io/otel/sdk v1.39.0 // indirect
+	go.opentelemetry.io/otel/trace v1.39.0 // indirect
+	golang.org/x/net v0.48.0 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	golang.org/x/text v0.32.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20251202230838-ff82c1b0f217 // indirect
+	google.golang.org/protobuf v1.36.11 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+replace (
+	github.com/bnema/zerowrap => ../
+	github.com/bnema/zerowrap/grpclog => ../grpclog
+)
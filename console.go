@@ -0,0 +1,108 @@
+package zerowrap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ConsoleConfig customizes the console format. The zero value keeps
+// zerolog's ConsoleWriter defaults.
+type ConsoleConfig struct {
+	// NoColor disables colorized output, e.g. for CI logs.
+	// Colors are also disabled when the NO_COLOR environment variable is set.
+	NoColor bool
+
+	// TimeOnly shows timestamps as "15:04:05", overriding Config.TimeFormat.
+	TimeOnly bool
+
+	// PartsOrder is the order of the line parts, using zerolog field names
+	// (zerolog.TimestampFieldName, zerolog.LevelFieldName,
+	// zerolog.CallerFieldName, zerolog.MessageFieldName).
+	PartsOrder []string
+
+	// PartsExclude lists parts not displayed.
+	PartsExclude []string
+
+	// FieldsOrder lists fields displayed first, in order; the remaining
+	// fields follow sorted by name.
+	FieldsOrder []string
+
+	// FieldsExclude lists fields not displayed, e.g. noisy context fields.
+	FieldsExclude []string
+
+	// LevelColors overrides the ANSI color code of level labels.
+	// Levels not listed use zerolog.LevelColors.
+	LevelColors map[zerolog.Level]int
+
+	// Indent spreads entries of the "json-pretty" format over several
+	// indented lines instead of one line each.
+	Indent bool
+
+	// Custom formatters replace the corresponding default formatter when set.
+	FormatTimestamp  zerolog.Formatter
+	FormatLevel      zerolog.Formatter
+	FormatCaller     zerolog.Formatter
+	FormatMessage    zerolog.Formatter
+	FormatFieldName  zerolog.Formatter
+	FormatFieldValue zerolog.Formatter
+}
+
+// newConsoleWriter creates a ConsoleWriter writing to out with cfg applied.
+func newConsoleWriter(out io.Writer, timeFormat string, cfg ConsoleConfig) zerolog.ConsoleWriter {
+	if cfg.TimeOnly {
+		timeFormat = time.TimeOnly
+	}
+	w := zerolog.ConsoleWriter{
+		Out:              out,
+		NoColor:          cfg.NoColor,
+		TimeFormat:       timeFormat,
+		PartsOrder:       cfg.PartsOrder,
+		PartsExclude:     cfg.PartsExclude,
+		FieldsOrder:      cfg.FieldsOrder,
+		FieldsExclude:    cfg.FieldsExclude,
+		FormatTimestamp:  cfg.FormatTimestamp,
+		FormatLevel:      cfg.FormatLevel,
+		FormatCaller:     cfg.FormatCaller,
+		FormatMessage:    cfg.FormatMessage,
+		FormatFieldName:  cfg.FormatFieldName,
+		FormatFieldValue: cfg.FormatFieldValue,
+	}
+	if w.FormatLevel == nil && len(cfg.LevelColors) > 0 {
+		w.FormatLevel = levelColorFormatter(cfg.LevelColors, cfg.NoColor)
+	}
+	return w
+}
+
+// levelColorFormatter formats level labels like zerolog's default
+// formatter, with colors taken from colors before zerolog.LevelColors.
+func levelColorFormatter(colors map[zerolog.Level]int, noColor bool) zerolog.Formatter {
+	noColor = noColor || os.Getenv("NO_COLOR") != ""
+	return func(i any) string {
+		s, _ := i.(string)
+		level, err := zerolog.ParseLevel(s)
+		label, ok := zerolog.FormattedLevels[level]
+		if err != nil || !ok {
+			if len(s) > 3 {
+				s = s[:3]
+			}
+			if s == "" {
+				return "???"
+			}
+			return strings.ToUpper(s)
+		}
+
+		color, ok := colors[level]
+		if !ok {
+			color = zerolog.LevelColors[level]
+		}
+		if noColor || color == 0 {
+			return label
+		}
+		return fmt.Sprintf("\x1b[%dm%s\x1b[0m", color, label)
+	}
+}
@@ -2,16 +2,90 @@ package zerowrap
 
 import (
 	"context"
+	"sync"
+	"sync/atomic"
 
 	"github.com/rs/zerolog"
 )
 
-// FromCtx extracts the logger from context.
+// CtxExtractor returns fields to add to loggers built from ctx, read from
+// values other middleware stored in it.
+type CtxExtractor func(ctx context.Context) map[string]any
+
+// extractors is replaced on registration, so FromCtx reads it without locking.
+var (
+	extractorsMu sync.Mutex
+	extractors   atomic.Pointer[[]CtxExtractor]
+)
+
+// RegisterCtxExtractor registers fn to run whenever FromCtx builds a
+// logger, so values already in the context (request IDs set by router
+// middleware, tenant IDs, trace IDs) are logged without callers adding
+// them again. Extractors run in registration order; they should be
+// registered at startup and return nil when their value is absent.
+//
+//	zerowrap.RegisterCtxExtractor(func(ctx context.Context) map[string]any {
+//	    if id := middleware.GetReqID(ctx); id != "" {
+//	        return map[string]any{zerowrap.FieldRequestID: id}
+//	    }
+//	    return nil
+//	})
+func RegisterCtxExtractor(fn CtxExtractor) {
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+	var fns []CtxExtractor
+	if cur := extractors.Load(); cur != nil {
+		fns = append(fns, *cur...)
+	}
+	fns = append(fns, fn)
+	extractors.Store(&fns)
+}
+
+// FromCtx extracts the logger from context, with the fields of registered
+// extractors (see RegisterCtxExtractor).
 // If no logger is found, returns a disabled (no-op) logger.
 func FromCtx(ctx context.Context) Logger {
+	return withExtracted(ctx, loggerFromCtx(ctx))
+}
+
+// loggerFromCtx returns the logger stored in ctx as is.
+func loggerFromCtx(ctx context.Context) Logger {
 	return Logger{*zerolog.Ctx(ctx)}
 }
 
+// withExtracted adds the fields of registered extractors to log.
+func withExtracted(ctx context.Context, log Logger) Logger {
+	fns := extractors.Load()
+	if fns == nil || log.GetLevel() == zerolog.Disabled {
+		return log
+	}
+	return Logger{extract(ctx, log.With(), *fns).Logger()}
+}
+
+// fromCtxWith returns a context builder for the ctx logger with the
+// extractor fields added, so callers adding more fields copy the logger
+// once. ok is false when the logger is disabled and adding fields can be
+// skipped.
+func fromCtxWith(ctx context.Context) (c zerolog.Context, ok bool) {
+	log := zerolog.Ctx(ctx)
+	if log.GetLevel() == zerolog.Disabled {
+		return c, false
+	}
+	c = log.With()
+	if fns := extractors.Load(); fns != nil {
+		c = extract(ctx, c, *fns)
+	}
+	return c, true
+}
+
+// extract adds the fields returned by fns to c.
+func extract(ctx context.Context, c zerolog.Context, fns []CtxExtractor) zerolog.Context {
+	for _, fn := range fns {
+		c = addFields(c, fn(ctx))
+	}
+	return c
+}
+
 // Ctx returns a pointer to the underlying zerolog.Logger in context.
 // This is for compatibility with zerolog's Ctx pattern.
 // If no logger is found, returns a pointer to a disabled logger.
@@ -24,6 +98,52 @@ func WithCtx(ctx context.Context, log Logger) context.Context {
 	return log.WithContext(ctx)
 }
 
+// UpdateCtx adds fields to the logger stored in ctx in place, following
+// zerolog's UpdateContext pattern: the fields become visible to every
+// FromCtx on contexts holding that logger, including parents created
+// earlier in the stack, such as a request logging middleware reading the
+// context after the handler returns. Logger values already returned by
+// FromCtx are copies and do not change.
+//
+// It reports false, changing nothing, when ctx holds no logger of its
+// own. It is not safe to call while other goroutines log with the same
+// logger; prefer CtxWithFields when a derived context suffices.
+//
+//	zerowrap.UpdateCtx(r.Context(), func(c zerolog.Context) zerolog.Context {
+//	    return c.Str(zerowrap.FieldUserID, user.ID)
+//	})
+func UpdateCtx(ctx context.Context, fn func(c zerolog.Context) zerolog.Context) bool {
+	l := zerolog.Ctx(ctx)
+	if l == zerolog.DefaultContextLogger || l.GetLevel() == zerolog.Disabled {
+		return false
+	}
+	l.UpdateContext(fn)
+	return true
+}
+
+// namedKey is the context key of a named logger.
+type namedKey struct{ name string }
+
+// WithCtxNamed attaches a logger to the context under name, next to the
+// default logger of WithCtx, so separate loggers (e.g. application and
+// audit) coexist in one request context.
+//
+//	ctx = zerowrap.WithCtxNamed(ctx, "audit", auditLog)
+//	zerowrap.FromCtxNamed(ctx, "audit").Info().Str(zerowrap.FieldAction, "login").Msg("audit")
+func WithCtxNamed(ctx context.Context, name string, log Logger) context.Context {
+	return context.WithValue(ctx, namedKey{name}, &log.Logger)
+}
+
+// FromCtxNamed returns the logger attached under name, with the fields of
+// registered extractors. If no logger is found, returns a disabled logger.
+func FromCtxNamed(ctx context.Context, name string) Logger {
+	l, ok := ctx.Value(namedKey{name}).(*zerolog.Logger)
+	if !ok {
+		return Logger{zerolog.Nop()}
+	}
+	return withExtracted(ctx, Logger{*l})
+}
+
 // WithCtxZerolog attaches a zerolog.Logger to the context.
 // Use this when working directly with zerolog loggers.
 func WithCtxZerolog(ctx context.Context, log zerolog.Logger) context.Context {
@@ -0,0 +1,161 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultCrashEntries is the number of entries kept for crash reports.
+const DefaultCrashEntries = 200
+
+// CrashConfig configures a CrashRecorder.
+type CrashConfig struct {
+	// Entries is the number of most recent entries kept, at all levels.
+	// Defaults to DefaultCrashEntries if 0.
+	Entries int
+
+	// Dir is the directory crash reports are written to, as
+	// crash-<time>-<pid>.json files. No file is written if empty.
+	Dir string
+
+	// OnCrash is called with the crash report, after it is written to
+	// Dir, e.g. to upload it. It must not log through the crashing logger.
+	OnCrash func(CrashReport)
+}
+
+// CrashReport holds the context of a crash: its reason and stack, and
+// the entries logged before it, oldest first, ending with the fatal or
+// panic entry when the crash was logged.
+type CrashReport struct {
+	Time    time.Time
+	Reason  string
+	Stack   []byte
+	Entries [][]byte
+}
+
+// CrashRecorder is a zerolog.LevelWriter keeping the last entries of all
+// levels in memory, whatever the level of the other outputs, and dumping
+// them on a fatal or panic entry, or a panic recovered by Recover, so a
+// crash comes with the events that led to it. Only the first crash is
+// reported.
+type CrashRecorder struct {
+	cfg  CrashConfig
+	ring *RingSink
+	once sync.Once
+}
+
+// NewCrashRecorder creates a crash recorder. Add it to the outputs of a
+// logger at trace or debug level so it sees every entry, and defer
+// Recover at the top of main and of long-lived goroutines:
+//
+//	crash := zerowrap.NewCrashRecorder(zerowrap.CrashConfig{Dir: "/var/log/app"})
+//	log := zerowrap.New(zerowrap.Config{
+//	    Level:  "debug",
+//	    Format: "json",
+//	    Output: zerolog.MultiLevelWriter(
+//	        &zerolog.FilteredLevelWriter{
+//	            Writer: zerolog.LevelWriterAdapter{Writer: os.Stdout},
+//	            Level:  zerolog.InfoLevel,
+//	        },
+//	        crash,
+//	    ),
+//	})
+//	defer crash.Recover()
+func NewCrashRecorder(cfg CrashConfig) *CrashRecorder {
+	n := cfg.Entries
+	if n == 0 {
+		n = DefaultCrashEntries
+	}
+	return &CrashRecorder{cfg: cfg, ring: NewRingSink(n)}
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (c *CrashRecorder) Write(p []byte) (int, error) {
+	return c.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Fatal and panic entries are
+// reported before zerolog exits or panics.
+func (c *CrashRecorder) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	n, err := c.ring.WriteLevel(level, p)
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		reason := level.String()
+		if msg, ok := decodeFields(p)[zerolog.MessageFieldName].(string); ok && msg != "" {
+			reason = msg
+		}
+		c.Report(reason, debug.Stack())
+	}
+	return n, err
+}
+
+// Recover reports a panic of the calling goroutine, then panics again
+// with the same value. It must be called directly by defer.
+func (c *CrashRecorder) Recover() {
+	if v := recover(); v != nil {
+		c.Report(fmt.Sprint(v), debug.Stack())
+		panic(v)
+	}
+}
+
+// Report writes a crash report with the kept entries to Dir and passes
+// it to OnCrash. Only the first call reports.
+func (c *CrashRecorder) Report(reason string, stack []byte) {
+	c.once.Do(func() {
+		report := CrashReport{
+			Time:    time.Now(),
+			Reason:  reason,
+			Stack:   stack,
+			Entries: c.ring.Snapshot(zerolog.TraceLevel),
+		}
+		if c.cfg.Dir != "" {
+			if err := writeCrashReport(c.cfg.Dir, report); err != nil {
+				ReportError(fmt.Errorf("%w: crash report: %w", ErrWrite, err))
+			}
+		}
+		if c.cfg.OnCrash != nil {
+			c.cfg.OnCrash(report)
+		}
+	})
+}
+
+// MarshalJSON encodes the report with its stack as a string and its
+// entries as JSON values (entries that are not JSON as strings).
+func (r CrashReport) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(`{"time":`)
+	t, _ := r.Time.MarshalJSON()
+	buf.Write(t)
+	buf.WriteString(`,"reason":`)
+	reason, _ := json.Marshal(r.Reason)
+	buf.Write(reason)
+	buf.WriteString(`,"stack":`)
+	stack, _ := json.Marshal(string(r.Stack))
+	buf.Write(stack)
+	buf.WriteString(`,"entries":[`)
+	for i, e := range r.Entries {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONEntry(&buf, e)
+	}
+	buf.WriteString("]}")
+	return buf.Bytes(), nil
+}
+
+// writeCrashReport writes report to a new file in dir.
+func writeCrashReport(dir string, report CrashReport) error {
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("crash-%s-%d.json", report.Time.UTC().Format("20060102T150405"), os.Getpid())
+	return os.WriteFile(filepath.Join(dir, name), append(b, '\n'), 0o600)
+}
@@ -0,0 +1,52 @@
+package cronlog
+
+import (
+	"context"
+
+	"github.com/bnema/zerowrap"
+	"github.com/robfig/cron/v3"
+)
+
+// Logger adapts a zerowrap.Logger to cron.Logger, so the scheduler's own
+// events (schedule, wake, run) are logged through zerowrap.
+// Routine messages log at debug level, errors at error level.
+func Logger(log zerowrap.Logger) cron.Logger {
+	return cronLogger{log: log.WithField(zerowrap.FieldComponent, "cron")}
+}
+
+type cronLogger struct {
+	log zerowrap.Logger
+}
+
+// Info implements cron.Logger.
+func (l cronLogger) Info(msg string, keysAndValues ...any) {
+	l.log.Debug().Fields(keysAndValues).Msg(msg)
+}
+
+// Error implements cron.Logger.
+func (l cronLogger) Error(err error, msg string, keysAndValues ...any) {
+	l.log.Error().Err(err).Fields(keysAndValues).Msg(msg)
+}
+
+// Job returns a cron.Job that runs fn through zerowrap.Job, so every
+// scheduled run gets its own run_id, start/finish events and panic recovery.
+//
+//	c.AddJob("@hourly", cronlog.Job(ctx, "cleanup-sessions", store.DeleteExpired))
+func Job(ctx context.Context, name string, fn func(ctx context.Context) error) cron.Job {
+	return cron.FuncJob(func() {
+		_ = zerowrap.Job(ctx, name, fn)
+	})
+}
+
+// Wrapper returns a cron.JobWrapper that runs existing jobs through
+// zerowrap.Job under the given name.
+//
+//	c := cron.New(cron.WithChain(cronlog.Wrapper(ctx, "scheduled")))
+func Wrapper(ctx context.Context, name string) cron.JobWrapper {
+	return func(j cron.Job) cron.Job {
+		return Job(ctx, name, func(context.Context) error {
+			j.Run()
+			return nil
+		})
+	}
+}
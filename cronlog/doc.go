@@ -0,0 +1,22 @@
+// Package cronlog provides robfig/cron integration for zerowrap.
+//
+// This is an optional sub-package that adds the robfig/cron dependency.
+// It routes the scheduler's own logging through zerowrap and runs jobs
+// through zerowrap.Job, so each run gets job and run_id fields, start and
+// finish events, duration, panic recovery and error logging.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/cronlog"
+//	    "github.com/robfig/cron/v3"
+//	)
+//
+//	c := cron.New(cron.WithLogger(cronlog.Logger(log)))
+//	c.AddJob("@hourly", cronlog.Job(ctx, "cleanup-sessions", func(ctx context.Context) error {
+//	    zerowrap.FromCtx(ctx).Info().Msg("cleaning up") // includes job, run_id
+//	    return store.DeleteExpired(ctx)
+//	}))
+//	c.Start()
+package cronlog
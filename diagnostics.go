@@ -0,0 +1,253 @@
+package zerowrap
+
+import (
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// Diagnostic error kinds, matched with errors.Is on errors passed to the
+// error handler.
+var (
+	// ErrWrite wraps errors returned by writers, e.g. lumberjack failing
+	// to rotate or a sink rejecting an entry.
+	ErrWrite = errors.New("zerowrap: write failed")
+
+	// ErrWriteTimeout wraps writes abandoned because the sink did not
+	// return in time, e.g. by a TimeoutWriter or a NetWriter.
+	ErrWriteTimeout = errors.New("zerowrap: write timed out")
+
+	// ErrDropped wraps reports of entries dropped by a writer, e.g. when
+	// an asynchronous queue is full.
+	ErrDropped = errors.New("zerowrap: entry dropped")
+
+	// ErrHookPanic wraps panics recovered by SafeHook.
+	ErrHookPanic = errors.New("zerowrap: hook panicked")
+)
+
+// Diagnostics holds counters of the logging pipeline itself: events
+// written by loggers created with Config.Diagnostics, by level, and the
+// errors, timeouts, drops, redactions and suppressions reported to
+// zerowrap.
+type Diagnostics struct {
+	Events        map[string]int64 `json:"events"`
+	WriteErrors   int64            `json:"write_errors"`
+	WriteTimeouts int64            `json:"write_timeouts"`
+	Dropped       int64            `json:"dropped"`
+	HookPanics    int64            `json:"hook_panics"`
+	Other         int64            `json:"other"`
+	Redactions    int64            `json:"redactions"`
+	Suppressed    int64            `json:"suppressed"`
+}
+
+var (
+	errorHandler atomic.Pointer[func(error)]
+
+	diagWriteErrors   atomic.Int64
+	diagWriteTimeouts atomic.Int64
+	diagDropped       atomic.Int64
+	diagHookPanics    atomic.Int64
+	diagOther         atomic.Int64
+	diagRedactions    atomic.Int64
+	diagSuppressed    atomic.Int64
+
+	// diagEvents counts the events of loggers created with
+	// Config.Diagnostics.
+	diagEvents LevelCounts
+
+	enableDiagnostics sync.Once
+)
+
+// EnableDiagnostics routes the writer errors of every zerolog logger,
+// including loggers not created by zerowrap, through ReportError, so
+// they are counted and passed to the error handler instead of printed
+// by zerolog. It replaces zerolog.ErrorHandler, so it is not done
+// implicitly: SetErrorHandler and New with Config.Diagnostics call it.
+func EnableDiagnostics() {
+	enableDiagnostics.Do(func() {
+		zerolog.ErrorHandler = func(err error) {
+			ReportError(fmt.Errorf("%w: %w", ErrWrite, err))
+		}
+	})
+}
+
+// SetErrorHandler sets the function called when logging itself fails:
+// writer errors, timed-out writes, dropped entries, or panics recovered
+// from hooks. Use errors.Is with ErrWrite, ErrWriteTimeout, ErrDropped or
+// ErrHookPanic to tell them apart; timed-out writes returned to zerolog
+// match both ErrWrite and ErrWriteTimeout.
+// The handler must not log through the failing logger.
+//
+// Errors are printed to stderr if no handler is set (or fn is nil).
+// Setting a handler enables diagnostics (see EnableDiagnostics).
+//
+//	zerowrap.SetErrorHandler(func(err error) {
+//	    loggingErrors.Inc()
+//	    fmt.Fprintln(os.Stderr, err)
+//	})
+func SetErrorHandler(fn func(err error)) {
+	if fn == nil {
+		errorHandler.Store(nil)
+		return
+	}
+	EnableDiagnostics()
+	errorHandler.Store(&fn)
+}
+
+// ReportError counts err in the diagnostics counters and passes it to the
+// error handler. Custom writers and hooks can use it to surface failures
+// that would otherwise be ignored.
+func ReportError(err error) {
+	if err == nil {
+		return
+	}
+	switch {
+	case errors.Is(err, ErrWriteTimeout):
+		diagWriteTimeouts.Add(1)
+	case errors.Is(err, ErrWrite):
+		diagWriteErrors.Add(1)
+	case errors.Is(err, ErrDropped):
+		diagDropped.Add(1)
+	case errors.Is(err, ErrHookPanic):
+		diagHookPanics.Add(1)
+	default:
+		diagOther.Add(1)
+	}
+
+	if fn := errorHandler.Load(); fn != nil {
+		(*fn)(err)
+		return
+	}
+	fmt.Fprintln(os.Stderr, err)
+}
+
+// ReportRedacted counts n values redacted before logging. Custom
+// redaction code can use it so redactions show in DiagnosticStats.
+func ReportRedacted(n int) {
+	diagRedactions.Add(int64(n))
+}
+
+// ReportSuppressed counts n entries deliberately not logged, e.g. by
+// sampling or rate limiting, so they show in DiagnosticStats.
+func ReportSuppressed(n int) {
+	diagSuppressed.Add(int64(n))
+}
+
+// DiagnosticStats returns the diagnostics counters since process start.
+func DiagnosticStats() Diagnostics {
+	return Diagnostics{
+		Events:        diagEvents.Map(),
+		WriteErrors:   diagWriteErrors.Load(),
+		WriteTimeouts: diagWriteTimeouts.Load(),
+		Dropped:       diagDropped.Load(),
+		HookPanics:    diagHookPanics.Load(),
+		Other:         diagOther.Load(),
+		Redactions:    diagRedactions.Load(),
+		Suppressed:    diagSuppressed.Load(),
+	}
+}
+
+// DiagnosticsHandler returns an http.Handler serving DiagnosticStats as
+// JSON, for a debug endpoint when expvar is not used.
+//
+//	mux.Handle("GET /debug/logging", zerowrap.DiagnosticsHandler())
+func DiagnosticsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(DiagnosticStats())
+	})
+}
+
+// LevelCounts counts events by level. It is a zerolog.Hook, so it can be
+// attached to any logger with WithHook, or set as Config.Counts. The zero
+// value is ready to use and safe for concurrent use.
+//
+//	var counts zerowrap.LevelCounts
+//	log := zerowrap.New(zerowrap.Config{Discard: true, Counts: &counts})
+//	svc.Run(zerowrap.WithCtx(ctx, log))
+//	if counts.Count(zerolog.ErrorLevel) > 0 { ... }
+type LevelCounts struct {
+	// counts is indexed by level+1: trace is -1, and NoLevel comes after
+	// panic.
+	counts [zerolog.NoLevel + 2]atomic.Int64
+}
+
+// Run implements zerolog.Hook. Hooks only run for enabled events, so
+// events below the logger level are not counted.
+func (c *LevelCounts) Run(_ *zerolog.Event, level zerolog.Level, _ string) {
+	if i := int(level) + 1; i >= 0 && i < len(c.counts) {
+		c.counts[i].Add(1)
+	}
+}
+
+// Count returns the number of events counted at level.
+func (c *LevelCounts) Count(level zerolog.Level) int64 {
+	if i := int(level) + 1; i >= 0 && i < len(c.counts) {
+		return c.counts[i].Load()
+	}
+	return 0
+}
+
+// Total returns the number of events counted at any level.
+func (c *LevelCounts) Total() int64 {
+	var n int64
+	for i := range c.counts {
+		n += c.counts[i].Load()
+	}
+	return n
+}
+
+// Map returns the counts keyed by level name, with "none" for events
+// logged without a level.
+func (c *LevelCounts) Map() map[string]int64 {
+	m := make(map[string]int64, len(c.counts))
+	for i := range c.counts {
+		level := zerolog.Level(i - 1)
+		name := level.String()
+		if level == zerolog.NoLevel {
+			name = "none"
+		}
+		m[name] = c.counts[i].Load()
+	}
+	return m
+}
+
+// Reset sets all counts to zero.
+func (c *LevelCounts) Reset() {
+	for i := range c.counts {
+		c.counts[i].Store(0)
+	}
+}
+
+// PublishExpvar publishes the diagnostics counters as an expvar variable,
+// served on /debug/vars by the expvar handler. Panics if name is already
+// published, like expvar.Publish.
+//
+//	zerowrap.PublishExpvar("zerowrap")
+func PublishExpvar(name string) {
+	expvar.Publish(name, expvar.Func(func() any {
+		return DiagnosticStats()
+	}))
+}
+
+// SafeHook returns a hook that recovers panics from hook and reports
+// them with ErrHookPanic, so a faulty hook cannot crash the application.
+//
+//	log = zerowrap.WithHook(log, zerowrap.SafeHook(metricsHook))
+func SafeHook(hook zerolog.Hook) zerolog.Hook {
+	return HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+		defer func() {
+			if r := recover(); r != nil {
+				ReportError(fmt.Errorf("%w: %v", ErrHookPanic, r))
+			}
+		}()
+		hook.Run(e, level, msg)
+	})
+}
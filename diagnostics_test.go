@@ -0,0 +1,56 @@
+package zerowrap
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// failingWriter fails every write.
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, errors.New("disk full") }
+
+func TestNewLeavesZerologErrorHandler(t *testing.T) {
+	if zerolog.ErrorHandler != nil {
+		t.Skip("diagnostics already enabled by another test")
+	}
+	before := diagEvents.Total()
+	log := New(Config{Format: "json", Output: io.Discard})
+	log.Info().Msg("hello")
+
+	if zerolog.ErrorHandler != nil {
+		t.Error("New replaced zerolog.ErrorHandler without Config.Diagnostics")
+	}
+	if got := diagEvents.Total(); got != before {
+		t.Errorf("events of a logger without Config.Diagnostics were counted: %d, want %d", got, before)
+	}
+}
+
+func TestConfigDiagnostics(t *testing.T) {
+	prev := zerolog.ErrorHandler
+	t.Cleanup(func() {
+		zerolog.ErrorHandler = prev
+		SetErrorHandler(nil)
+	})
+
+	var reported []error
+	SetErrorHandler(func(err error) { reported = append(reported, err) })
+
+	before := DiagnosticStats()
+	log := New(Config{Format: "json", Output: failingWriter{}, Diagnostics: true})
+	log.Warn().Msg("hello")
+
+	stats := DiagnosticStats()
+	if got := stats.Events["warn"] - before.Events["warn"]; got != 1 {
+		t.Errorf("warn events counted = %d, want 1", got)
+	}
+	if got := stats.WriteErrors - before.WriteErrors; got != 1 {
+		t.Errorf("write errors counted = %d, want 1", got)
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrWrite) {
+		t.Errorf("reported errors = %v, want one ErrWrite", reported)
+	}
+}
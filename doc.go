@@ -48,6 +48,16 @@
 //	WithCtx(ctx, log) context.Context     // Attach logger to context
 //	WithCtxZerolog(ctx, log) context.Context  // Attach zerolog.Logger to context
 //
+// UpdateCtx adds fields to the stored logger in place, like zerolog's
+// UpdateContext, so fields added deep in the stack reach middleware that
+// logs from a parent context. It is not safe while other goroutines log
+// with the same logger.
+//
+// Named loggers, e.g. a separate audit logger, live next to the default one:
+//
+//	WithCtxNamed(ctx, "audit", log) context.Context
+//	FromCtxNamed(ctx, "audit") Logger
+//
 // # Field Helpers
 //
 // Get logger with additional fields:
@@ -62,6 +72,32 @@
 //	CtxWithFields(ctx, fields) context.Context
 //	CtxWithStruct(ctx, s) context.Context
 //
+// Build field maps with typed setters:
+//
+//	fields := zerowrap.Fields().Str("user_id", id).Int("count", n).Err(err).Map()
+//
+// Or skip the map entirely with typed fields, for performance-sensitive code:
+//
+//	FromCtxWith(ctx, zerowrap.Str("user_id", id), zerowrap.Int("count", n)) Logger
+//	CtxWith(ctx, fields...) context.Context
+//	log.WithTyped(fields...) Logger
+//	log.WithFieldsOrdered([]Field) Logger
+//
+// Values of type map[string]any and []any are logged as nested objects
+// and arrays, with their values added like top-level fields; Dict and
+// Array build the equivalent typed fields.
+//
+// IP addresses and URLs are logged as strings, URLs with the password
+// masked and DefaultRedactQueryKeys query values redacted. [16]byte values,
+// including named types such as uuid.UUID, are logged as UUID strings.
+//
+// Embed pre-serialized JSON (webhook bodies, API responses) as structured
+// JSON with log.WithRawJSON(key, raw), RawJSON(key, raw), or a
+// json.RawMessage value in any field map. Invalid JSON is logged as a string.
+//
+// Fields from maps are added in key order, so output is stable across
+// calls; typed fields keep the order they are given in.
+//
 // # Struct Tags
 //
 // Extract fields from structs using the `log` tag (falls back to `json`, then field name):
@@ -75,6 +111,17 @@
 //
 //	log := zerowrap.FromCtxWithStruct(ctx, Request{UserID: 123, RequestID: "abc"})
 //
+// Tag a nested or embedded struct with `log:",inline"` to merge its fields
+// at the top level, or `log:"prefix=db_"` to merge them prefixed. Other
+// values with these tags are logged under their own name.
+//
+// Types implementing FieldsProvider return their own fields from
+// LogFields, used instead of reflection.
+//
+// Nested structs, slices and maps are walked within the depth, field and
+// length limits set with SetStructLimits; cyclic references are logged as
+// "<cycle>".
+//
 // # Logger Creation
 //
 // Create loggers with configuration:
@@ -84,30 +131,78 @@
 //	NewWithFile(cfg, fileCfg) (Logger, func(), error)  // Create with file output
 //	Default() Logger                              // Default logger (info, console)
 //	WithHook(log, hook) Logger                    // Add hook to logger
+//	WithHooks(log, hooks...) Logger               // Add several hooks to logger
+//
+// # Hooks
+//
+// Compose and gate hooks:
+//
+//	log = zerowrap.WithHooks(log,
+//	    otelHook,
+//	    zerowrap.HookMinLevel(zerolog.WarnLevel, alertHook),
+//	    zerowrap.HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+//	        e.Str("region", region)
+//	    }),
+//	)
 //
 // # Config
 //
 // Configuration for logger creation:
 //
 //	type Config struct {
-//	    Level      string     // trace, debug, info, warn, error, fatal, panic
-//	    Format     string     // json or console
-//	    TimeFormat string     // time format (default: time.RFC3339)
-//	    Output     io.Writer  // output writer (default: os.Stderr)
-//	    Caller     bool       // include caller info (file:line)
+//	    Level       string        // trace, debug, info, warn, error, fatal, panic
+//	    Format      string        // json, console, pretty (fields below message), json-pretty (highlighted JSON), gcp, ltsv, cbor or discard
+//	    TimeFormat  string        // time format (default: time.RFC3339)
+//	    Output      io.Writer     // output writer (default: os.Stderr)
+//	    Caller      bool          // include caller info (file:line)
+//	    Console     ConsoleConfig // console colors, part order, hidden fields, formatters
+//	    GCPProject  string        // trace project for gcp format (default: $GOOGLE_CLOUD_PROJECT)
+//	    Discard     bool          // drop output, still building and counting events
+//	    Counts      *LevelCounts  // count the logger's events by level
+//	    Diagnostics bool          // count events in DiagnosticStats, report writer errors
+//
+//	    IncludeHost      bool // host field on every entry
+//	    IncludePID       bool // pid field on every entry
+//	    IncludeGoVersion bool // go_version field on every entry
+//	    IncludeBuildInfo bool // module_version, vcs_revision, vcs_time, vcs_modified
+//	    IncludeK8s       bool // k8s_pod, k8s_namespace, k8s_node, k8s_container in a pod
+//	    GoroutineID      bool // goroutine_id field on every event (debugging only)
+//
+//	    MaxFieldBytes   int  // cut longer field values, marking _truncated (default: no limit)
+//	    MaxMessageBytes int  // cut longer messages, marking _truncated (default: no limit)
+//	    MaxEventBytes   int  // summarize larger entries (default: no limit)
+//	    DropOversized   bool // drop entries over MaxEventBytes instead
 //	}
 //
+// # Custom Formats
+//
+// Implement Encoder to render decoded entries in another format, and
+// select it with Config.Format after registering it, or on one sink:
+//
+//	zerowrap.RegisterFormat("kv", zerowrap.EncoderFunc(func(dst []byte, e *zerowrap.Entry) ([]byte, error) { ... }))
+//	log := zerowrap.New(zerowrap.Config{Format: "kv"})
+//	w := zerowrap.NewEncoderWriter(sink, enc)
+//
+// # SIEM (CEF / LEEF)
+//
+// The siemlog sub-package provides CEF and LEEF encoders for SIEMs:
+//
+//	zerowrap.RegisterFormat("cef", siemlog.CEF{Vendor: "Acme", Product: "Shop", Version: "1.4"})
+//	siem := zerowrap.NewEncoderWriter(conn, siemlog.LEEF{Vendor: "Acme", Product: "Shop"})
+//
 // # FileConfig
 //
 // Configuration for file-based logging with rotation:
 //
 //	type FileConfig struct {
-//	    Enabled    bool    // toggle file logging
-//	    Path       string  // log file path
-//	    MaxSize    int     // max size in MB before rotation (default: 100)
-//	    MaxBackups int     // max old files to retain (default: 3)
-//	    MaxAge     int     // max days to retain (default: 28)
-//	    Compress   bool    // compress rotated files
+//	    Enabled       bool          // toggle file logging
+//	    Path          string        // log file path
+//	    MaxSize       int           // max size in MB before rotation (default: 100)
+//	    MaxBackups    int           // max old files to retain (default: 3)
+//	    MaxAge        int           // max days to retain (default: 28)
+//	    Compress      bool          // compress rotated files
+//	    BufferSize    int           // buffer file writes, in bytes (default: 0, unbuffered)
+//	    FlushInterval time.Duration // max time entries stay buffered (default: 1s)
 //	}
 //
 // # Error Helpers
@@ -135,25 +230,66 @@
 //	    }
 //	}
 //
+// With SetErrorCallers(true), each wrap records its call site and logs
+// the chain of sites in error_trace; ErrorCallers returns it from an error.
+//
+// ErrRateLimited logs a repeated error at most once per interval, with
+// the count suppressed in between, for retry loops and health checks:
+//
+//	log.ErrRateLimited(err, "health check failed", time.Minute)
+//
+// # Conditional Logging
+//
+// Once, Every, EveryDuration and OnChange return the logger only when
+// their condition is met for a key, and a disabled logger otherwise:
+//
+//	log.Every("import-progress", 1000).Info().Int("done", i).Msg("importing")
+//	log.EveryDuration("queue-full", 10*time.Second).Warn().Msg("queue full")
+//
 // # Field Constants
 //
 // Common field names for consistency:
 //
 //	// Identity & Tracing
 //	FieldComponent, FieldRequestID, FieldTraceID, FieldSpanID
-//	FieldCorrelationID, FieldSessionID, FieldUserID
+//	FieldCorrelationID, FieldSessionID, FieldUserID, FieldEmail, FieldTenantID
 //
 //	// HTTP/API
 //	FieldMethod, FieldPath, FieldStatus, FieldClientIP
+//	FieldRoute, FieldQueryStr, FieldHeaders, FieldRequestBody, FieldResponseBody
+//	FieldUABrowser, FieldUAOS, FieldUADevice
+//
+//	// Geo
+//	FieldGeoCountry, FieldGeoCity, FieldASN, FieldASOrg
 //
 //	// Service/Infra
 //	FieldService, FieldVersion, FieldHost, FieldEnv
+//	FieldPID, FieldGoVersion, FieldGoroutineID
+//	FieldModuleVersion, FieldVCSRevision, FieldVCSTime, FieldVCSModified
+//	FieldK8sPod, FieldK8sNamespace, FieldK8sNode, FieldK8sContainer
 //
 //	// Operations
-//	FieldAction, FieldOperation, FieldError, FieldDuration
+//	FieldAction, FieldOperation, FieldError, FieldDuration, FieldRetries, FieldStack
+//	FieldDurationText, FieldDurationNs, FieldErrorTrace, FieldSuppressed
+//	FieldDeadlineIn, FieldAttempt, FieldBackoff, FieldChanges
+//
+//	// Size limits
+//	FieldTruncated, FieldDroppedFields, FieldEventBytes
+//
+//	// Tamper evidence
+//	FieldSignature
+//
+//	// Lifecycle
+//	FieldSignal, FieldUptime
+//
+//	// Circuit breakers
+//	FieldBreakerFrom, FieldBreakerState, FieldRequests, FieldFailures
+//
+//	// Jobs
+//	FieldJob, FieldRunID, FieldWorker, FieldWorkerIndex
 //
 //	// Data
-//	FieldCount, FieldSize
+//	FieldCount, FieldSize, FieldSizeText
 //
 //	// Clean Architecture - Layers
 //	FieldLayer, FieldUseCase
@@ -162,7 +298,7 @@
 //	FieldAdapter, FieldAdapterType, FieldHandler, FieldRepository, FieldGateway
 //
 //	// Database/Storage
-//	FieldTable, FieldQuery, FieldDatabase
+//	FieldTable, FieldQuery, FieldQueryHash, FieldDatabase, FieldTxID
 //
 //	// Messaging/Events
 //	FieldEvent, FieldTopic, FieldQueue, FieldPayload
@@ -178,6 +314,28 @@
 //	    zerowrap.FieldUserID:    userID,
 //	})
 //
+// # Process Fields
+//
+// IncludeHost, IncludePID and IncludeGoVersion add host, pid and
+// go_version to every entry; IncludeBuildInfo adds the module version and
+// VCS revision of the binary; GoroutineID adds the logging goroutine's ID
+// to each event.
+//
+// In Kubernetes, IncludeK8s (or WithK8sFields on an existing logger) adds
+// the pod, namespace, node and container read from downward-API
+// environment variables (POD_NAME, POD_NAMESPACE, NODE_NAME,
+// CONTAINER_NAME).
+//
+// # Durations and Sizes
+//
+// SetDurationFormat and SetSizeFormat choose how durations (duration_ms,
+// duration or duration_ns) and sizes (size_bytes or human-readable size)
+// are rendered by the built-in request, job and task logs. AddDuration
+// and AddSize apply the same rules to your own events:
+//
+//	zerowrap.SetDurationFormat(zerowrap.DurationString)
+//	zerowrap.AddDuration(log.Info(), time.Since(start)).Msg("done")
+//
 // # Environment Variables
 //
 // Create logger from environment variables:
@@ -205,6 +363,36 @@
 //	}
 //	defer cleanup()
 //
+// With BufferSize set, file writes are batched and flushed every
+// FlushInterval, immediately for warn and above, and by cleanup. Entries
+// below warn still buffered on a crash are lost.
+// WriteTimeout bounds each file write, for files on network storage.
+//
+// # Tamper Evidence
+//
+// Sign each JSON entry with an HMAC, chained to the previous entry, and
+// verify the file later; the first bad line is a *TamperError:
+//
+//	signed := zerowrap.NewSignedWriter(auditFile, zerowrap.SignConfig{Key: key, Chain: true})
+//	last, err := zerowrap.VerifySigned(f, zerowrap.SignConfig{Key: key, Chain: true})
+//
+// FileConfig.Sign does the same for NewWithFile, resuming the chain from
+// the last entry of the file.
+//
+// # PII Masking
+//
+// Mask emails, phone numbers, IBANs and national identifiers (country
+// packs) found in string values, counted by kind:
+//
+//	masker := zerowrap.NewPIIMasker(os.Stdout, zerowrap.PIIConfig{Countries: []string{"fr"}})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: masker})
+//
+// NewHashingWriter replaces identifier fields (user_id, email, client_ip
+// by default) with salted hashes, so entries stay correlatable per user:
+//
+//	out := zerowrap.NewHashingWriter(os.Stdout, zerowrap.HashConfig{Salt: salt})
+//	out.Hash("u-1842") // the value logged in place of the ID
+//
 // # OpenTelemetry Integration
 //
 // For OpenTelemetry log bridging, use the optional otel sub-package:
@@ -217,6 +405,353 @@
 //	// Using custom provider
 //	log := zerowrap.New(cfg).Hook(otel.NewHookWithProvider(provider, "my-service"))
 //
+// For error reporting to Sentry, use the optional sentry sub-package:
+//
+//	log := zerowrap.New(cfg).Hook(sentry.NewHook(sentry.Options{}))
+//
+// To index logs in Elasticsearch or OpenSearch, use the optional eslog
+// sub-package as an output:
+//
+//	es, err := eslog.NewWriter(eslog.Config{URL: esURL, Index: "app-logs"})
+//
+// Its NewSearchTransport logs the search requests of Elasticsearch and
+// OpenSearch clients with took_ms, hits and shard failures.
+//
+// To publish logs to a Kafka topic, use the optional kafkalog sub-package
+// with a Producer adapter for your Kafka client:
+//
+//	kw, err := kafkalog.NewWriter(kafkalog.Config{Producer: producer, Topic: "app-logs"})
+//
+// # Network Output
+//
+// Ship entries to a TCP, UDP or Unix socket endpoint with reconnection
+// and an optional on-disk spool for entries that do not fit in the queue
+// while it is down or slow:
+//
+//	agent, err := zerowrap.NewNetWriter(zerowrap.NetConfig{
+//	    Network:   "tcp",
+//	    Addr:      "127.0.0.1:9000",
+//	    SpoolPath: "/var/spool/myapp/logs.spool",
+//	})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: agent})
+//
+// Writes to the endpoint are bounded by NetConfig.WriteTimeout. Wrap other
+// blocking sinks with NewTimeoutWriter, so a hung write returns an error
+// wrapping ErrWriteTimeout and the entry goes through the fallback or drop
+// policy of the surrounding writer:
+//
+//	out, err := zerowrap.NewFailoverWriter(zerowrap.FailoverConfig{
+//	    Primary: zerowrap.NewTimeoutWriter(nfsFile, 2*time.Second),
+//	})
+//
+// # Diagnostics
+//
+// Writer errors (including those of lumberjack and custom writers),
+// dropped entries and hook panics recovered by SafeHook are passed to the
+// error handler and counted. zerolog's error handler is replaced only by
+// SetErrorHandler, EnableDiagnostics or a logger with Config.Diagnostics:
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Diagnostics: true})
+//	zerowrap.SetErrorHandler(func(err error) { ... })
+//	zerowrap.PublishExpvar("zerowrap")
+//	stats := zerowrap.DiagnosticStats()
+//
+// The counters also include events written by level by loggers with
+// Diagnostics set, redactions and suppressed entries; DiagnosticsHandler serves them as JSON:
+//
+//	mux.Handle("GET /debug/logging", zerowrap.DiagnosticsHandler())
+//
+// # Recent Logs
+//
+// Keep the last entries in memory and serve them as JSON or server-sent
+// events on a debug page:
+//
+//	ring := zerowrap.NewRingSink(500)
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: zerolog.MultiLevelWriter(os.Stdout, ring)})
+//	mux.Handle("GET /debug/logs", ring.Handler()) // ?level=warn&limit=100
+//	entries := ring.Snapshot(zerolog.WarnLevel)
+//
+// # Crash Reports
+//
+// Keep the last entries of all levels and dump them with the stack on a
+// fatal or panic entry, or a recovered panic, to a file or callback:
+//
+//	crash := zerowrap.NewCrashRecorder(zerowrap.CrashConfig{Dir: "/var/log/app"})
+//	log := zerowrap.New(zerowrap.Config{Level: "debug", Format: "json", Output: zerolog.MultiLevelWriter(out, crash)})
+//	defer crash.Recover()
+//
+// # Routing
+//
+// Send entries to destinations selected by level, component or field;
+// entries matching no route go to the default writer:
+//
+//	router := zerowrap.NewRouter().
+//	    Route(zerowrap.Matcher{MinLevel: "error"}, alerts).
+//	    Route(zerowrap.Matcher{Field: "audit"}, auditFile).
+//	    Default(os.Stdout)
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: router})
+//
+// # Multiple Sinks
+//
+// Write to several sinks with isolated failures, reported per sink:
+//
+//	w := zerowrap.NewMultiWriter().Sink("stdout", os.Stdout).Sink("file", logFile)
+//	stats := w.Stats() // writes, errors and last error per sink
+//
+// # Failover
+//
+// Wrap a sink to fail over to a fallback writer (stderr by default) after
+// repeated write errors; the primary is retried periodically and used
+// again once it recovers:
+//
+//	out, err := zerowrap.NewFailoverWriter(zerowrap.FailoverConfig{Primary: gelf})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: out})
+//
+// # Fluentd
+//
+// Send entries to fluentd or fluent-bit with the forward protocol,
+// optionally waiting for delivery acknowledgements:
+//
+//	fluent, err := zerowrap.NewFluentWriter(zerowrap.FluentConfig{
+//	    Addr:       "fluentd.internal:24224",
+//	    Tag:        "app.billing",
+//	    RequireAck: true,
+//	})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: fluent})
+//
+// # Syslog
+//
+// Send entries to a local or remote syslog daemon with RFC 5424 framing:
+//
+//	syslog, err := zerowrap.NewSyslogWriter(zerowrap.SyslogConfig{
+//	    Network:  "udp",
+//	    Addr:     "logs.internal:514",
+//	    Facility: "local0",
+//	})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: syslog})
+//
+// # Graylog (GELF)
+//
+// Send entries to a Graylog GELF input over UDP or TCP:
+//
+//	gelf, err := zerowrap.NewGELFWriter(zerowrap.GELFConfig{Addr: "graylog.internal:12201"})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: gelf})
+//
+// # journald
+//
+// Send entries to the systemd journal with real structured fields:
+//
+//	journal, err := zerowrap.NewJournaldWriter(zerowrap.JournaldConfig{Identifier: "billing"})
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: journal})
+//
+// # Webhook Notifications
+//
+// Post error entries to Slack, Discord or a JSON webhook, batched and rate-limited:
+//
+//	notify, err := zerowrap.NewWebhookWriter(zerowrap.WebhookConfig{
+//	    URL:    webhookURL,
+//	    Format: zerowrap.WebhookSlack,
+//	})
+//	defer notify.Close()
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: zerolog.MultiLevelWriter(os.Stderr, notify),
+//	})
+//
+// # HTTP Request Logging
+//
+// The optional httplog, chilog, echolog and fiberlog sub-packages provide
+// request logging middleware that enriches the context logger per request:
+//
+//	handler := httplog.Middleware(httplog.Options{})(mux)   // net/http
+//	r.Use(chilog.Middleware(httplog.Options{}))              // chi
+//	e.Use(echolog.Middleware(httplog.Options{}))             // echo
+//	app.Use(fiberlog.Middleware(log, httplog.Options{}))     // fiber
+//
+// With Options.GeoIP, the client IP is resolved to country and ASN
+// fields through a GeoResolver backed by the application's MaxMind
+// reader; CtxWithGeo does the same outside HTTP. Options.UserAgent adds
+// the browser, OS and device class parsed from the User-Agent header, and
+// Options.Operations the OpenAPI operationId of the request.
+//
+// # gRPC
+//
+// The optional grpclog sub-package provides server interceptors that
+// enrich the call context logger and log completion with the status code,
+// and optionally every stream message:
+//
+//	grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(grpclog.Options{})),
+//	    grpc.ChainStreamInterceptor(grpclog.StreamServerInterceptor(grpclog.Options{})),
+//	)
+//
+// The connectlog and gatewaylog sub-packages do the same for connect-go
+// handlers and grpc-gateway muxes.
+//
+// # GraphQL
+//
+// The optional gqllog sub-package is a gqlgen extension that adds the
+// operation fields to the request context logger and logs each operation:
+//
+//	srv.Use(gqllog.NewExtension(gqllog.Options{}))
+//
+// # WebSocket Connections
+//
+// The optional wslog sub-package gives each WebSocket connection a context
+// logger and logs connect and disconnect with bytes, messages and close
+// codes:
+//
+//	ctx, conn := wslog.Open(r.Context(), wslog.Info{RemoteAddr: r.RemoteAddr})
+//	defer conn.Close(websocket.CloseNormalClosure, "", nil)
+//
+// # SQL Queries
+//
+// The optional sqllog sub-package wraps a database/sql driver and logs
+// every statement, normalized with NormalizeQuery and grouped by
+// query_hash:
+//
+//	db, err := sqllog.Open("pgx", dsn, sqllog.Options{Database: "orders"})
+//
+// Options.SlowThreshold, MaxQueries and MaxRepeats flag slow statements
+// and requests issuing too many or repeated (N+1) queries, and
+// Options.BeginTx logs transactions under a tx_id. AddQuery logs the same
+// fields for other database clients.
+//
+// # MongoDB
+//
+// The optional mongolog sub-package implements the mongo-driver command
+// and pool monitors, logging redacted commands with the logger of their
+// context:
+//
+//	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri).
+//	    SetMonitor(mongolog.CommandMonitor(mongolog.Options{})))
+//
+// # Outbound HTTP Logging
+//
+// Log outgoing calls with the logger from each request's context:
+//
+//	client := &http.Client{
+//	    Transport: zerowrap.NewLoggingTransport(nil, zerowrap.TransportConfig{}),
+//	}
+//
+// # Correlation IDs
+//
+// ExtractHTTP and InjectHTTP carry request and correlation IDs across
+// services in the X-Request-ID and X-Correlation-ID headers; Extract and
+// Inject do the same through accessor functions, e.g. for gRPC metadata:
+//
+//	ctx := zerowrap.ExtractHTTP(r)
+//	zerowrap.InjectHTTP(outReq, ctx)
+//
+// # W3C Trace Context
+//
+// CtxWithTraceParent parses the traceparent header and adds trace_id and
+// span_id to the context logger, for services without a tracing SDK:
+//
+//	ctx := zerowrap.CtxWithTraceParent(r.Context(), r.Header.Get)
+//
+// # AWS SDK
+//
+// The optional awslog sub-package adds a smithy middleware to AWS SDK v2
+// clients that logs each API call with its service, operation, request
+// ID, attempts, duration and error code:
+//
+//	cfg.APIOptions = append(cfg.APIOptions, awslog.AddMiddleware(awslog.Options{}))
+//
+// # Background Jobs
+//
+// Run a job with a per-run context logger, start/finish events and panic recovery:
+//
+//	err := zerowrap.Job(ctx, "cleanup-sessions", func(ctx context.Context) error {
+//	    return store.DeleteExpired(ctx)
+//	})
+//
+// # Slow Operations
+//
+// WarnIfSlow logs a warn entry when an operation outlives a threshold or
+// nears its ctx deadline, while it is still running:
+//
+//	defer zerowrap.WarnIfSlow(ctx, 500*time.Millisecond, "load-profile")()
+//
+// # Retries
+//
+// Retry runs a function with retries and logs each failed attempt at
+// debug, with its number, backoff and error, and the outcome at info or
+// error. LogRetries does the same for existing retry loops:
+//
+//	err := zerowrap.Retry(ctx, "publish-event", 5, backoff, publish)
+//
+//	r := zerowrap.LogRetries(ctx, "fetch-invoice")
+//	r.Failed(err, delay) // per failed attempt
+//	r.Done(err)          // final outcome
+//
+// # Process Lifecycle
+//
+// LogLifecycle logs the process start with build info and the SIGINT or
+// SIGTERM that ends it, and Shutdown closes subsystems in order, logging
+// each result:
+//
+//	ctx, stop := zerowrap.LogLifecycle(ctx)
+//	defer stop()
+//	<-ctx.Done()
+//	err := zerowrap.Shutdown(ctx, 10*time.Second,
+//	    zerowrap.ShutdownStep{Name: "http", Close: srv.Shutdown},
+//	)
+//
+// # Config Changes
+//
+// LogConfigChange diffs two config structs, as extracted for WithStruct,
+// and logs the changed keys with their old and new values, secrets
+// redacted, to audit config reloads:
+//
+//	zerowrap.LogConfigChange(ctx, current, next)
+//
+// # Circuit Breakers
+//
+// LogBreakerTransition logs circuit breaker state changes with the
+// breaker name, counts and last error; the optional breakerlog
+// sub-package wires it into sony/gobreaker:
+//
+//	cb := gobreaker.NewCircuitBreaker[[]byte](breakerlog.Settings(ctx, gobreaker.Settings{Name: "payments-api"}))
+//
+// # Testing
+//
+// The logtest sub-package routes logs through t.Log so they only show
+// for failing tests:
+//
+//	log := logtest.NewTestLogger(t, "debug")
+//	log := logtest.NewTestLoggerWithConfig(t, logtest.TestConfig{FailOnError: true})
+//
+// Drop the output and count events by level, for tests that only check
+// that something was logged or for benchmarks:
+//
+//	var counts zerowrap.LevelCounts
+//	log := zerowrap.New(zerowrap.Config{Discard: true, Counts: &counts})
+//	n := counts.Count(zerolog.ErrorLevel)
+//
+// # Context Extractors
+//
+// Register extractors to add values stored in the context by other
+// middleware to every logger returned by FromCtx:
+//
+//	zerowrap.RegisterCtxExtractor(func(ctx context.Context) map[string]any {
+//	    if id := middleware.GetReqID(ctx); id != "" {
+//	        return map[string]any{zerowrap.FieldRequestID: id}
+//	    }
+//	    return nil
+//	})
+//
+// # Multi-Tenancy
+//
+// CtxWithTenant adds tenant_id to the context logger and applies the
+// tenant's level from the policy set with SetTenantPolicy; the policy's
+// Writer also copies a tenant's entries to a dedicated output:
+//
+//	zerowrap.SetTenantPolicy(&zerowrap.TenantPolicy{
+//	    Levels: map[string]string{"acme": "debug"},
+//	})
+//	ctx = zerowrap.CtxWithTenant(ctx, tenantID)
+//
 // # Field Propagation Pattern
 //
 // The key pattern is to enrich the context with fields EARLY (at request entry points),
@@ -0,0 +1,27 @@
+// Package echolog provides echo request logging for zerowrap.
+//
+// This is an optional sub-package that adds the echo dependency.
+// It shares its options with httplog, so redaction and slow-request
+// settings behave the same across routers.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/echolog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	)
+//
+//	e := echo.New()
+//	e.Use(echolog.Middleware(httplog.Options{
+//	    SlowThreshold: 500 * time.Millisecond,
+//	}))
+//	e.GET("/users/:id", getUser)
+//
+//	// Handlers read the enriched logger from the request context
+//	func getUser(c echo.Context) error {
+//	    log := zerowrap.FromCtx(c.Request().Context())
+//	    log.Info().Msg("fetching user") // includes request_id, method, path
+//	    return c.JSON(http.StatusOK, user)
+//	}
+package echolog
@@ -0,0 +1,84 @@
+package echolog
+
+import (
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware returns echo request logging middleware.
+// It attaches request fields (request_id, method, path, client_ip) to the
+// request context logger and logs completion with the echo route template
+// (e.g. "/users/:id"), status, size and duration.
+// A RoutePattern set in opts takes precedence over the echo route.
+// Handler errors are passed to echo's HTTPErrorHandler once, by the
+// middleware, and not returned to outer middleware.
+//
+//	e := echo.New()
+//	e.Use(echolog.Middleware(httplog.Options{}))
+func Middleware(opts httplog.Options) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			req := c.Request()
+
+			ip := clientIP(c, opts)
+			ctx := opts.WithIDs(req.Context(), req.Header.Get)
+			ctx = zerowrap.CtxWithFields(ctx, map[string]any{
+				zerowrap.FieldMethod:   req.Method,
+				zerowrap.FieldPath:     req.URL.Path,
+				zerowrap.FieldClientIP: ip,
+			})
+			ctx = opts.WithGeo(ctx, ip)
+			ctx = opts.WithUserAgent(ctx, req.Header.Get)
+			ctx, _ = opts.WithOperation(ctx, req)
+			ctx = opts.WithDebug(ctx, req.Header.Get)
+			req = req.WithContext(ctx)
+			body, truncated := opts.CaptureBody(req)
+			c.SetRequest(req)
+
+			err := next(c)
+			if err != nil {
+				// Commit the response so the logged status is the final one.
+				// The error is handled here, so nil is returned and echo
+				// does not run its error handler a second time.
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if opts.RoutePattern != nil {
+				route = opts.RoutePattern(req)
+			}
+
+			res := c.Response()
+			opts.Log(zerowrap.FromCtx(ctx), httplog.Completion{
+				Method:   req.Method,
+				Path:     req.URL.Path,
+				Route:    route,
+				Query:    opts.Query(req.URL.RawQuery),
+				Status:   res.Status,
+				Bytes:    res.Size,
+				Duration: time.Since(start),
+				Headers:  opts.HeaderFields(req.Header.Get),
+				Err:      err,
+
+				RequestBody:          body,
+				RequestContentType:   req.Header.Get("Content-Type"),
+				RequestBodyTruncated: truncated,
+			})
+			return nil
+		}
+	}
+}
+
+// clientIP resolves the client IP with Options.ClientIPSource when set,
+// and echo's IP extractor otherwise.
+func clientIP(c echo.Context, opts httplog.Options) string {
+	if opts.ClientIPSource == "" {
+		return c.RealIP()
+	}
+	req := c.Request()
+	return opts.ClientIP(req.RemoteAddr, req.Header.Get)
+}
@@ -0,0 +1,45 @@
+package echolog_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/echolog"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+func TestErrorHandledOnce(t *testing.T) {
+	var buf bytes.Buffer
+	e := echo.New()
+	handled := 0
+	e.HTTPErrorHandler = func(err error, c echo.Context) {
+		handled++
+		_ = c.NoContent(http.StatusTeapot)
+	}
+	e.Use(echolog.Middleware(httplog.Options{}))
+	e.GET("/fail", func(c echo.Context) error {
+		return echo.NewHTTPError(http.StatusBadRequest, "bad")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req = req.WithContext(zerowrap.WithCtxZerolog(context.Background(), zerolog.New(&buf)))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	if handled != 1 {
+		t.Errorf("error handler ran %d times, want 1", handled)
+	}
+	if rec.Code != http.StatusTeapot {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusTeapot)
+	}
+	if !strings.Contains(buf.String(), `"status":418`) {
+		t.Errorf("completion = %s, want the handled status", buf.String())
+	}
+}
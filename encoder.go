@@ -0,0 +1,215 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Encoder renders log entries in an output format. zerolog writes entries
+// as JSON objects; encoder writers decode them into an Entry and write
+// what the Encoder appends instead, so a format only has to implement
+// the rendering.
+type Encoder interface {
+	// Encode appends the rendering of e to dst, including its line
+	// terminator if the format has one.
+	Encode(dst []byte, e *Entry) ([]byte, error)
+}
+
+// EncoderFunc adapts a function to an Encoder.
+type EncoderFunc func(dst []byte, e *Entry) ([]byte, error)
+
+// Encode implements Encoder.
+func (f EncoderFunc) Encode(dst []byte, e *Entry) ([]byte, error) {
+	return f(dst, e)
+}
+
+// Entry is a log entry decoded for an Encoder.
+type Entry struct {
+	// Level is the entry level, or zerolog.NoLevel if it has none.
+	Level zerolog.Level
+
+	// Time is the entry timestamp, or the zero time if it has none or it
+	// is not in zerolog.TimeFieldFormat (the field is then kept in Fields).
+	Time time.Time
+
+	// Message is the entry message.
+	Message string
+
+	// Fields holds the other fields in entry order, including the caller
+	// and error fields.
+	Fields []EntryField
+}
+
+// EntryField is a field of an Entry, with its raw JSON value.
+type EntryField struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// String returns the field value as text: strings unquoted, other values
+// as their JSON.
+func (f EntryField) String() string {
+	return rawString(f.Value)
+}
+
+// Field returns the field with key, if any.
+func (e *Entry) Field(key string) (EntryField, bool) {
+	for _, f := range e.Fields {
+		if f.Key == key {
+			return f, true
+		}
+	}
+	return EntryField{}, false
+}
+
+// DecodeEntry decodes a JSON entry as written by zerolog.
+func DecodeEntry(p []byte) (*Entry, error) {
+	fields, err := decodeOrdered(p)
+	if err != nil {
+		return nil, err
+	}
+	e := &Entry{Level: zerolog.NoLevel, Fields: fields[:0]}
+	for _, f := range fields {
+		switch f.Key {
+		case zerolog.LevelFieldName:
+			if level, err := zerolog.ParseLevel(f.String()); err == nil {
+				e.Level = level
+				continue
+			}
+		case zerolog.MessageFieldName:
+			if len(f.Value) > 0 && f.Value[0] == '"' {
+				e.Message = f.String()
+				continue
+			}
+		case zerolog.TimestampFieldName:
+			if t, err := time.Parse(zerolog.TimeFieldFormat, f.String()); err == nil {
+				e.Time = t
+				continue
+			}
+		}
+		e.Fields = append(e.Fields, f)
+	}
+	return e, nil
+}
+
+// decodeOrdered decodes the top-level fields of a JSON object in order.
+func decodeOrdered(p []byte) ([]EntryField, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return nil, fmt.Errorf("zerowrap: not a JSON object")
+	}
+	var fields []EntryField
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		fields = append(fields, EntryField{Key: key, Value: value})
+	}
+	return fields, nil
+}
+
+// encoderWriter renders JSON entries with an Encoder.
+type encoderWriter struct {
+	out io.Writer
+	enc Encoder
+}
+
+// NewEncoderWriter returns a writer rendering entries with enc before
+// writing them to out, to use a format on a single sink:
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: zerolog.MultiLevelWriter(
+//	        os.Stdout,
+//	        zerowrap.NewEncoderWriter(siem, cefEncoder),
+//	    ),
+//	})
+//
+// Entries that are not JSON objects, or that enc fails to encode, are
+// written unchanged.
+func NewEncoderWriter(out io.Writer, enc Encoder) zerolog.LevelWriter {
+	return encoderWriter{out: out, enc: enc}
+}
+
+// Write implements io.Writer.
+func (w encoderWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter, passing level on when out
+// is a zerolog.LevelWriter.
+func (w encoderWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	entry, err := DecodeEntry(p)
+	if err != nil {
+		return writeLevel(w.out, level, p)
+	}
+	if level != zerolog.NoLevel {
+		entry.Level = level
+	}
+	buf, err := w.enc.Encode(nil, entry)
+	if err != nil {
+		return writeLevel(w.out, level, p)
+	}
+	if _, err := writeLevel(w.out, level, buf); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// builtinFormats are the formats handled by New itself.
+var builtinFormats = map[string]bool{
+	"": true, "json": true, "console": true, "pretty": true, "json-pretty": true,
+	"gcp": true, "ltsv": true, "cbor": true, "discard": true,
+}
+
+var (
+	formatsMu sync.RWMutex
+	formats   = map[string]Encoder{}
+)
+
+// RegisterFormat makes enc available as Config.Format name (matched
+// case-insensitively) for New and NewWithFile. It panics if name is a
+// built-in format or already registered, or enc is nil, so register
+// formats from init functions or before creating loggers:
+//
+//	func init() {
+//	    zerowrap.RegisterFormat("cef", siemlog.CEF{Vendor: "Acme", Product: "Shop"})
+//	}
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "cef"})
+func RegisterFormat(name string, enc Encoder) {
+	name = strings.ToLower(name)
+	if enc == nil {
+		panic("zerowrap: RegisterFormat encoder is nil")
+	}
+	if builtinFormats[name] {
+		panic("zerowrap: RegisterFormat cannot replace built-in format " + name)
+	}
+	formatsMu.Lock()
+	defer formatsMu.Unlock()
+	if _, dup := formats[name]; dup {
+		panic("zerowrap: RegisterFormat called twice for format " + name)
+	}
+	formats[name] = enc
+}
+
+// LookupFormat returns the encoder registered as name, if any.
+func LookupFormat(name string) (Encoder, bool) {
+	formatsMu.RLock()
+	defer formatsMu.RUnlock()
+	enc, ok := formats[strings.ToLower(name)]
+	return enc, ok
+}
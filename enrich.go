@@ -0,0 +1,100 @@
+package zerowrap
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// enrich adds the process-level fields enabled in cfg to logger.
+func enrich(logger zerolog.Logger, cfg Config) zerolog.Logger {
+	c := logger.With()
+	if cfg.IncludeHost {
+		if host, err := os.Hostname(); err == nil {
+			c = c.Str(FieldHost, host)
+		}
+	}
+	if cfg.IncludePID {
+		c = c.Int(FieldPID, os.Getpid())
+	}
+	if cfg.IncludeGoVersion {
+		c = c.Str(FieldGoVersion, runtime.Version())
+	}
+	if cfg.IncludeBuildInfo {
+		for _, f := range buildInfoFields() {
+			c = f.addTo(c)
+		}
+	}
+	if cfg.IncludeK8s {
+		c = addFields(c, K8sFields())
+	}
+	logger = c.Logger()
+	if cfg.Diagnostics {
+		EnableDiagnostics()
+		logger = logger.Hook(&diagEvents)
+	}
+	if cfg.Counts != nil {
+		logger = logger.Hook(cfg.Counts)
+	}
+
+	if cfg.GoroutineID {
+		logger = logger.Hook(goroutineIDHook{})
+	}
+	return logger
+}
+
+// buildInfoFields returns the main module version and VCS settings
+// embedded by the Go toolchain, read once.
+var buildInfoFields = sync.OnceValue(func() []Field {
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return nil
+	}
+	var fields []Field
+	if v := bi.Main.Version; v != "" && v != "(devel)" {
+		fields = append(fields, Str(FieldModuleVersion, v))
+	}
+	for _, s := range bi.Settings {
+		switch s.Key {
+		case "vcs.revision":
+			fields = append(fields, Str(FieldVCSRevision, s.Value))
+		case "vcs.time":
+			fields = append(fields, Str(FieldVCSTime, s.Value))
+		case "vcs.modified":
+			fields = append(fields, Bool(FieldVCSModified, s.Value == "true"))
+		}
+	}
+	return fields
+})
+
+// goroutineIDHook adds the ID of the logging goroutine to each event.
+type goroutineIDHook struct{}
+
+// Run implements zerolog.Hook.
+func (goroutineIDHook) Run(e *zerolog.Event, _ zerolog.Level, _ string) {
+	if id := goroutineID(); id != 0 {
+		e.Uint64(FieldGoroutineID, id)
+	}
+}
+
+// goroutineID returns the current goroutine ID, parsed from the
+// "goroutine N [running]:" header of its stack trace. Returns 0 if it
+// cannot be parsed.
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, err := strconv.ParseUint(string(b), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return id
+}
@@ -0,0 +1,63 @@
+// Package eslog ships zerolog JSON entries to Elasticsearch or OpenSearch
+// through the bulk API, for teams indexing logs without Logstash or an agent.
+// It also logs the requests of Elasticsearch and OpenSearch clients (see
+// NewSearchTransport).
+//
+// # Usage
+//
+//	es, err := eslog.NewWriter(eslog.Config{
+//	    URL:    "https://es.internal:9200",
+//	    Index:  "app-logs", // app-logs-2024.06.01, one index per day
+//	    APIKey: os.Getenv("ES_API_KEY"),
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer es.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: io.MultiWriter(os.Stderr, es),
+//	})
+//
+// # Batching and Backpressure
+//
+// Entries are queued and sent in bulk requests of BatchSize entries, or
+// every FlushInterval. When the cluster cannot keep up and the queue is
+// full, entries are dropped and counted (see Dropped); set Block to make
+// logging wait instead. Throttled (429), 5xx and network failures are
+// retried with exponential backoff; item-level failures are reported
+// through OnError.
+//
+// # Search Client Logging
+//
+// NewSearchTransport is an http.RoundTripper for Elasticsearch and
+// OpenSearch clients that logs each request with the logger from its
+// context: the index, the query body of search APIs (size-capped), and
+// took_ms, hits, timed_out and shard failures from the response.
+//
+//	es, err := elasticsearch.NewClient(elasticsearch.Config{
+//	    Transport: eslog.NewSearchTransport(nil, eslog.TransportConfig{}),
+//	})
+//
+// Timed out searches, shard failures, slow searches (see SlowThreshold)
+// and error responses log at warn. Bodies of other APIs, such as
+// documents and bulk requests, are not logged.
+//
+// # ECS Mode
+//
+// With ECS set, zerowrap field names are mapped to Elastic Common Schema
+// fields so Kibana's log views and dashboards work out of the box:
+//
+//	time         @timestamp
+//	level        log.level
+//	error        error.message
+//	trace_id     trace.id
+//	request_id   http.request.id
+//	method       http.request.method
+//	status       http.response.status_code
+//	duration_ms  event.duration (nanoseconds)
+//	service      service.name
+//
+// Unmapped fields are indexed as-is.
+package eslog
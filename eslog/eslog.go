@@ -0,0 +1,480 @@
+package eslog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// ErrClosed is returned by Flush after the writer was closed.
+var ErrClosed = errors.New("eslog: writer is closed")
+
+// Config holds configuration for the Elasticsearch bulk writer.
+type Config struct {
+	// URL is the cluster base URL, e.g. "http://localhost:9200".
+	URL string
+
+	// Index is the index name prefix; the entry date is appended
+	// ("app-logs" becomes "app-logs-2024.06.01").
+	// Defaults to "logs" if empty.
+	Index string
+
+	// DateLayout is the Go time layout of the index date suffix.
+	// Defaults to "2006.01.02" if empty. Set to "-" for a fixed index name.
+	DateLayout string
+
+	// Username and Password enable basic authentication.
+	Username string
+	Password string
+
+	// APIKey enables API key authentication (the base64 encoded key).
+	APIKey string
+
+	// BatchSize is the number of entries per bulk request.
+	// Defaults to 500 if 0.
+	BatchSize int
+
+	// FlushInterval is the maximum time an entry waits before being sent.
+	// Defaults to 5 seconds if 0.
+	FlushInterval time.Duration
+
+	// QueueSize is the number of entries buffered while requests are in flight.
+	// Defaults to 10000 if 0.
+	QueueSize int
+
+	// Block makes Write wait for queue space instead of dropping entries
+	// when the cluster cannot keep up.
+	Block bool
+
+	// MaxRetries is the number of retries for bulk requests failing with
+	// 429, 5xx or a network error, with exponential backoff.
+	// Defaults to 3 if 0.
+	MaxRetries int
+
+	// ECS maps zerowrap field names to Elastic Common Schema fields
+	// (time to @timestamp, level to log.level, trace_id to trace.id, ...).
+	ECS bool
+
+	// Client sends the bulk requests.
+	// Defaults to an http.Client with a 30 second timeout if nil.
+	Client *http.Client
+
+	// OnError is called when a bulk request or some of its items fail.
+	// Defaults to reporting the error with zerowrap.ReportError if nil.
+	OnError func(error)
+}
+
+// Writer is an io.Writer that ships zerolog JSON entries to Elasticsearch
+// or OpenSearch through the bulk API.
+type Writer struct {
+	cfg    Config
+	client *http.Client
+	url    string
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan []byte
+	flushes chan chan struct{}
+	done    chan struct{}
+	drops   atomic.Int64
+}
+
+// NewWriter creates a bulk writer and starts its background sender.
+// Call Close before exit to send buffered entries.
+//
+//	es, err := eslog.NewWriter(eslog.Config{
+//	    URL:   "http://localhost:9200",
+//	    Index: "app-logs",
+//	    ECS:   true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer es.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: io.MultiWriter(os.Stderr, es),
+//	})
+func NewWriter(cfg Config) (*Writer, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("eslog: URL is required")
+	}
+	if cfg.Index == "" {
+		cfg.Index = "logs"
+	}
+	if cfg.DateLayout == "" {
+		cfg.DateLayout = "2006.01.02"
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 500
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = 5 * time.Second
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = 3
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error) {
+			zerowrap.ReportError(fmt.Errorf("%w: %w", zerowrap.ErrWrite, err))
+		}
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	w := &Writer{
+		cfg:     cfg,
+		client:  client,
+		url:     strings.TrimRight(cfg.URL, "/") + "/_bulk",
+		queue:   make(chan []byte, cfg.QueueSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write implements io.Writer. Each call must hold one JSON entry, as
+// written by zerolog. Entries are dropped (see Dropped) when the queue is
+// full, unless Config.Block is set.
+func (w *Writer) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p))
+	copy(entry, p)
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.drop(1)
+		return len(p), nil
+	}
+	if w.cfg.Block {
+		w.queue <- entry
+		return len(p), nil
+	}
+	select {
+	case w.queue <- entry:
+	default:
+		w.drop(1)
+	}
+	return len(p), nil
+}
+
+// Flush sends all buffered entries and waits for the request to complete.
+func (w *Writer) Flush(ctx context.Context) error {
+	w.mu.RLock()
+	closed := w.closed
+	w.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	flushed := make(chan struct{})
+	select {
+	case w.flushes <- flushed:
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close sends buffered entries and stops the writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the queue was
+// full or the writer was closed.
+func (w *Writer) Dropped() int64 {
+	return w.drops.Load()
+}
+
+// drop counts n dropped entries and reports them with zerowrap.ErrDropped.
+func (w *Writer) drop(n int) {
+	w.drops.Add(int64(n))
+	zerowrap.ReportError(fmt.Errorf("%w: eslog: %d entries", zerowrap.ErrDropped, n))
+}
+
+// run batches queued entries into bulk requests.
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var body bytes.Buffer
+	n := 0
+	send := func() {
+		if n > 0 {
+			w.send(body.Bytes(), n)
+			body.Reset()
+			n = 0
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				send()
+				return
+			}
+			if w.appendAction(&body, entry) {
+				n++
+			}
+			if n >= w.cfg.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-w.flushes:
+			for drained := false; !drained; {
+				select {
+				case entry, ok := <-w.queue:
+					if !ok {
+						drained = true
+						break
+					}
+					if w.appendAction(&body, entry) {
+						n++
+					}
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(flushed)
+		}
+	}
+}
+
+// appendAction appends the bulk action and document for entry to body.
+// Returns false if entry is not a JSON object.
+func (w *Writer) appendAction(body *bytes.Buffer, entry []byte) bool {
+	dec := json.NewDecoder(bytes.NewReader(entry))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return false
+	}
+
+	ts := entryTime(doc)
+	if w.cfg.ECS {
+		doc = toECS(doc)
+	}
+	src, err := json.Marshal(doc)
+	if err != nil {
+		return false
+	}
+
+	action, _ := json.Marshal(map[string]any{
+		"create": map[string]string{"_index": w.index(ts)},
+	})
+	body.Write(action)
+	body.WriteByte('\n')
+	body.Write(src)
+	body.WriteByte('\n')
+	return true
+}
+
+// index returns the index name for an entry logged at ts.
+func (w *Writer) index(ts time.Time) string {
+	if w.cfg.DateLayout == "-" {
+		return w.cfg.Index
+	}
+	return w.cfg.Index + "-" + ts.UTC().Format(w.cfg.DateLayout)
+}
+
+// send posts a bulk body, retrying throttled and failed requests.
+func (w *Writer) send(body []byte, n int) {
+	backoff := 500 * time.Millisecond
+	var err error
+	for attempt := 0; attempt <= w.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+		var retry bool
+		retry, err = w.post(body)
+		if !retry {
+			break
+		}
+	}
+	if err != nil {
+		w.cfg.OnError(fmt.Errorf("eslog: bulk request with %d entries: %w", n, err))
+	}
+}
+
+// post sends one bulk request and reports whether it should be retried.
+func (w *Writer) post(body []byte) (bool, error) {
+	req, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-ndjson")
+	switch {
+	case w.cfg.APIKey != "":
+		req.Header.Set("Authorization", "ApiKey "+w.cfg.APIKey)
+	case w.cfg.Username != "":
+		req.SetBasicAuth(w.cfg.Username, w.cfg.Password)
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return true, fmt.Errorf("status %s", resp.Status)
+	}
+	if resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return false, fmt.Errorf("status %s: %s", resp.Status, msg)
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+		Items  []map[string]struct {
+			Status int `json:"status"`
+			Error  struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"error"`
+		} `json:"items"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil || !result.Errors {
+		return false, nil
+	}
+
+	failed := 0
+	var first string
+	for _, item := range result.Items {
+		for _, r := range item {
+			if r.Status >= 300 {
+				if failed == 0 {
+					first = r.Error.Type + ": " + r.Error.Reason
+				}
+				failed++
+			}
+		}
+	}
+	return false, fmt.Errorf("%d items failed, first: %s", failed, first)
+}
+
+// entryTime returns the entry's timestamp, or the current time.
+func entryTime(doc map[string]any) time.Time {
+	if s, ok := doc[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(zerolog.TimeFieldFormat, s); err == nil {
+			return t
+		}
+	}
+	return time.Now()
+}
+
+// ecsFields maps zerowrap field names to ECS field names.
+var ecsFields = map[string]string{
+	zerolog.TimestampFieldName: "@timestamp",
+	zerolog.LevelFieldName:     "log.level",
+	zerolog.ErrorFieldName:     "error.message",
+	zerolog.CallerFieldName:    "log.origin.file.name",
+	zerowrap.FieldStack:        "error.stack_trace",
+	zerowrap.FieldTraceID:      "trace.id",
+	zerowrap.FieldSpanID:       "span.id",
+	zerowrap.FieldRequestID:    "http.request.id",
+	zerowrap.FieldUserID:       "user.id",
+	zerowrap.FieldSessionID:    "session.id",
+	zerowrap.FieldMethod:       "http.request.method",
+	zerowrap.FieldPath:         "url.path",
+	zerowrap.FieldRoute:        "http.route",
+	zerowrap.FieldQueryStr:     "url.query",
+	zerowrap.FieldStatus:       "http.response.status_code",
+	zerowrap.FieldSize:         "http.response.body.bytes",
+	zerowrap.FieldClientIP:     "client.ip",
+	zerowrap.FieldService:      "service.name",
+	zerowrap.FieldVersion:      "service.version",
+	zerowrap.FieldEnv:          "service.environment",
+	zerowrap.FieldHost:         "host.name",
+	zerowrap.FieldAction:       "event.action",
+}
+
+// toECS renames fields to their ECS equivalents. Durations (duration_ms,
+// duration or duration_ns) become event.duration in nanoseconds, as ECS
+// requires.
+func toECS(doc map[string]any) map[string]any {
+	out := make(map[string]any, len(doc)+1)
+	for k, v := range doc {
+		if ecs, ok := ecsFields[k]; ok {
+			out[ecs] = v
+			continue
+		}
+		if d, ok := ecsDuration(k, v); ok {
+			out["event.duration"] = int64(d)
+			continue
+		}
+		out[k] = v
+	}
+	out["ecs.version"] = "8.11.0"
+	return out
+}
+
+// ecsDuration parses a duration field in any zerowrap duration format.
+func ecsDuration(key string, v any) (time.Duration, bool) {
+	switch key {
+	case zerowrap.FieldDuration, zerowrap.FieldDurationNs:
+		n, ok := v.(json.Number)
+		if !ok {
+			return 0, false
+		}
+		f, err := n.Float64()
+		if err != nil {
+			return 0, false
+		}
+		if key == zerowrap.FieldDuration {
+			return time.Duration(f * float64(time.Millisecond)), true
+		}
+		return time.Duration(f), true
+	case zerowrap.FieldDurationText:
+		s, ok := v.(string)
+		if !ok {
+			return 0, false
+		}
+		d, err := time.ParseDuration(s)
+		return d, err == nil
+	}
+	return 0, false
+}
@@ -0,0 +1,306 @@
+package eslog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Field names logged by SearchTransport.
+const (
+	FieldIndex         = "es_index"
+	FieldTook          = "took_ms"
+	FieldTimedOut      = "timed_out"
+	FieldHits          = "hits"
+	FieldShardsTotal   = "shards_total"
+	FieldShardsFailed  = "shards_failed"
+	FieldShardFailures = "shard_failures"
+	FieldErrorType     = "es_error_type"
+)
+
+// queryEndpoints are the path segments of APIs whose request body is a
+// query, logged by SearchTransport. Bodies of other APIs (documents,
+// bulk requests) are not logged.
+var queryEndpoints = []string{
+	"_search", "_msearch", "_async_search", "_count", "_explain",
+	"_delete_by_query", "_update_by_query", "_validate", "_knn_search",
+	"_sql", "_eql", "_field_caps", "_terms_enum",
+}
+
+// maxShardFailures is the number of shard failures logged per request.
+const maxShardFailures = 5
+
+// TransportConfig holds configuration for SearchTransport.
+type TransportConfig struct {
+	// Level is the level of successful requests. 4xx/5xx responses,
+	// timed out searches and shard failures log at warn, transport errors
+	// at error.
+	// Defaults to debug if 0.
+	Level zerolog.Level
+
+	// SlowThreshold logs requests whose took exceeds this at warn level.
+	// Disabled if 0.
+	SlowThreshold time.Duration
+
+	// MaxBodyBytes caps the logged query body; longer bodies are cut and
+	// logged as a string.
+	// Defaults to 4096 if 0.
+	MaxBodyBytes int
+
+	// MaxResponseBytes caps the response size read to find took, hits
+	// and shard failures; larger responses are passed on without them.
+	// Defaults to 10 MiB if 0.
+	MaxResponseBytes int
+}
+
+// SearchTransport is an http.RoundTripper for Elasticsearch and
+// OpenSearch clients that logs each request with the logger from its
+// context: the index, the query body of search APIs (size-capped), and
+// from the response the took time, total hits, timeouts and shard
+// failures, which a plain HTTP log does not show.
+type SearchTransport struct {
+	next http.RoundTripper
+	cfg  TransportConfig
+}
+
+// NewSearchTransport wraps rt with search request logging. If rt is nil,
+// http.DefaultTransport is used.
+//
+//	es, err := elasticsearch.NewClient(elasticsearch.Config{
+//	    Addresses: []string{"https://es.internal:9200"},
+//	    Transport: eslog.NewSearchTransport(nil, eslog.TransportConfig{}),
+//	})
+//
+//	res, err := es.Search(es.Search.WithContext(ctx), es.Search.WithIndex("products"), ...)
+//	// "search request completed" with es_index=products took_ms=12 hits=48 shards_total=5
+func NewSearchTransport(rt http.RoundTripper, cfg TransportConfig) *SearchTransport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if cfg.MaxBodyBytes == 0 {
+		cfg.MaxBodyBytes = 4096
+	}
+	if cfg.MaxResponseBytes == 0 {
+		cfg.MaxResponseBytes = 10 << 20
+	}
+	return &SearchTransport{next: rt, cfg: cfg}
+}
+
+// searchResponse holds the response fields logged by SearchTransport.
+type searchResponse struct {
+	Took     *int64 `json:"took"`
+	TimedOut bool   `json:"timed_out"`
+	Shards   *struct {
+		Total    int `json:"total"`
+		Failed   int `json:"failed"`
+		Failures []struct {
+			Index  string `json:"index"`
+			Shard  int    `json:"shard"`
+			Reason struct {
+				Type   string `json:"type"`
+				Reason string `json:"reason"`
+			} `json:"reason"`
+		} `json:"failures"`
+	} `json:"_shards"`
+	Hits *struct {
+		Total json.RawMessage `json:"total"`
+	} `json:"hits"`
+	Error json.RawMessage `json:"error"`
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *SearchTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := zerowrap.FromCtx(req.Context())
+
+	var body []byte
+	if isQuery(req.URL.Path) && req.Body != nil && req.Body != http.NoBody {
+		body, req = t.captureRequestBody(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	dur := time.Since(start)
+
+	var sr searchResponse
+	parsed := err == nil && t.readResponse(resp, &sr)
+
+	level := t.cfg.Level
+	switch {
+	case err != nil:
+		level = zerolog.ErrorLevel
+	case resp.StatusCode >= 400, sr.TimedOut, sr.Shards != nil && sr.Shards.Failed > 0:
+		level = zerolog.WarnLevel
+	case t.cfg.SlowThreshold > 0 && sr.Took != nil && time.Duration(*sr.Took)*time.Millisecond >= t.cfg.SlowThreshold:
+		level = zerolog.WarnLevel
+	}
+
+	e := log.WithLevel(level)
+	if e == nil {
+		return resp, err
+	}
+	e = e.Str(zerowrap.FieldMethod, req.Method).
+		Str(zerowrap.FieldHost, req.URL.Host).
+		Str(zerowrap.FieldPath, req.URL.Path)
+	if index := indexName(req.URL.Path); index != "" {
+		e = e.Str(FieldIndex, index)
+	}
+	if body != nil {
+		e = t.addBody(e, body)
+	}
+	if err != nil {
+		zerowrap.AddDuration(e.Err(err), dur).Msg("search request failed")
+		return resp, err
+	}
+
+	e = e.Int(zerowrap.FieldStatus, resp.StatusCode)
+	if parsed {
+		e = addResponse(e, &sr)
+	}
+	zerowrap.AddDuration(e, dur).Msg("search request completed")
+	return resp, nil
+}
+
+// captureRequestBody reads up to MaxBodyBytes+1 bytes of the request
+// body. It returns the request to send: without GetBody, a clone whose
+// body replays the bytes read, as RoundTrippers must not modify the
+// caller's request.
+func (t *SearchTransport) captureRequestBody(req *http.Request) ([]byte, *http.Request) {
+	limit := int64(t.cfg.MaxBodyBytes) + 1
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, req
+		}
+		defer body.Close()
+		buf, _ := io.ReadAll(io.LimitReader(body, limit))
+		return buf, req
+	}
+	buf, _ := io.ReadAll(io.LimitReader(req.Body, limit))
+	clone := req.Clone(req.Context())
+	clone.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+	return buf, clone
+}
+
+// addBody adds the query body to e: as JSON when complete and valid,
+// otherwise cut at MaxBodyBytes and marked truncated. Multi-search
+// bodies (NDJSON) are logged as a string.
+func (t *SearchTransport) addBody(e *zerolog.Event, body []byte) *zerolog.Event {
+	if len(body) > t.cfg.MaxBodyBytes {
+		return e.Bytes(zerowrap.FieldRequestBody, body[:t.cfg.MaxBodyBytes]).Bool(zerowrap.FieldTruncated, true)
+	}
+	if trimmed := bytes.TrimSpace(body); json.Valid(trimmed) {
+		return e.RawJSON(zerowrap.FieldRequestBody, trimmed)
+	}
+	return e.Bytes(zerowrap.FieldRequestBody, body)
+}
+
+// readResponse decodes the logged fields of a JSON response of at most
+// MaxResponseBytes into sr, leaving the body readable for the caller.
+func (t *SearchTransport) readResponse(resp *http.Response, sr *searchResponse) bool {
+	if resp.Body == nil || !strings.Contains(resp.Header.Get("Content-Type"), "json") {
+		return false
+	}
+	if resp.ContentLength > int64(t.cfg.MaxResponseBytes) {
+		return false
+	}
+	buf, err := io.ReadAll(io.LimitReader(resp.Body, int64(t.cfg.MaxResponseBytes)+1))
+	if err != nil || len(buf) > t.cfg.MaxResponseBytes {
+		resp.Body = readCloser{io.MultiReader(bytes.NewReader(buf), resp.Body), resp.Body}
+		return false
+	}
+	resp.Body = readCloser{bytes.NewReader(buf), resp.Body}
+	return json.Unmarshal(buf, sr) == nil
+}
+
+// addResponse adds took, hits, timeouts, shard failures and the error
+// type of a response to e.
+func addResponse(e *zerolog.Event, sr *searchResponse) *zerolog.Event {
+	if sr.Took != nil {
+		e = e.Int64(FieldTook, *sr.Took)
+	}
+	if sr.Hits != nil {
+		if total, ok := hitsTotal(sr.Hits.Total); ok {
+			e = e.Int64(FieldHits, total)
+		}
+	}
+	if sr.TimedOut {
+		e = e.Bool(FieldTimedOut, true)
+	}
+	if s := sr.Shards; s != nil {
+		e = e.Int(FieldShardsTotal, s.Total)
+		if s.Failed > 0 {
+			e = e.Int(FieldShardsFailed, s.Failed)
+			arr := zerolog.Arr()
+			for i, f := range s.Failures {
+				if i == maxShardFailures {
+					break
+				}
+				arr = arr.Dict(zerolog.Dict().
+					Str("index", f.Index).
+					Int("shard", f.Shard).
+					Str("type", f.Reason.Type).
+					Str("reason", f.Reason.Reason))
+			}
+			e = e.Array(FieldShardFailures, arr)
+		}
+	}
+	if len(sr.Error) > 0 {
+		var typed struct {
+			Type string `json:"type"`
+		}
+		if json.Unmarshal(sr.Error, &typed) == nil && typed.Type != "" {
+			e = e.Str(FieldErrorType, typed.Type)
+		}
+	}
+	return e
+}
+
+// hitsTotal returns hits.total, a number before Elasticsearch 7 and an
+// object with a value since.
+func hitsTotal(raw json.RawMessage) (int64, bool) {
+	var n int64
+	if json.Unmarshal(raw, &n) == nil {
+		return n, true
+	}
+	var obj struct {
+		Value *int64 `json:"value"`
+	}
+	if json.Unmarshal(raw, &obj) == nil && obj.Value != nil {
+		return *obj.Value, true
+	}
+	return 0, false
+}
+
+// isQuery reports whether path is a search API taking a query body.
+func isQuery(path string) bool {
+	for _, seg := range strings.Split(path, "/") {
+		for _, ep := range queryEndpoints {
+			if seg == ep {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// indexName returns the index (or comma-separated indices) of path,
+// the first segment when it is not an API name.
+func indexName(path string) string {
+	seg, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	if seg == "" || strings.HasPrefix(seg, "_") {
+		return ""
+	}
+	return seg
+}
+
+// readCloser combines a reader with the closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -0,0 +1,94 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/rs/zerolog"
+)
+
+// sizeLimitWriter replaces entries larger than max bytes with a summary
+// keeping the level, time, caller and message, or drops them.
+type sizeLimitWriter struct {
+	w    io.Writer
+	max  int
+	drop bool
+}
+
+// newSizeLimitWriter wraps w.
+func newSizeLimitWriter(w io.Writer, max int, drop bool) *sizeLimitWriter {
+	return &sizeLimitWriter{w: w, max: max, drop: drop}
+}
+
+// Write implements io.Writer.
+func (s *sizeLimitWriter) Write(p []byte) (int, error) {
+	return s.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (s *sizeLimitWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if len(p) <= s.max {
+		return writeLevel(s.w, level, p)
+	}
+	if !s.drop {
+		if out, ok := s.summarize(p); ok {
+			if _, err := writeLevel(s.w, level, out); err != nil {
+				return 0, err
+			}
+			return len(p), nil
+		}
+	}
+	ReportError(fmt.Errorf("%w: %d byte %s entry over MaxEventBytes", ErrDropped, len(p), level))
+	return len(p), nil
+}
+
+// summarize returns a replacement for the oversized entry p holding its
+// level, time, caller and message, FieldDroppedFields and
+// FieldEventBytes. The message is cut if the summary is still too large.
+// ok is false when p is not a JSON object or no summary fits.
+func (s *sizeLimitWriter) summarize(p []byte) (out []byte, ok bool) {
+	var msg string
+	kept := make(map[string]json.RawMessage, 3)
+	dropped := 0
+	err := eachField(p, func(key string, raw json.RawMessage) {
+		switch key {
+		case zerolog.LevelFieldName, zerolog.TimestampFieldName, zerolog.CallerFieldName:
+			kept[key] = raw
+		case zerolog.MessageFieldName:
+			_ = json.Unmarshal(raw, &msg)
+		default:
+			dropped++
+		}
+	})
+	if err != nil {
+		return nil, false
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, 256))
+	buf.WriteByte('{')
+	for _, key := range []string{zerolog.LevelFieldName, zerolog.TimestampFieldName, zerolog.CallerFieldName} {
+		if raw, ok := kept[key]; ok {
+			appendField(buf, key, raw)
+		}
+	}
+	appendField(buf, FieldDroppedFields, json.RawMessage(strconv.Itoa(dropped)))
+	appendField(buf, FieldEventBytes, json.RawMessage(strconv.Itoa(len(p))))
+
+	// Leave room for the message key, quotes, braces and newline; the
+	// message is cut further if escaping makes it longer.
+	room := s.max - buf.Len() - len(zerolog.MessageFieldName) - 8
+	if room < 0 {
+		return nil, false
+	}
+	msg = truncateString(msg, room)
+	raw, _ := json.Marshal(msg)
+	for len(raw) > room && msg != "" {
+		msg = truncateString(msg, min(len(msg)-1, len(msg)*room/len(raw)))
+		raw, _ = json.Marshal(msg)
+	}
+	appendField(buf, zerolog.MessageFieldName, raw)
+	return closeEntry(buf, p), true
+}
@@ -0,0 +1,157 @@
+package zerowrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// FailoverConfig holds configuration for a failover writer.
+type FailoverConfig struct {
+	// Primary is the preferred sink, e.g. a NetWriter or file.
+	Primary io.Writer
+
+	// Fallback receives entries while the primary is unhealthy.
+	// Defaults to os.Stderr if nil.
+	Fallback io.Writer
+
+	// MaxFailures is the number of consecutive primary write failures
+	// after which the primary is marked unhealthy.
+	// Defaults to 3 if 0.
+	MaxFailures int
+
+	// RetryInterval is how often an unhealthy primary is tried again.
+	// Defaults to 10 seconds if 0.
+	RetryInterval time.Duration
+
+	// OnStateChange is called when the primary becomes unhealthy (with the
+	// last write error) or healthy again (with a nil error).
+	OnStateChange func(healthy bool, err error)
+}
+
+// FailoverWriter is a zerolog.LevelWriter that writes to a primary sink
+// and fails over to a fallback writer when the primary keeps failing.
+// Entries that fail on the primary are written to the fallback, so none
+// are lost. While unhealthy, the primary is retried every RetryInterval
+// and used again as soon as a write succeeds.
+//
+// State changes are reported as diagnostic entries on the fallback, with
+// component "zerowrap", so the failover itself shows up in the logs.
+type FailoverWriter struct {
+	cfg FailoverConfig
+
+	mu        sync.Mutex
+	failures  int
+	healthy   bool
+	lastRetry time.Time
+}
+
+// NewFailoverWriter creates a failover writer.
+//
+//	agent, _ := zerowrap.NewNetWriter(zerowrap.NetConfig{Network: "tcp", Addr: "127.0.0.1:9000"})
+//	out, err := zerowrap.NewFailoverWriter(zerowrap.FailoverConfig{
+//	    Primary:  agent,
+//	    Fallback: os.Stderr,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: out})
+func NewFailoverWriter(cfg FailoverConfig) (*FailoverWriter, error) {
+	if cfg.Primary == nil {
+		return nil, errors.New("zerowrap: failover primary is required")
+	}
+	if cfg.Fallback == nil {
+		cfg.Fallback = os.Stderr
+	}
+	if cfg.MaxFailures == 0 {
+		cfg.MaxFailures = 3
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = 10 * time.Second
+	}
+	return &FailoverWriter{cfg: cfg, healthy: true}, nil
+}
+
+// Write implements io.Writer.
+func (w *FailoverWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Level-aware sinks receive
+// the entry level.
+func (w *FailoverWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.healthy && time.Since(w.lastRetry) < w.cfg.RetryInterval {
+		return writeLevel(w.cfg.Fallback, level, p)
+	}
+
+	_, err := writeLevel(w.cfg.Primary, level, p)
+	if err == nil {
+		w.failures = 0
+		if !w.healthy {
+			w.healthy = true
+			w.diagnose(zerolog.InfoLevel, "primary sink recovered", nil)
+		}
+		return len(p), nil
+	}
+
+	if w.healthy {
+		w.failures++
+		if w.failures >= w.cfg.MaxFailures {
+			w.healthy = false
+			w.diagnose(zerolog.WarnLevel, "primary sink failing, switching to fallback", err)
+		}
+	}
+	w.lastRetry = time.Now()
+	ReportError(fmt.Errorf("%w: failover primary: %w", ErrWrite, err))
+	return writeLevel(w.cfg.Fallback, level, p)
+}
+
+// Healthy reports whether entries currently go to the primary sink.
+func (w *FailoverWriter) Healthy() bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.healthy
+}
+
+// Close closes the primary and fallback sinks if they implement io.Closer.
+// os.Stdout and os.Stderr are never closed.
+func (w *FailoverWriter) Close() error {
+	var errs []error
+	for _, out := range []io.Writer{w.cfg.Primary, w.cfg.Fallback} {
+		if out == os.Stdout || out == os.Stderr {
+			continue
+		}
+		if c, ok := out.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// diagnose writes a diagnostic entry to the fallback and notifies
+// OnStateChange.
+func (w *FailoverWriter) diagnose(level zerolog.Level, msg string, err error) {
+	diag := zerolog.New(w.cfg.Fallback).With().Timestamp().Str(FieldComponent, "zerowrap").Logger()
+	diag.WithLevel(level).Err(err).Msg(msg)
+	if w.cfg.OnStateChange != nil {
+		w.cfg.OnStateChange(err == nil, err)
+	}
+}
+
+// writeLevel writes p to out, passing the level to level-aware writers.
+func writeLevel(out io.Writer, level zerolog.Level, p []byte) (int, error) {
+	if lw, ok := out.(zerolog.LevelWriter); ok && level != zerolog.NoLevel {
+		return lw.WriteLevel(level, p)
+	}
+	return out.Write(p)
+}
@@ -0,0 +1,29 @@
+// Package fiberlog provides fiber request logging for zerowrap.
+//
+// This is an optional sub-package that adds the fiber dependency.
+// Fiber is built on fasthttp rather than net/http, so the logger travels
+// in fiber's UserContext. Options are shared with httplog, so redaction
+// and slow-request settings behave the same across routers.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/fiberlog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	)
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json"})
+//
+//	app := fiber.New()
+//	app.Use(fiberlog.Middleware(log, httplog.Options{
+//	    SlowThreshold: 500 * time.Millisecond,
+//	    Headers:       []string{"User-Agent"},
+//	}))
+//
+//	app.Get("/users/:id", func(c *fiber.Ctx) error {
+//	    log := zerowrap.FromCtx(c.UserContext())
+//	    log.Info().Msg("fetching user") // includes request_id, method, path
+//	    return c.JSON(user)
+//	})
+package fiberlog
@@ -0,0 +1,93 @@
+package fiberlog
+
+import (
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+)
+
+// Middleware returns fiber request logging middleware.
+// It attaches base, enriched with request fields (request_id, method, path,
+// client_ip), to fiber's UserContext and logs completion with the fiber
+// route template (e.g. "/users/:id"), status, size and duration.
+//
+//	app := fiber.New()
+//	app.Use(fiberlog.Middleware(log, httplog.Options{}))
+//
+// Options.RoutePattern is ignored since fiber does not use net/http.
+//
+// Values fiber returns point into fasthttp buffers reused by the next
+// request. Those kept in the context (headers, method, path, client IP)
+// are copied, so loggers used after the handler returns (e.g. by
+// goroutines it started) do not log another request's values.
+func Middleware(base zerowrap.Logger, opts httplog.Options) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		get := func(key string) string { return utils.CopyString(c.Get(key)) }
+
+		ip := clientIP(c, opts, get)
+		ctx := zerowrap.WithCtx(c.UserContext(), base)
+		ctx = opts.WithIDs(ctx, get)
+		ctx = zerowrap.CtxWithFields(ctx, map[string]any{
+			zerowrap.FieldMethod:   utils.CopyString(c.Method()),
+			zerowrap.FieldPath:     utils.CopyString(c.Path()),
+			zerowrap.FieldClientIP: ip,
+		})
+		ctx = opts.WithGeo(ctx, ip)
+		ctx = opts.WithUserAgent(ctx, get)
+		ctx = opts.WithDebug(ctx, get)
+		c.SetUserContext(ctx)
+		body, truncated := requestBody(c, opts)
+
+		err := c.Next()
+		if err != nil {
+			// Run the error handler so the logged status is the final one.
+			if herr := c.App().ErrorHandler(c, err); herr != nil {
+				_ = c.SendStatus(fiber.StatusInternalServerError)
+			}
+		}
+
+		opts.Log(zerowrap.FromCtx(ctx), httplog.Completion{
+			Method:   c.Method(),
+			Path:     c.Path(),
+			Route:    c.Route().Path,
+			Query:    opts.Query(string(c.Request().URI().QueryString())),
+			Status:   c.Response().StatusCode(),
+			Bytes:    int64(len(c.Response().Body())),
+			Duration: time.Since(start),
+			Headers:  opts.HeaderFields(get),
+			Err:      err,
+
+			RequestBody:          body,
+			RequestContentType:   get("Content-Type"),
+			RequestBodyTruncated: truncated,
+		})
+		return nil
+	}
+}
+
+// clientIP resolves the client IP with Options.ClientIPSource when set,
+// and fiber's proxy settings otherwise. fiber may return a proxy header
+// value, which is copied.
+func clientIP(c *fiber.Ctx, opts httplog.Options, get func(string) string) string {
+	if opts.ClientIPSource == "" {
+		return utils.CopyString(c.IP())
+	}
+	return opts.ClientIP(c.Context().RemoteAddr().String(), get)
+}
+
+// requestBody returns up to Options.RequestBodyLimit bytes of the request
+// body, already buffered by fiber.
+func requestBody(c *fiber.Ctx, opts httplog.Options) (body []byte, truncated bool) {
+	if opts.RequestBodyLimit <= 0 {
+		return nil, false
+	}
+	body = c.Body()
+	if len(body) > opts.RequestBodyLimit {
+		return body[:opts.RequestBodyLimit], true
+	}
+	return body, false
+}
@@ -0,0 +1,62 @@
+package fiberlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/fiberlog"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/valyala/fasthttp"
+)
+
+func TestContextOutlivesRequest(t *testing.T) {
+	var buf bytes.Buffer
+	app := fiber.New()
+	app.Use(fiberlog.Middleware(zerowrap.Logger{Logger: zerolog.New(&buf).Level(zerolog.WarnLevel)}, httplog.Options{}))
+
+	var ctxs []context.Context
+	app.All("/*", func(c *fiber.Ctx) error {
+		ctxs = append(ctxs, c.UserContext())
+		return nil
+	})
+
+	// fasthttp reuses a RequestCtx, and its buffers, for the next
+	// request of a connection.
+	var fctx fasthttp.RequestCtx
+	handler := app.Handler()
+	for _, r := range []struct{ method, path, id string }{
+		{"GET", "/first", "aaaaaaaa"},
+		{"PUT", "/other", "bbbbbbbb"},
+	} {
+		fctx.Request.Header.SetMethod(r.method)
+		fctx.Request.SetRequestURI(r.path)
+		fctx.Request.Header.Set(httplog.DefaultRequestIDHeader, r.id)
+		handler(&fctx)
+	}
+
+	buf.Reset()
+	log := zerowrap.FromCtx(ctxs[0])
+	log.Warn().Msg("later")
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("entry %q: %v", buf.String(), err)
+	}
+	want := map[string]string{
+		zerowrap.FieldRequestID: "aaaaaaaa",
+		zerowrap.FieldMethod:    "GET",
+		zerowrap.FieldPath:      "/first",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %s", k, entry[k], v)
+		}
+	}
+	if id := zerowrap.RequestIDFromCtx(ctxs[0]); id != "aaaaaaaa" {
+		t.Errorf("RequestIDFromCtx = %q, want aaaaaaaa", id)
+	}
+}
@@ -0,0 +1,297 @@
+package zerowrap
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net"
+	"net/netip"
+	"net/url"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// fieldKind is the value type of a Field, selecting the zerolog method
+// writing it.
+type fieldKind uint8
+
+const (
+	fieldAny fieldKind = iota // marshaled with encoding/json
+	fieldStr
+	fieldInt
+	fieldUint
+	fieldFloat
+	fieldFloat32
+	fieldBool
+	fieldDur
+	fieldTime
+	fieldErr
+	fieldRawJSON
+	fieldBytes
+	fieldStrs
+	fieldObject
+	fieldArray
+	fieldIP
+)
+
+// Field is a typed key/value pair. Unlike map[string]any, a list of
+// fields passed to FromCtxWith, CtxWith or Logger.WithTyped stays on the
+// stack, and the values of Str, Int, Int64, Uint64, Float64, Bool, Dur,
+// Time and Err fields are stored and added without boxing or type
+// switches. Any and RawJSON values are boxed and converted like those of
+// the map-based helpers.
+type Field struct {
+	Key  string
+	kind fieldKind
+	num  uint64
+	str  string
+	any  any
+}
+
+// Str returns a string field.
+func Str(key, value string) Field {
+	return Field{Key: key, kind: fieldStr, str: value}
+}
+
+// Int returns an int field.
+func Int(key string, value int) Field {
+	return Field{Key: key, kind: fieldInt, num: uint64(value)}
+}
+
+// Int64 returns an int64 field.
+func Int64(key string, value int64) Field {
+	return Field{Key: key, kind: fieldInt, num: uint64(value)}
+}
+
+// Uint64 returns a uint64 field.
+func Uint64(key string, value uint64) Field {
+	return Field{Key: key, kind: fieldUint, num: value}
+}
+
+// Float64 returns a float64 field.
+func Float64(key string, value float64) Field {
+	return Field{Key: key, kind: fieldFloat, num: math.Float64bits(value)}
+}
+
+// Bool returns a bool field.
+func Bool(key string, value bool) Field {
+	var n uint64
+	if value {
+		n = 1
+	}
+	return Field{Key: key, kind: fieldBool, num: n}
+}
+
+// Dur returns a duration field, rendered in the duration format (see
+// SetDurationFormat).
+func Dur(key string, value time.Duration) Field {
+	switch DurationFormat(durationFormat.Load()) {
+	case DurationString:
+		return Str(key, value.String())
+	case DurationNanos:
+		return Int64(key, int64(value))
+	default:
+		return Field{Key: key, kind: fieldDur, num: uint64(value)}
+	}
+}
+
+// minUnixNano and maxUnixNano bound the times Time stores as Unix
+// nanoseconds.
+var (
+	minUnixNano = time.Unix(0, math.MinInt64)
+	maxUnixNano = time.Unix(0, math.MaxInt64)
+)
+
+// Time returns a time field. Times between 1678 and 2262 are stored as
+// Unix nanoseconds and their location, which is not boxed; others are
+// stored as is.
+func Time(key string, value time.Time) Field {
+	if value.Before(minUnixNano) || value.After(maxUnixNano) {
+		return Field{Key: key, kind: fieldTime, any: value}
+	}
+	return Field{Key: key, kind: fieldTime, num: uint64(value.UnixNano()), any: value.Location()}
+}
+
+// Err returns the error field. Nil errors are skipped.
+func Err(err error) Field {
+	return Field{Key: FieldError, kind: fieldErr, any: err}
+}
+
+// RawJSON returns a field embedding raw as JSON (see Logger.WithRawJSON).
+func RawJSON(key string, raw []byte) Field {
+	return Any(key, json.RawMessage(raw))
+}
+
+// Any returns a field of any type, added like the map-based helpers.
+// It is the conversion of every map, slice and struct field value, so
+// values are logged the same at the top level and nested at any depth.
+func Any(key string, value any) Field {
+	switch v := value.(type) {
+	case string:
+		return Str(key, v)
+	case int:
+		return Int64(key, int64(v))
+	case int8:
+		return Int64(key, int64(v))
+	case int16:
+		return Int64(key, int64(v))
+	case int32:
+		return Int64(key, int64(v))
+	case int64:
+		return Int64(key, v)
+	case uint:
+		return Uint64(key, uint64(v))
+	case uint8:
+		return Uint64(key, uint64(v))
+	case uint16:
+		return Uint64(key, uint64(v))
+	case uint32:
+		return Uint64(key, uint64(v))
+	case uint64:
+		return Uint64(key, v)
+	case float32:
+		return Field{Key: key, kind: fieldFloat32, num: uint64(math.Float32bits(v))}
+	case float64:
+		return Float64(key, v)
+	case bool:
+		return Bool(key, v)
+	case error:
+		return Field{Key: key, kind: fieldErr, any: v}
+	case time.Time:
+		return Time(key, v)
+	case time.Duration:
+		return Dur(key, v)
+	case json.RawMessage:
+		// Invalid JSON is logged as a string, so a bad payload cannot
+		// corrupt the entry.
+		if len(v) == 0 || !json.Valid(v) {
+			return Field{Key: key, kind: fieldBytes, any: value}
+		}
+		return Field{Key: key, kind: fieldRawJSON, any: value}
+	case []byte:
+		return Field{Key: key, kind: fieldBytes, any: value}
+	case []string:
+		return Field{Key: key, kind: fieldStrs, any: value}
+	case map[string]any:
+		return Field{Key: key, kind: fieldObject, any: value}
+	case []any:
+		return Field{Key: key, kind: fieldArray, any: value}
+	case net.IP:
+		return Field{Key: key, kind: fieldIP, any: value}
+	case netip.Addr:
+		return Str(key, v.String())
+	case netip.AddrPort:
+		return Str(key, v.String())
+	case netip.Prefix:
+		return Str(key, v.String())
+	case *url.URL:
+		if v == nil {
+			return Field{Key: key, kind: fieldAny}
+		}
+		return Str(key, redactURL(v))
+	case url.URL:
+		return Str(key, redactURL(&v))
+	default:
+		if b, ok := uuidBytes(v); ok {
+			return Str(key, formatUUID(b))
+		}
+		return Field{Key: key, kind: fieldAny, any: value}
+	}
+}
+
+// time returns the value of a time field.
+func (f Field) time() time.Time {
+	if loc, ok := f.any.(*time.Location); ok {
+		return time.Unix(0, int64(f.num)).In(loc)
+	}
+	return f.any.(time.Time)
+}
+
+// bytes returns the value of a raw JSON or bytes field.
+func (f Field) bytes() []byte {
+	if raw, ok := f.any.(json.RawMessage); ok {
+		return raw
+	}
+	return f.any.([]byte)
+}
+
+// addTo adds the field to c.
+func (f Field) addTo(c zerolog.Context) zerolog.Context {
+	switch f.kind {
+	case fieldStr:
+		return c.Str(f.Key, f.str)
+	case fieldInt:
+		return c.Int64(f.Key, int64(f.num))
+	case fieldUint:
+		return c.Uint64(f.Key, f.num)
+	case fieldFloat:
+		return c.Float64(f.Key, math.Float64frombits(f.num))
+	case fieldFloat32:
+		return c.Float32(f.Key, math.Float32frombits(uint32(f.num)))
+	case fieldBool:
+		return c.Bool(f.Key, f.num == 1)
+	case fieldDur:
+		return c.Dur(f.Key, time.Duration(f.num))
+	case fieldTime:
+		return c.Time(f.Key, f.time())
+	case fieldErr:
+		if f.any == nil {
+			return c
+		}
+		return c.AnErr(f.Key, f.any.(error))
+	case fieldRawJSON:
+		return c.RawJSON(f.Key, f.bytes())
+	case fieldBytes:
+		return c.Bytes(f.Key, f.bytes())
+	case fieldStrs:
+		return c.Strs(f.Key, f.any.([]string))
+	case fieldObject:
+		return c.Object(f.Key, objectFields(f.any.(map[string]any)))
+	case fieldArray:
+		return c.Array(f.Key, arrayValues(f.any.([]any)))
+	case fieldIP:
+		return c.IPAddr(f.Key, f.any.(net.IP))
+	default:
+		return c.Interface(f.Key, f.any)
+	}
+}
+
+// WithTyped returns a new Logger with the fields added. It is the typed
+// counterpart of WithFields (With is zerolog's context builder).
+//
+//	log = log.WithTyped(zerowrap.Str(zerowrap.FieldUserID, id), zerowrap.Int(zerowrap.FieldCount, n))
+func (l Logger) WithTyped(fields ...Field) Logger {
+	c := l.With()
+	for _, f := range fields {
+		c = f.addTo(c)
+	}
+	return Logger{c.Logger()}
+}
+
+// FromCtxWith returns the context logger with the fields added.
+//
+//	log := zerowrap.FromCtxWith(ctx, zerowrap.Str(zerowrap.FieldUserID, id), zerowrap.Int(zerowrap.FieldCount, n))
+func FromCtxWith(ctx context.Context, fields ...Field) Logger {
+	c, ok := fromCtxWith(ctx)
+	if !ok {
+		return loggerFromCtx(ctx)
+	}
+	for _, f := range fields {
+		c = f.addTo(c)
+	}
+	return Logger{c.Logger()}
+}
+
+// CtxWith returns a new context with an enriched logger containing the fields.
+func CtxWith(ctx context.Context, fields ...Field) context.Context {
+	log := zerolog.Ctx(ctx)
+	if log.GetLevel() == zerolog.Disabled {
+		return ctx
+	}
+	c := log.With()
+	for _, f := range fields {
+		c = f.addTo(c)
+	}
+	return c.Logger().WithContext(ctx)
+}
@@ -0,0 +1,91 @@
+package zerowrap
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var benchTime = time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+func BenchmarkFromCtxWith(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := FromCtxWith(ctx,
+			Str(FieldRequestID, "r-1"),
+			Int(FieldCount, 42),
+			Dur(FieldDuration, 1250*time.Microsecond),
+			Time("at", benchTime),
+		)
+		l.Info().Msg("request")
+	}
+}
+
+// BenchmarkFromCtxWithFields is the map-based counterpart of
+// BenchmarkFromCtxWith.
+func BenchmarkFromCtxWithFields(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := FromCtxWithFields(ctx, map[string]any{
+			FieldRequestID: "r-1",
+			FieldCount:     42,
+			FieldDuration:  1250 * time.Microsecond,
+			"at":           benchTime,
+		})
+		l.Info().Msg("request")
+	}
+}
+
+// TestFieldAllocs checks typed fields allocate no more than zerolog's own
+// With: only the copy of the logger.
+func TestFieldAllocs(t *testing.T) {
+	ctx := benchCtx()
+	err := errors.New("boom")
+	base := testing.AllocsPerRun(100, func() {
+		sinkLogger = Logger{zerolog.Ctx(ctx).With().
+			Str(FieldRequestID, "r-1").
+			Int(FieldCount, 42).
+			Float64("ratio", 0.5).
+			Bool("ok", true).
+			Time("at", benchTime).
+			AnErr(FieldError, err).
+			Logger()}
+	})
+	got := testing.AllocsPerRun(100, func() {
+		sinkLogger = FromCtxWith(ctx,
+			Str(FieldRequestID, "r-1"),
+			Int(FieldCount, 42),
+			Float64("ratio", 0.5),
+			Bool("ok", true),
+			Time("at", benchTime),
+			Err(err),
+		)
+	})
+	if got > base {
+		t.Errorf("FromCtxWith: %v allocs/op, zerolog With: %v", got, base)
+	}
+}
+
+func TestFieldTime(t *testing.T) {
+	cet := time.FixedZone("CET", 3600)
+	for _, v := range []time.Time{
+		benchTime,
+		benchTime.In(cet),
+		time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(3000, 1, 1, 0, 0, 0, 0, cet),
+	} {
+		var got, want bytes.Buffer
+		gotLog := Logger{zerolog.New(&got)}.WithTyped(Time("at", v))
+		gotLog.Info().Msg("")
+		wantLog := zerolog.New(&want).With().Time("at", v).Logger()
+		wantLog.Info().Msg("")
+		if got.String() != want.String() {
+			t.Errorf("Time(%v) logged %s, want %s", v, got.Bytes(), want.Bytes())
+		}
+	}
+}
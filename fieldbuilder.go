@@ -0,0 +1,101 @@
+package zerowrap
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// FieldsBuilder builds a field map with typed setters, for use with
+// WithFields, CtxWithFields, FromCtxWithFields and WrapErrWithFields.
+type FieldsBuilder struct {
+	m map[string]any
+}
+
+// Fields returns an empty field builder.
+//
+//	log = log.WithFields(zerowrap.Fields().
+//	    Str(zerowrap.FieldUserID, userID).
+//	    Int(zerowrap.FieldCount, len(items)).
+//	    Map())
+func Fields() *FieldsBuilder {
+	return &FieldsBuilder{m: make(map[string]any, 8)}
+}
+
+// Str sets a string field.
+func (b *FieldsBuilder) Str(key, value string) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Int sets an int field.
+func (b *FieldsBuilder) Int(key string, value int) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Int64 sets an int64 field.
+func (b *FieldsBuilder) Int64(key string, value int64) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Uint64 sets a uint64 field.
+func (b *FieldsBuilder) Uint64(key string, value uint64) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Float64 sets a float64 field.
+func (b *FieldsBuilder) Float64(key string, value float64) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Bool sets a bool field.
+func (b *FieldsBuilder) Bool(key string, value bool) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Time sets a time field.
+func (b *FieldsBuilder) Time(key string, value time.Time) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Dur sets a duration field.
+func (b *FieldsBuilder) Dur(key string, value time.Duration) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Strs sets a string slice field.
+func (b *FieldsBuilder) Strs(key string, value []string) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Err sets the error field. Nil errors are skipped.
+func (b *FieldsBuilder) Err(err error) *FieldsBuilder {
+	if err != nil {
+		b.m[FieldError] = err
+	}
+	return b
+}
+
+// RawJSON sets a field embedded as JSON rather than an escaped string.
+func (b *FieldsBuilder) RawJSON(key string, raw []byte) *FieldsBuilder {
+	b.m[key] = json.RawMessage(raw)
+	return b
+}
+
+// Any sets a field of any type.
+func (b *FieldsBuilder) Any(key string, value any) *FieldsBuilder {
+	b.m[key] = value
+	return b
+}
+
+// Map returns the built fields. The builder must not be reused after.
+func (b *FieldsBuilder) Map() map[string]any {
+	return b.m
+}
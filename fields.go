@@ -2,9 +2,11 @@ package zerowrap
 
 import (
 	"context"
+	"encoding/json"
+	"maps"
 	"reflect"
+	"slices"
 	"strings"
-	"time"
 
 	"github.com/rs/zerolog"
 )
@@ -19,28 +21,96 @@ const (
 	FieldCorrelationID = "correlation_id"
 	FieldSessionID     = "session_id"
 	FieldUserID        = "user_id"
+	FieldEmail         = "email"
+	FieldTenantID      = "tenant_id"
 
 	// HTTP/API
 	FieldMethod   = "method"
 	FieldPath     = "path"
 	FieldStatus   = "status"
 	FieldClientIP = "client_ip"
+	FieldRoute    = "route"
+	FieldQueryStr = "query_string"
+	FieldHeaders  = "headers"
+
+	FieldRequestBody  = "request_body"
+	FieldResponseBody = "response_body"
+
+	FieldUABrowser = "ua_browser"
+	FieldUAOS      = "ua_os"
+	FieldUADevice  = "ua_device"
+
+	// Geo (with GeoFields)
+	FieldGeoCountry = "geo_country"
+	FieldGeoCity    = "geo_city"
+	FieldASN        = "asn"
+	FieldASOrg      = "as_org"
 
 	// Service/Infra
-	FieldService = "service"
-	FieldVersion = "version"
-	FieldHost    = "host"
-	FieldEnv     = "env"
+	FieldService     = "service"
+	FieldVersion     = "version"
+	FieldHost        = "host"
+	FieldPID         = "pid"
+	FieldGoVersion   = "go_version"
+	FieldGoroutineID = "goroutine_id"
+
+	FieldModuleVersion = "module_version"
+	FieldVCSRevision   = "vcs_revision"
+	FieldVCSTime       = "vcs_time"
+	FieldVCSModified   = "vcs_modified"
+
+	// Kubernetes
+	FieldK8sPod       = "k8s_pod"
+	FieldK8sNamespace = "k8s_namespace"
+	FieldK8sNode      = "k8s_node"
+	FieldK8sContainer = "k8s_container"
+	FieldEnv          = "env"
 
 	// Operations
-	FieldAction    = "action"
-	FieldOperation = "operation"
-	FieldError     = "error"
-	FieldDuration  = "duration_ms"
+	FieldAction       = "action"
+	FieldOperation    = "operation"
+	FieldError        = "error"
+	FieldDuration     = "duration_ms"
+	FieldDurationText = "duration"    // with DurationString
+	FieldDurationNs   = "duration_ns" // with DurationNanos
+	FieldRetries      = "retries"
+	FieldStack        = "stack"
+	FieldErrorTrace   = "error_trace"
+	FieldSuppressed   = "suppressed"
+	FieldDeadlineIn   = "deadline_in"
+	FieldAttempt      = "attempt"
+	FieldBackoff      = "backoff"
+	FieldChanges      = "changes"
+
+	// Size limits
+	FieldTruncated     = "_truncated"           // with MaxFieldBytes/MaxMessageBytes
+	FieldDroppedFields = "dropped_fields_count" // with MaxEventBytes
+	FieldEventBytes    = "event_bytes"          // with MaxEventBytes
+
+	// Tamper evidence
+	FieldSignature = "_sig" // with SignedWriter
+
+	// Lifecycle
+	FieldSignal = "signal"
+	FieldUptime = "uptime"
+
+	// Circuit breakers
+	FieldBreakerFrom  = "breaker_from"
+	FieldBreakerState = "breaker_state"
+	FieldRequests     = "requests"
+	FieldFailures     = "consecutive_failures"
+
+	// Jobs
+	FieldJob   = "job"
+	FieldRunID = "run_id"
+
+	FieldWorker      = "worker"
+	FieldWorkerIndex = "worker_index"
 
 	// Data
-	FieldCount = "count"
-	FieldSize  = "size_bytes"
+	FieldCount    = "count"
+	FieldSize     = "size_bytes"
+	FieldSizeText = "size" // with SizeHuman
 
 	// Clean Architecture - Layers
 	FieldLayer   = "layer"   // domain, usecase, adapter
@@ -54,9 +124,11 @@ const (
 	FieldGateway     = "gateway"      // external service gateway
 
 	// Database/Storage
-	FieldTable    = "table"
-	FieldQuery    = "query"
-	FieldDatabase = "database"
+	FieldTable     = "table"
+	FieldQuery     = "query"
+	FieldQueryHash = "query_hash" // with AddQuery
+	FieldDatabase  = "database"
+	FieldTxID      = "tx_id"
 
 	// Messaging/Events
 	FieldEvent   = "event"
@@ -71,17 +143,22 @@ const (
 )
 
 // FromCtxWithField returns a logger with one additional field.
+// The context logger is copied once, and not at all when it is disabled.
 func FromCtxWithField(ctx context.Context, key string, value any) Logger {
-	return Logger{addToContext(FromCtx(ctx).With(), key, value).Logger()}
+	c, ok := fromCtxWith(ctx)
+	if !ok {
+		return loggerFromCtx(ctx)
+	}
+	return Logger{addToContext(c, key, value).Logger()}
 }
 
 // FromCtxWithFields returns a logger with multiple additional fields.
 func FromCtxWithFields(ctx context.Context, fields map[string]any) Logger {
-	log := FromCtx(ctx)
-	c := log.With()
-	for k, v := range fields {
-		c = addToContext(c, k, v)
+	c, ok := fromCtxWith(ctx)
+	if !ok {
+		return loggerFromCtx(ctx)
 	}
+	c = addFields(c, fields)
 	return Logger{c.Logger()}
 }
 
@@ -94,84 +171,107 @@ func FromCtxWithStruct(ctx context.Context, s any) Logger {
 }
 
 // CtxWithField returns a new context with an enriched logger containing the field.
+// ctx is returned as is when its logger is disabled.
 func CtxWithField(ctx context.Context, key string, value any) context.Context {
-	log := FromCtxWithField(ctx, key, value)
-	return WithCtx(ctx, log)
+	log := zerolog.Ctx(ctx)
+	if log.GetLevel() == zerolog.Disabled {
+		return ctx
+	}
+	return addToContext(log.With(), key, value).Logger().WithContext(ctx)
 }
 
 // CtxWithFields returns a new context with an enriched logger containing the fields.
+// Extractor fields are not stored, as FromCtx adds them on each call.
 func CtxWithFields(ctx context.Context, fields map[string]any) context.Context {
-	log := FromCtxWithFields(ctx, fields)
-	return WithCtx(ctx, log)
+	log := zerolog.Ctx(ctx)
+	if log.GetLevel() == zerolog.Disabled {
+		return ctx
+	}
+	c := log.With()
+	c = addFields(c, fields)
+	return c.Logger().WithContext(ctx)
 }
 
 // CtxWithStruct returns a new context with an enriched logger containing fields from struct.
 func CtxWithStruct(ctx context.Context, s any) context.Context {
-	log := FromCtxWithStruct(ctx, s)
-	return WithCtx(ctx, log)
+	return CtxWithFields(ctx, extractFields(s))
+}
+
+// addFields adds fields to c sorted by key, so map fields are logged in
+// the same order on every call.
+func addFields(c zerolog.Context, fields map[string]any) zerolog.Context {
+	switch len(fields) {
+	case 0:
+		return c
+	case 1:
+		for k, v := range fields {
+			c = addToContext(c, k, v)
+		}
+		return c
+	}
+	for _, k := range slices.Sorted(maps.Keys(fields)) {
+		c = addToContext(c, k, fields[k])
+	}
+	return c
 }
 
 // addToContext adds a field to the zerolog Context with type-specific methods for efficiency.
 func addToContext(c zerolog.Context, key string, val any) zerolog.Context {
-	switch v := val.(type) {
-	case string:
-		return c.Str(key, v)
-	case int:
-		return c.Int(key, v)
-	case int8:
-		return c.Int8(key, v)
-	case int16:
-		return c.Int16(key, v)
-	case int32:
-		return c.Int32(key, v)
-	case int64:
-		return c.Int64(key, v)
-	case uint:
-		return c.Uint(key, v)
-	case uint8:
-		return c.Uint8(key, v)
-	case uint16:
-		return c.Uint16(key, v)
-	case uint32:
-		return c.Uint32(key, v)
-	case uint64:
-		return c.Uint64(key, v)
-	case float32:
-		return c.Float32(key, v)
-	case float64:
-		return c.Float64(key, v)
-	case bool:
-		return c.Bool(key, v)
-	case error:
-		return c.AnErr(key, v)
-	case time.Time:
-		return c.Time(key, v)
-	case time.Duration:
-		return c.Dur(key, v)
-	case []byte:
-		return c.Bytes(key, v)
-	case []string:
-		return c.Strs(key, v)
-	default:
-		return c.Interface(key, v)
+	return Any(key, val).addTo(c)
+}
+
+// rawJSONToContext embeds raw as JSON, or as a string when it is not
+// valid JSON, so a bad payload cannot corrupt the entry.
+func rawJSONToContext(c zerolog.Context, key string, raw []byte) zerolog.Context {
+	if len(raw) == 0 || !json.Valid(raw) {
+		return c.Bytes(key, raw)
 	}
+	return c.RawJSON(key, raw)
+}
+
+// FieldsProvider is implemented by types that choose their own log
+// fields. WithStruct, FromCtxWithStruct and CtxWithStruct use LogFields
+// instead of reflection, including for nested and inline values, so
+// domain types can log curated fields and keep secrets out.
+//
+//	func (u User) LogFields() map[string]any {
+//	    return map[string]any{zerowrap.FieldUserID: u.ID, "plan": u.Plan}
+//	}
+type FieldsProvider interface {
+	LogFields() map[string]any
 }
 
 // extractFields extracts loggable fields from a struct using reflection.
 // Priority: `log` tag > `json` tag > field name (lowercased with underscores).
+// Struct fields tagged `log:",inline"` or `log:"prefix=db_"` have their
+// own fields merged at the top level, the latter with the prefix added.
+// Nested values are bounded by the struct limits (see SetStructLimits).
+// Values implementing FieldsProvider provide their own fields instead.
 func extractFields(s any) map[string]any {
+	v := reflect.ValueOf(s)
+	if p, ok := s.(FieldsProvider); ok && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		return p.LogFields()
+	}
 	fields := make(map[string]any)
+	e := &structExtractor{limits: currentStructLimits()}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		e.path = append(e.path, v.Pointer())
+	}
+	e.structFields(fields, v, "", 0)
+	return fields
+}
 
-	v := reflect.ValueOf(s)
+// structFields adds the fields of the struct v to fields, prefixing names.
+func (e *structExtractor) structFields(fields map[string]any, v reflect.Value, prefix string, depth int) {
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			return fields
+			return
 		}
 		v = v.Elem()
 	}
 
 	if v.Kind() != reflect.Struct {
-		return fields
+		return
 	}
 
 	t := v.Type()
@@ -184,7 +284,7 @@ func extractFields(s any) map[string]any {
 		}
 
 		// Determine field name from tags
-		name := field.Tag.Get("log")
+		name, inline, fieldPrefix := parseLogTag(field.Tag.Get("log"))
 		if name == "" {
 			jsonTag := field.Tag.Get("json")
 			if jsonTag != "" {
@@ -201,16 +301,90 @@ func extractFields(s any) map[string]any {
 		}
 
 		fieldVal := v.Field(i)
+		if inline && fieldVal.Kind() == reflect.Interface && !fieldVal.IsNil() {
+			// Inline the value held by an interface.
+			fieldVal = fieldVal.Elem()
+		}
 
 		// Skip zero values for pointers and interfaces
 		if (fieldVal.Kind() == reflect.Ptr || fieldVal.Kind() == reflect.Interface) && fieldVal.IsNil() {
 			continue
 		}
 
-		fields[name] = fieldVal.Interface()
+		if inline && inlinable(fieldVal) {
+			if p, ok := fieldVal.Interface().(FieldsProvider); ok {
+				if !e.mergeFields(fields, p.LogFields(), prefix+fieldPrefix) {
+					return
+				}
+				continue
+			}
+			if depth < e.limits.MaxDepth && !e.onPath(fieldVal) {
+				e.structFields(fields, fieldVal, prefix+fieldPrefix, depth+1)
+			}
+			if len(fields) > e.limits.MaxFields {
+				return
+			}
+			continue
+		}
+
+		if len(fields) >= e.limits.MaxFields {
+			fields[FieldTruncated] = true
+			return
+		}
+		fields[prefix+name] = e.value(fieldVal, depth+1)
 	}
+}
 
-	return fields
+// mergeFields adds the fields of an inline FieldsProvider to fields,
+// prefixing names, in name order up to MaxFields. It reports false when
+// fields is full and marked truncated.
+func (e *structExtractor) mergeFields(fields, provided map[string]any, prefix string) bool {
+	for _, k := range slices.Sorted(maps.Keys(provided)) {
+		if len(fields) >= e.limits.MaxFields {
+			fields[FieldTruncated] = true
+			return false
+		}
+		fields[prefix+k] = provided[k]
+	}
+	return true
+}
+
+// inlinable reports whether the fields of v can be merged into its
+// parent: a FieldsProvider, or a struct walked field by field. Interface
+// fields are passed with the value they hold. Other values tagged inline
+// or with a prefix are logged under their name.
+func inlinable(v reflect.Value) bool {
+	if v.Type().Implements(fieldsProviderType) {
+		return true
+	}
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+	return v.Kind() == reflect.Struct && !keepValue(v.Type())
+}
+
+// onPath reports whether v is a pointer already being walked.
+func (e *structExtractor) onPath(v reflect.Value) bool {
+	return v.Kind() == reflect.Ptr && slices.Contains(e.path, v.Pointer())
+}
+
+// parseLogTag splits a `log` tag into the field name and its options:
+// "inline", or "prefix=..." which implies inline. A tag made of options
+// only, such as "prefix=db_", has no name. The options only apply to
+// struct and FieldsProvider values (see inlinable).
+func parseLogTag(tag string) (name string, inline bool, prefix string) {
+	for i, part := range strings.Split(tag, ",") {
+		switch {
+		case part == "inline":
+			inline = true
+		case strings.HasPrefix(part, "prefix="):
+			inline = true
+			prefix = strings.TrimPrefix(part, "prefix=")
+		case i == 0:
+			name = part
+		}
+	}
+	return name, inline, prefix
 }
 
 // toSnakeCase converts PascalCase/camelCase to snake_case.
@@ -0,0 +1,204 @@
+package zerowrap
+
+import (
+	"context"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// benchCtx returns a context holding a logger writing to io.Discard.
+func benchCtx() context.Context {
+	log := zerolog.New(io.Discard).With().Timestamp().Logger()
+	return log.WithContext(context.Background())
+}
+
+// BenchmarkZerologWith is the baseline of the context field helpers: the
+// ctx logger copied with one field by zerolog alone.
+func BenchmarkZerologWith(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := zerolog.Ctx(ctx).With().Str(FieldRequestID, "r-1").Logger()
+		l.Info().Msg("request")
+	}
+}
+
+func BenchmarkFromCtxWithField(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		l := FromCtxWithField(ctx, FieldRequestID, "r-1")
+		l.Info().Msg("request")
+	}
+}
+
+// BenchmarkZerologWithContext is the baseline of CtxWithField: the ctx
+// logger copied with one field and stored in a new context.
+func BenchmarkZerologWithContext(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := zerolog.Ctx(ctx).With().Str(FieldRequestID, "r-1").Logger().WithContext(ctx)
+		zerolog.Ctx(c).Info().Msg("request")
+	}
+}
+
+func BenchmarkCtxWithField(b *testing.B) {
+	ctx := benchCtx()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		c := CtxWithField(ctx, FieldRequestID, "r-1")
+		zerolog.Ctx(c).Info().Msg("request")
+	}
+}
+
+// sinkLogger and sinkCtx keep the loggers built by TestCtxFieldAllocs on
+// the heap, as they are in real callers.
+var (
+	sinkLogger Logger
+	sinkCtx    context.Context
+)
+
+// TestCtxFieldAllocs checks the context field helpers allocate no more
+// than zerolog's own With for the common value types.
+func TestCtxFieldAllocs(t *testing.T) {
+	ctx := benchCtx()
+	tests := []struct {
+		name  string
+		value any
+		with  func(c zerolog.Context) zerolog.Context
+	}{
+		{"string", "r-1", func(c zerolog.Context) zerolog.Context { return c.Str(FieldRequestID, "r-1") }},
+		{"int", 42, func(c zerolog.Context) zerolog.Context { return c.Int(FieldRequestID, 42) }},
+		{"bool", true, func(c zerolog.Context) zerolog.Context { return c.Bool(FieldRequestID, true) }},
+		{"float64", 1.5, func(c zerolog.Context) zerolog.Context { return c.Float64(FieldRequestID, 1.5) }},
+	}
+	for _, tt := range tests {
+		base := testing.AllocsPerRun(100, func() {
+			sinkLogger = Logger{tt.with(zerolog.Ctx(ctx).With()).Logger()}
+		})
+		got := testing.AllocsPerRun(100, func() {
+			sinkLogger = FromCtxWithField(ctx, FieldRequestID, tt.value)
+		})
+		if got > base {
+			t.Errorf("FromCtxWithField(%s): %v allocs/op, zerolog With: %v", tt.name, got, base)
+		}
+
+		base = testing.AllocsPerRun(100, func() {
+			sinkCtx = tt.with(zerolog.Ctx(ctx).With()).Logger().WithContext(ctx)
+		})
+		got = testing.AllocsPerRun(100, func() {
+			sinkCtx = CtxWithField(ctx, FieldRequestID, tt.value)
+		})
+		if got > base {
+			t.Errorf("CtxWithField(%s): %v allocs/op, zerolog With: %v", tt.name, got, base)
+		}
+	}
+}
+
+// testProvider is a FieldsProvider with n fields, f0 to f(n-1).
+type testProvider int
+
+func (p testProvider) LogFields() map[string]any {
+	fields := make(map[string]any, int(p))
+	for i := range int(p) {
+		fields["f"+strconv.Itoa(i)] = i
+	}
+	return fields
+}
+
+func TestExtractFieldsPrefixOnNonStruct(t *testing.T) {
+	type conn struct {
+		Host string `log:"host"`
+	}
+	type request struct {
+		Name  string         `log:"name,prefix=db_"`
+		Count int            `log:",inline"`
+		DB    conn           `log:"prefix=db_"`
+		Tags  map[string]int `log:"prefix=tag_"`
+		When  time.Time      `log:"when,inline"`
+	}
+	when := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	got := extractFields(request{Name: "orders", Count: 2, DB: conn{Host: "pg"}, Tags: map[string]int{"a": 1}, When: when})
+
+	want := map[string]any{
+		"name":    "orders",
+		"count":   2,
+		"db_host": "pg",
+		"tags":    map[string]any{"a": 1},
+		"when":    when,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFields = %v, want %v", got, want)
+	}
+}
+
+func TestExtractFieldsInlineInterface(t *testing.T) {
+	type conn struct {
+		Host string `log:"host"`
+	}
+	type request struct {
+		DB    any `log:"prefix=db_"`
+		Peer  any `log:"prefix=peer_"`
+		Extra any `log:",inline"`
+		None  any `log:",inline"`
+	}
+	got := extractFields(request{DB: conn{Host: "pg"}, Peer: &conn{Host: "api"}, Extra: testProvider(1)})
+
+	want := map[string]any{
+		"db_host":   "pg",
+		"peer_host": "api",
+		"f0":        0,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFields = %v, want %v", got, want)
+	}
+}
+
+func TestExtractFieldsInlineProviderLimit(t *testing.T) {
+	SetStructLimits(StructLimits{MaxFields: 5})
+	defer SetStructLimits(StructLimits{})
+
+	type request struct {
+		ID    string       `log:"id"`
+		Extra testProvider `log:",inline"`
+		After string       `log:"after"`
+	}
+	got := extractFields(request{ID: "r-1", Extra: 10, After: "x"})
+
+	want := map[string]any{
+		"id":           "r-1",
+		"f0":           0,
+		"f1":           1,
+		"f2":           2,
+		"f3":           3,
+		FieldTruncated: true,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFields = %v, want %v", got, want)
+	}
+}
+
+func TestExtractFieldsSliceLen(t *testing.T) {
+	SetStructLimits(StructLimits{MaxSliceLen: 2})
+	defer SetStructLimits(StructLimits{})
+
+	type request struct {
+		IDs    []int          `log:"ids"`
+		Counts map[string]int `log:"counts"`
+	}
+	got := extractFields(request{IDs: []int{1, 2, 3, 4}, Counts: map[string]int{"c": 3, "a": 1, "b": 2}})
+
+	want := map[string]any{
+		"ids":    []any{1, 2, "... 2 more"},
+		"counts": map[string]any{"a": 1, "b": 2, FieldTruncated: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("extractFields = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,364 @@
+package zerowrap
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// FluentConfig holds configuration for Fluentd forward protocol output.
+type FluentConfig struct {
+	// Network is "tcp" or "unix".
+	// Defaults to "tcp" if empty.
+	Network string
+
+	// Addr is the fluentd or fluent-bit forward input address.
+	// Defaults to "127.0.0.1:24224" if empty.
+	Addr string
+
+	// Tag is the fluentd tag of each entry, used for routing.
+	Tag string
+
+	// RequireAck waits for the server to acknowledge each entry (the
+	// forward protocol's at-least-once mode) and fails the write otherwise.
+	RequireAck bool
+
+	// Timeout bounds connection, write and acknowledgement.
+	// Defaults to 5 seconds if 0.
+	Timeout time.Duration
+}
+
+// FluentWriter is an io.Writer that sends entries to fluentd or fluent-bit
+// using the forward protocol (msgpack over TCP). Each entry is sent
+// synchronously; wrap it with zerolog's diode writer to log asynchronously.
+type FluentWriter struct {
+	cfg FluentConfig
+
+	mu     sync.Mutex
+	conn   net.Conn
+	r      *bufio.Reader
+	closed bool
+}
+
+// NewFluentWriter connects to a forward input.
+//
+//	fluent, err := zerowrap.NewFluentWriter(zerowrap.FluentConfig{
+//	    Addr:       "fluentd.internal:24224",
+//	    Tag:        "app.billing",
+//	    RequireAck: true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer fluent.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: fluent})
+func NewFluentWriter(cfg FluentConfig) (*FluentWriter, error) {
+	if cfg.Tag == "" {
+		return nil, errors.New("zerowrap: fluent tag is required")
+	}
+	if cfg.Network == "" {
+		cfg.Network = "tcp"
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:24224"
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Second
+	}
+
+	w := &FluentWriter{cfg: cfg}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// connect dials the forward input.
+func (w *FluentWriter) connect() error {
+	conn, err := net.DialTimeout(w.cfg.Network, w.cfg.Addr, w.cfg.Timeout)
+	if err != nil {
+		return fmt.Errorf("zerowrap: fluent: %w", err)
+	}
+	w.conn = conn
+	w.r = bufio.NewReader(conn)
+	return nil
+}
+
+// Write implements io.Writer. The entry is sent in the forward protocol's
+// message mode, with the entry time as event time and its fields as the
+// record. Writes after Close fail with net.ErrClosed.
+func (w *FluentWriter) Write(p []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var record map[string]any
+	if err := dec.Decode(&record); err != nil {
+		record = map[string]any{zerolog.MessageFieldName: string(bytes.TrimRight(p, "\n"))}
+	}
+
+	ts := time.Now()
+	if s, ok := record[zerolog.TimestampFieldName].(string); ok {
+		if t, err := time.Parse(zerolog.TimeFieldFormat, s); err == nil {
+			ts = t
+		}
+	}
+
+	var chunk string
+	if w.cfg.RequireAck {
+		chunk = NewID()
+	}
+	msg := encodeFluentMessage(w.cfg.Tag, ts, record, chunk)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, net.ErrClosed
+	}
+
+	if err := w.send(msg, chunk); err != nil {
+		// Reconnect once, e.g. after an aggregator restart.
+		if w.conn != nil {
+			_ = w.conn.Close()
+			w.conn = nil
+		}
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+		if err := w.send(msg, chunk); err != nil {
+			return 0, fmt.Errorf("zerowrap: fluent: %w", err)
+		}
+	}
+	return len(p), nil
+}
+
+// send writes msg and waits for its acknowledgement if chunk is set.
+func (w *FluentWriter) send(msg []byte, chunk string) error {
+	if w.conn == nil {
+		return net.ErrClosed
+	}
+	_ = w.conn.SetDeadline(time.Now().Add(w.cfg.Timeout))
+	if _, err := w.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+	resp, err := decodeMsgpackStringMap(w.r)
+	if err != nil {
+		return err
+	}
+	if resp["ack"] != chunk {
+		return errors.New("unexpected acknowledgement")
+	}
+	return nil
+}
+
+// Close closes the connection. Later writes are not sent.
+func (w *FluentWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// encodeFluentMessage encodes [tag, time, record, option] in msgpack.
+func encodeFluentMessage(tag string, ts time.Time, record map[string]any, chunk string) []byte {
+	var buf bytes.Buffer
+	n := 3
+	if chunk != "" {
+		n = 4
+	}
+	buf.WriteByte(0x90 | byte(n)) // fixarray
+	msgpackString(&buf, tag)
+
+	// EventTime: fixext8 of type 0 holding seconds and nanoseconds.
+	buf.WriteByte(0xd7)
+	buf.WriteByte(0x00)
+	_ = binary.Write(&buf, binary.BigEndian, uint32(ts.Unix()))
+	_ = binary.Write(&buf, binary.BigEndian, uint32(ts.Nanosecond()))
+
+	msgpackValue(&buf, record)
+	if chunk != "" {
+		msgpackValue(&buf, map[string]any{"chunk": chunk})
+	}
+	return buf.Bytes()
+}
+
+// msgpackValue appends the msgpack encoding of a decoded JSON value.
+func msgpackValue(buf *bytes.Buffer, v any) {
+	switch v := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if v {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case string:
+		msgpackString(buf, v)
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			msgpackInt(buf, i)
+		} else {
+			f, _ := v.Float64()
+			msgpackFloat(buf, f)
+		}
+	case int64:
+		msgpackInt(buf, v)
+	case float64:
+		msgpackFloat(buf, v)
+	case []any:
+		msgpackHeader(buf, len(v), 0x90, 0xdc, 0xdd)
+		for _, e := range v {
+			msgpackValue(buf, e)
+		}
+	case map[string]any:
+		msgpackHeader(buf, len(v), 0x80, 0xde, 0xdf)
+		for k, e := range v {
+			msgpackString(buf, k)
+			msgpackValue(buf, e)
+		}
+	default:
+		msgpackString(buf, fmt.Sprint(v))
+	}
+}
+
+// msgpackHeader appends an array or map header: fix for fewer than 16
+// elements, then 16-bit and 32-bit lengths.
+func msgpackHeader(buf *bytes.Buffer, n int, fix, b16, b32 byte) {
+	switch {
+	case n < 16:
+		buf.WriteByte(fix | byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(b16)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(b32)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+}
+
+// msgpackString appends a str value.
+func msgpackString(buf *bytes.Buffer, s string) {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf.WriteByte(0xa0 | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(0xd9)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(0xda)
+		_ = binary.Write(buf, binary.BigEndian, uint16(n))
+	default:
+		buf.WriteByte(0xdb)
+		_ = binary.Write(buf, binary.BigEndian, uint32(n))
+	}
+	buf.WriteString(s)
+}
+
+// msgpackInt appends an integer as fixint or int64.
+func msgpackInt(buf *bytes.Buffer, i int64) {
+	if i >= -32 && i < 128 {
+		buf.WriteByte(byte(int8(i)))
+		return
+	}
+	buf.WriteByte(0xd3)
+	_ = binary.Write(buf, binary.BigEndian, i)
+}
+
+// msgpackFloat appends a float64.
+func msgpackFloat(buf *bytes.Buffer, f float64) {
+	buf.WriteByte(0xcb)
+	_ = binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+}
+
+// decodeMsgpackStringMap reads a msgpack map with string keys, keeping
+// string values only, as sent in forward protocol acknowledgements.
+func decodeMsgpackStringMap(r *bufio.Reader) (map[string]string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	var n int
+	switch {
+	case b&0xf0 == 0x80:
+		n = int(b & 0x0f)
+	case b == 0xde:
+		var l uint16
+		if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+			return nil, err
+		}
+		n = int(l)
+	default:
+		return nil, fmt.Errorf("unexpected msgpack type 0x%x", b)
+	}
+
+	m := make(map[string]string, n)
+	for range n {
+		k, err := decodeMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+		v, err := decodeMsgpackString(r)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = v
+	}
+	return m, nil
+}
+
+// decodeMsgpackString reads a msgpack str value.
+func decodeMsgpackString(r *bufio.Reader) (string, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return "", err
+	}
+	var n int
+	switch {
+	case b&0xe0 == 0xa0:
+		n = int(b & 0x1f)
+	case b == 0xd9:
+		l, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		n = int(l)
+	case b == 0xda:
+		var l uint16
+		if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+			return "", err
+		}
+		n = int(l)
+	case b == 0xdb:
+		var l uint32
+		if err := binary.Read(r, binary.BigEndian, &l); err != nil {
+			return "", err
+		}
+		n = int(l)
+	default:
+		return "", fmt.Errorf("unexpected msgpack type 0x%x", b)
+	}
+	s := make([]byte, n)
+	if _, err := io.ReadFull(r, s); err != nil {
+		return "", err
+	}
+	return string(s), nil
+}
@@ -0,0 +1,28 @@
+// Package gatewaylog provides grpc-gateway request logging for zerowrap.
+//
+// This is an optional sub-package that adds the grpc-gateway dependency.
+// Its middleware wraps httplog.Middleware and resolves route templates
+// from the gateway's google.api.http annotations, so logs carry
+// "/v1/users/{id=*}" rather than "/v1/users/42". Metadata forwards the
+// route and the request IDs to the gRPC server, where the grpclog
+// interceptors log them next to grpc_method.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap/gatewaylog"
+//	    "github.com/bnema/zerowrap/grpclog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	    "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+//	)
+//
+//	mux := runtime.NewServeMux(
+//	    runtime.WithMiddlewares(gatewaylog.Middleware(httplog.Options{})),
+//	    runtime.WithMetadata(gatewaylog.Metadata),
+//	)
+//
+//	// gRPC side: entries carry route, grpc_method and the gateway's request_id
+//	srv := grpc.NewServer(grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(grpclog.Options{})))
+//
+// The server's base context must carry the logger, as with httplog.
+package gatewaylog
@@ -0,0 +1,60 @@
+package gatewaylog
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/grpclog"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/metadata"
+)
+
+// Middleware returns grpc-gateway request logging middleware, for
+// runtime.WithMiddlewares. It behaves like httplog.Middleware, but logs
+// the matched google.api.http path template (e.g. "/v1/users/{id=*}") as
+// the route field. A RoutePattern set in opts takes precedence.
+//
+//	mux := runtime.NewServeMux(
+//	    runtime.WithMiddlewares(gatewaylog.Middleware(httplog.Options{})),
+//	    runtime.WithMetadata(gatewaylog.Metadata),
+//	)
+func Middleware(opts httplog.Options) runtime.Middleware {
+	if opts.RoutePattern == nil {
+		opts.RoutePattern = RoutePattern
+	}
+	mw := httplog.Middleware(opts)
+	return func(next runtime.HandlerFunc) runtime.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+			mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				next(w, r, pathParams)
+			})).ServeHTTP(w, r)
+		}
+	}
+}
+
+// RoutePattern returns the gateway path template matched for r.
+// Returns an empty string if r was not routed by a gateway ServeMux.
+func RoutePattern(r *http.Request) string {
+	p, ok := runtime.HTTPPattern(r.Context())
+	if !ok {
+		return ""
+	}
+	return p.String()
+}
+
+// Metadata returns the gRPC metadata forwarding the route template and
+// the request and correlation IDs of r to the gRPC server, for
+// runtime.WithMetadata. The grpclog and connectlog interceptors then log
+// the route next to grpc_method, under the same request_id.
+func Metadata(ctx context.Context, r *http.Request) metadata.MD {
+	md := metadata.MD{}
+	if route := RoutePattern(r); route != "" {
+		md.Set(grpclog.MetadataHTTPRoute, route)
+	}
+	zerowrap.Inject(ctx, func(k, v string) {
+		md.Set(k, v)
+	})
+	return md
+}
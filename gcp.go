@@ -0,0 +1,143 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// gcpSeverities maps zerolog level names to Cloud Logging severities.
+var gcpSeverities = map[string]string{
+	zerolog.LevelTraceValue: "DEBUG",
+	zerolog.LevelDebugValue: "DEBUG",
+	zerolog.LevelInfoValue:  "INFO",
+	zerolog.LevelWarnValue:  "WARNING",
+	zerolog.LevelErrorValue: "ERROR",
+	zerolog.LevelFatalValue: "CRITICAL",
+	zerolog.LevelPanicValue: "ALERT",
+}
+
+// gcpHTTPFields maps request fields to Cloud Logging httpRequest fields.
+var gcpHTTPFields = map[string]string{
+	FieldMethod:   "requestMethod",
+	FieldPath:     "requestUrl",
+	FieldStatus:   "status",
+	FieldClientIP: "remoteIp",
+	FieldSize:     "responseSize",
+}
+
+// gcpWriter rewrites JSON entries into the structured format Cloud Logging
+// parses from stdout on GKE, Cloud Run and Cloud Functions.
+type gcpWriter struct {
+	out     io.Writer
+	project string
+}
+
+// newGCPWriter returns a writer mapping entries for Cloud Logging.
+// If project is empty, it is read from GOOGLE_CLOUD_PROJECT.
+func newGCPWriter(out io.Writer, project string) io.Writer {
+	if project == "" {
+		project = os.Getenv("GOOGLE_CLOUD_PROJECT")
+	}
+	return gcpWriter{out: out, project: project}
+}
+
+// Write implements io.Writer. Entries that are not JSON objects are
+// written unchanged.
+func (w gcpWriter) Write(p []byte) (int, error) {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var entry map[string]any
+	if err := dec.Decode(&entry); err != nil {
+		return w.out.Write(p)
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(w.mapEntry(entry)); err != nil {
+		return w.out.Write(p)
+	}
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// mapEntry renames fields to the special fields Cloud Logging recognizes:
+// severity, logging.googleapis.com/trace and spanId, sourceLocation and
+// httpRequest. Other fields are kept as jsonPayload fields.
+func (w gcpWriter) mapEntry(entry map[string]any) map[string]any {
+	out := make(map[string]any, len(entry))
+	httpRequest := make(map[string]any)
+
+	for key, val := range entry {
+		switch key {
+		case zerolog.LevelFieldName:
+			if s, ok := gcpSeverities[toString(val)]; ok {
+				out["severity"] = s
+			} else {
+				out["severity"] = "DEFAULT"
+			}
+		case zerolog.MessageFieldName:
+			out["message"] = val
+		case FieldTraceID:
+			trace := toString(val)
+			if w.project != "" {
+				trace = "projects/" + w.project + "/traces/" + trace
+			}
+			out["logging.googleapis.com/trace"] = trace
+		case FieldSpanID:
+			out["logging.googleapis.com/spanId"] = val
+		case zerolog.CallerFieldName:
+			out["logging.googleapis.com/sourceLocation"] = gcpSourceLocation(toString(val))
+		case FieldDuration, FieldDurationText, FieldDurationNs:
+			if d, ok := entryDuration(key, val); ok {
+				httpRequest["latency"] = strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+			}
+			out[key] = val
+		default:
+			if name, ok := gcpHTTPFields[key]; ok {
+				if key == FieldStatus || key == FieldSize {
+					httpRequest[name] = val
+				} else {
+					httpRequest[name] = toString(val)
+				}
+			}
+			out[key] = val
+		}
+	}
+
+	if _, ok := httpRequest["requestMethod"]; ok {
+		out["httpRequest"] = httpRequest
+	}
+	return out
+}
+
+// gcpSourceLocation splits a "file:line" caller into a sourceLocation.
+func gcpSourceLocation(caller string) map[string]any {
+	loc := map[string]any{"file": caller}
+	if i := strings.LastIndexByte(caller, ':'); i > 0 {
+		loc["file"] = caller[:i]
+		loc["line"] = caller[i+1:]
+	}
+	return loc
+}
+
+// toString returns v as a string without quoting.
+func toString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		b, _ := json.Marshal(v)
+		return string(b)
+	}
+}
@@ -0,0 +1,304 @@
+package zerowrap
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// GELF compression modes for GELFConfig.Compression.
+const (
+	GELFCompressGzip = "gzip"
+	GELFCompressZlib = "zlib"
+	GELFCompressNone = "none"
+)
+
+// gelfMaxChunks is the maximum number of chunks per GELF UDP message.
+const gelfMaxChunks = 128
+
+// GELFConfig holds configuration for GELF (Graylog) output.
+type GELFConfig struct {
+	// Network is "udp" or "tcp".
+	// Defaults to "udp" if empty.
+	Network string
+
+	// Addr is the Graylog input address, e.g. "graylog.internal:12201".
+	Addr string
+
+	// Host is the GELF host field.
+	// Defaults to os.Hostname if empty.
+	Host string
+
+	// Compression is the UDP payload compression: "gzip", "zlib" or "none".
+	// Defaults to "gzip" if empty. TCP messages are never compressed, as
+	// GELF TCP framing does not support it.
+	Compression string
+
+	// ChunkSize is the maximum UDP datagram size; larger messages are
+	// split into GELF chunks.
+	// Defaults to 1420 if 0.
+	ChunkSize int
+}
+
+// GELFWriter is a zerolog.LevelWriter that encodes entries as GELF 1.1
+// and sends them to Graylog over UDP (chunked, compressed) or TCP.
+type GELFWriter struct {
+	cfg  GELFConfig
+	host string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewGELFWriter connects to a Graylog GELF input.
+//
+//	gelf, err := zerowrap.NewGELFWriter(zerowrap.GELFConfig{
+//	    Addr: "graylog.internal:12201",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer gelf.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: gelf})
+func NewGELFWriter(cfg GELFConfig) (*GELFWriter, error) {
+	if cfg.Addr == "" {
+		return nil, errors.New("zerowrap: gelf address is required")
+	}
+	cfg.Network = strings.ToLower(cfg.Network)
+	if cfg.Network == "" {
+		cfg.Network = "udp"
+	}
+	if cfg.Network != "udp" && cfg.Network != "tcp" {
+		return nil, fmt.Errorf("zerowrap: unsupported gelf network %q", cfg.Network)
+	}
+	cfg.Compression = strings.ToLower(cfg.Compression)
+	switch cfg.Compression {
+	case "":
+		cfg.Compression = GELFCompressGzip
+	case GELFCompressGzip, GELFCompressZlib, GELFCompressNone:
+	default:
+		return nil, fmt.Errorf("zerowrap: unsupported gelf compression %q", cfg.Compression)
+	}
+	if cfg.ChunkSize == 0 {
+		cfg.ChunkSize = 1420
+	}
+	host := cfg.Host
+	if host == "" {
+		host, _ = os.Hostname()
+	}
+
+	conn, err := net.Dial(cfg.Network, cfg.Addr)
+	if err != nil {
+		return nil, fmt.Errorf("zerowrap: gelf: %w", err)
+	}
+	return &GELFWriter{cfg: cfg, host: host, conn: conn}, nil
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (w *GELFWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (w *GELFWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg, err := json.Marshal(w.message(level, p))
+	if err != nil {
+		return 0, err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return 0, net.ErrClosed
+	}
+
+	if w.cfg.Network == "tcp" {
+		if err := w.sendTCP(msg); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if err := w.sendUDP(msg); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// message converts a JSON entry to a GELF message. Fields become
+// additional fields prefixed with an underscore.
+func (w *GELFWriter) message(level zerolog.Level, p []byte) map[string]any {
+	msg := map[string]any{
+		"version": "1.1",
+		"host":    w.host,
+		"level":   syslogSeverity(level),
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		msg["short_message"] = string(bytes.TrimRight(p, "\n"))
+		msg["timestamp"] = gelfTimestamp(time.Now())
+		return msg
+	}
+
+	ts := time.Now()
+	for key, val := range fields {
+		switch key {
+		case zerolog.LevelFieldName:
+		case zerolog.MessageFieldName:
+			msg["short_message"] = fmt.Sprint(val)
+		case zerolog.TimestampFieldName:
+			if s, ok := val.(string); ok {
+				if t, err := time.Parse(zerolog.TimeFieldFormat, s); err == nil {
+					ts = t
+				}
+			}
+		default:
+			msg[gelfKey(key)] = gelfValue(val)
+		}
+	}
+	if _, ok := msg["short_message"]; !ok {
+		msg["short_message"] = "-"
+	}
+	msg["timestamp"] = gelfTimestamp(ts)
+	return msg
+}
+
+// sendTCP writes a null-terminated message, reconnecting once on failure.
+func (w *GELFWriter) sendTCP(msg []byte) error {
+	frame := append(msg, 0)
+	if _, err := w.conn.Write(frame); err == nil {
+		return nil
+	}
+	_ = w.conn.Close()
+	conn, err := net.Dial(w.cfg.Network, w.cfg.Addr)
+	if err != nil {
+		w.conn = nil
+		return fmt.Errorf("zerowrap: gelf: %w", err)
+	}
+	w.conn = conn
+	_, err = w.conn.Write(frame)
+	return err
+}
+
+// sendUDP compresses msg and sends it, split into chunks if needed.
+func (w *GELFWriter) sendUDP(msg []byte) error {
+	payload, err := w.compress(msg)
+	if err != nil {
+		return err
+	}
+	if len(payload) <= w.cfg.ChunkSize {
+		_, err := w.conn.Write(payload)
+		return err
+	}
+
+	// Chunk header: magic (2), message ID (8), sequence (1), count (1).
+	const headerSize = 12
+	size := w.cfg.ChunkSize - headerSize
+	count := (len(payload) + size - 1) / size
+	if count > gelfMaxChunks {
+		return fmt.Errorf("zerowrap: gelf message too large (%d chunks)", count)
+	}
+
+	var id [8]byte
+	_, _ = rand.Read(id[:])
+	chunk := make([]byte, 0, w.cfg.ChunkSize)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*size, len(payload))
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, payload[i*size:end]...)
+		if _, err := w.conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compress applies the configured UDP compression.
+func (w *GELFWriter) compress(msg []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	var zw io.WriteCloser
+	switch w.cfg.Compression {
+	case GELFCompressNone:
+		return msg, nil
+	case GELFCompressZlib:
+		zw = zlib.NewWriter(&buf)
+	default:
+		zw = gzip.NewWriter(&buf)
+	}
+	if _, err := zw.Write(msg); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Close closes the connection to Graylog.
+func (w *GELFWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// gelfKey converts a field name to a GELF additional field name:
+// underscore-prefixed, limited to letters, digits, underscores, dots and
+// dashes. The reserved "_id" becomes "_id_".
+func gelfKey(key string) string {
+	key = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			return r
+		default:
+			return '_'
+		}
+	}, key)
+	if key == "id" {
+		return "_id_"
+	}
+	return "_" + key
+}
+
+// gelfValue converts a decoded JSON value to a GELF field value.
+// GELF only allows strings and numbers, so other values are encoded as
+// JSON strings.
+func gelfValue(v any) any {
+	switch v := v.(type) {
+	case string, json.Number:
+		return v
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
+
+// gelfTimestamp returns t as Unix seconds with millisecond precision.
+func gelfTimestamp(t time.Time) float64 {
+	return float64(t.UnixMilli()) / 1000
+}
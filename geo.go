@@ -0,0 +1,98 @@
+package zerowrap
+
+import (
+	"context"
+	"net/netip"
+)
+
+// GeoInfo is the geographic and network context of an IP address.
+// Empty values are not logged.
+type GeoInfo struct {
+	// Country is the ISO 3166-1 alpha-2 country code, e.g. "FR".
+	Country string
+
+	// City is the city name.
+	City string
+
+	// ASN is the autonomous system number.
+	ASN uint
+
+	// ASOrg is the organization of the autonomous system.
+	ASOrg string
+}
+
+// GeoResolver resolves the geo context of an IP address, typically from
+// a MaxMind GeoLite2/GeoIP2 database the application opens itself, so
+// zerowrap does not depend on a database reader:
+//
+//	type geoDB struct{ city, asn *geoip2.Reader }
+//
+//	func (g geoDB) LookupGeo(ip netip.Addr) (zerowrap.GeoInfo, error) {
+//	    var info zerowrap.GeoInfo
+//	    if c, err := g.city.City(ip.AsSlice()); err == nil {
+//	        info.Country, info.City = c.Country.IsoCode, c.City.Names["en"]
+//	    }
+//	    if a, err := g.asn.ASN(ip.AsSlice()); err == nil {
+//	        info.ASN, info.ASOrg = a.AutonomousSystemNumber, a.AutonomousSystemOrganization
+//	    }
+//	    return info, nil
+//	}
+type GeoResolver interface {
+	LookupGeo(ip netip.Addr) (GeoInfo, error)
+}
+
+// GeoResolverFunc adapts a function to a GeoResolver.
+type GeoResolverFunc func(ip netip.Addr) (GeoInfo, error)
+
+// LookupGeo implements GeoResolver.
+func (f GeoResolverFunc) LookupGeo(ip netip.Addr) (GeoInfo, error) {
+	return f(ip)
+}
+
+// GeoFields returns the geo fields (geo_country, geo_city, asn, as_org)
+// of ip resolved with r. It returns nil if r is nil, ip is not a public
+// address (loopback, private, link-local) or the lookup fails.
+func GeoFields(r GeoResolver, ip string) []Field {
+	if r == nil {
+		return nil
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return nil
+	}
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return nil
+	}
+	info, err := r.LookupGeo(addr)
+	if err != nil {
+		return nil
+	}
+	var fields []Field
+	if info.Country != "" {
+		fields = append(fields, Str(FieldGeoCountry, info.Country))
+	}
+	if info.City != "" {
+		fields = append(fields, Str(FieldGeoCity, info.City))
+	}
+	if info.ASN != 0 {
+		fields = append(fields, Uint64(FieldASN, uint64(info.ASN)))
+	}
+	if info.ASOrg != "" {
+		fields = append(fields, Str(FieldASOrg, info.ASOrg))
+	}
+	return fields
+}
+
+// CtxWithGeo returns ctx with the geo fields of ip (see GeoFields) added
+// to its logger, so every entry of a request carries them. ctx is
+// returned unchanged when there is nothing to add.
+//
+//	ctx = zerowrap.CtxWithGeo(ctx, geo, clientIP)
+func CtxWithGeo(ctx context.Context, r GeoResolver, ip string) context.Context {
+	fields := GeoFields(r, ip)
+	if len(fields) == 0 {
+		return ctx
+	}
+	return CtxWith(ctx, fields...)
+}
@@ -0,0 +1,32 @@
+// Package gormlog provides gorm statement logging for zerowrap.
+//
+// This is an optional sub-package that adds the gorm dependency. Its
+// logger writes gorm statements with the same fields and level rules as
+// sqllog: normalized query, query_hash, duration and error, with the
+// logger of the statement context, so statements run with
+// db.WithContext(ctx) carry the request fields.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap/gormlog"
+//	    "github.com/bnema/zerowrap/sqllog"
+//	    "gorm.io/driver/postgres"
+//	    "gorm.io/gorm"
+//	)
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//	    Logger: gormlog.New(sqllog.Options{
+//	        Database:      "orders",
+//	        SlowThreshold: 200 * time.Millisecond,
+//	    }),
+//	})
+//
+//	db.WithContext(ctx).First(&user, 42)
+//	// {"level":"debug","database":"orders",
+//	//  "query":"SELECT * FROM \"users\" WHERE \"users\".\"id\" = ? ORDER BY \"users\".\"id\" LIMIT ?",
+//	//  "query_hash":"...","duration_ms":0.52,"message":"query"}
+//
+// Use this logger or a gorm dialector opened on an sqllog-wrapped
+// *sql.DB, not both, or every statement is logged twice.
+package gormlog
@@ -0,0 +1,80 @@
+package gormlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/sqllog"
+	"gorm.io/gorm/logger"
+)
+
+// Logger is a gorm logger writing every statement through
+// sqllog.Options.Record, and gorm's own messages, with the logger of the
+// statement context. It implements gorm's logger.Interface.
+type Logger struct {
+	opts  sqllog.Options
+	level logger.LogLevel
+}
+
+// New returns a gorm logger logging statements with opts:
+//
+//	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
+//	    Logger: gormlog.New(sqllog.Options{Database: "orders"}),
+//	})
+//
+// Levels are those of opts and of the context logger, so every statement
+// is passed on; LogMode(logger.Silent) turns logging off, as gorm does
+// for dry runs.
+func New(opts sqllog.Options) *Logger {
+	return &Logger{opts: opts, level: logger.Info}
+}
+
+// LogMode returns a copy of l logging gorm messages up to level.
+// Statements are logged at any level but logger.Silent.
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	c := *l
+	c.level = level
+	return &c
+}
+
+// Info logs a gorm message at info level.
+func (l *Logger) Info(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Info {
+		log := zerowrap.FromCtx(ctx)
+		log.Info().Msgf(msg, data...)
+	}
+}
+
+// Warn logs a gorm message at warn level.
+func (l *Logger) Warn(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Warn {
+		log := zerowrap.FromCtx(ctx)
+		log.Warn().Msgf(msg, data...)
+	}
+}
+
+// Error logs a gorm message at error level.
+func (l *Logger) Error(ctx context.Context, msg string, data ...any) {
+	if l.level >= logger.Error {
+		log := zerowrap.FromCtx(ctx)
+		log.Error().Msgf(msg, data...)
+	}
+}
+
+// Trace logs the statement run from begin. gorm passes statements with
+// their arguments interpolated; they are normalized like any other, so
+// the literals do not reach the logs. logger.ErrRecordNotFound, the
+// result of First on an empty table rather than a failure, is logged as
+// a successful statement.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+	if errors.Is(err, logger.ErrRecordNotFound) {
+		err = nil
+	}
+	sql, _ := fc()
+	l.opts.Record(ctx, sql, time.Since(begin), err)
+}
@@ -0,0 +1,60 @@
+package gormlog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/gormlog"
+	"github.com/bnema/zerowrap/sqllog"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm/logger"
+)
+
+func statement() (string, int64) {
+	return `SELECT * FROM "users" WHERE email = 'a@example.com' LIMIT 1`, 0
+}
+
+func TestTraceLogsNormalizedStatements(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"success", nil, `"level":"debug"`},
+		{"record not found", logger.ErrRecordNotFound, `"level":"debug"`},
+		{"failure", context.DeadlineExceeded, `"level":"warn"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := zerowrap.WithCtxZerolog(context.Background(), zerolog.New(&buf))
+			gormlog.New(sqllog.Options{}).Trace(ctx, time.Now(), statement, tt.err)
+
+			out := buf.String()
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("entry %s, want %s", out, tt.want)
+			}
+			if strings.Contains(out, "a@example.com") {
+				t.Errorf("literal logged: %s", out)
+			}
+			if !strings.Contains(out, `"query":"SELECT * FROM \"users\" WHERE email = ? LIMIT ?"`) {
+				t.Errorf("statement not normalized: %s", out)
+			}
+		})
+	}
+}
+
+func TestLogModeSilent(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerowrap.WithCtxZerolog(context.Background(), zerolog.New(&buf))
+	l := gormlog.New(sqllog.Options{}).LogMode(logger.Silent)
+	l.Trace(ctx, time.Now(), statement, nil)
+	l.Error(ctx, "failed %s", "migration")
+	if buf.Len() != 0 {
+		t.Errorf("silent logger wrote %s", buf.String())
+	}
+}
@@ -0,0 +1,35 @@
+// Package gqllog provides gqlgen GraphQL logging for zerowrap.
+//
+// This is an optional sub-package that adds the gqlgen dependency.
+// Its handler extension attaches graphql_operation, graphql_operation_type
+// and query_hash to the request context logger, so resolver code inherits
+// them, and logs one completion event per operation with its duration,
+// complexity and errors. Operations with errors log at warn.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/99designs/gqlgen/graphql/handler"
+//	    "github.com/99designs/gqlgen/graphql/handler/extension"
+//	    "github.com/bnema/zerowrap/gqllog"
+//	    "github.com/bnema/zerowrap/httplog"
+//	)
+//
+//	srv := handler.NewDefaultServer(generated.NewExecutableSchema(cfg))
+//	srv.Use(extension.FixedComplexityLimit(200)) // adds the complexity field
+//	srv.Use(gqllog.NewExtension(gqllog.Options{
+//	    ResolverThreshold: 100 * time.Millisecond, // slow resolvers log at warn
+//	    LogVariables:      true,                   // sensitive names redacted
+//	}))
+//
+//	http.Handle("/query", httplog.Middleware(httplog.Options{})(srv))
+//
+// The logger is taken from the request context, so the server's base
+// context (or httplog.Middleware) must carry one.
+//
+// # Query Hash
+//
+// query_hash is the hex SHA-256 of the query text, the same hash used by
+// automatic persisted queries, so occurrences of one operation can be
+// grouped even when clients do not name it.
+package gqllog
@@ -0,0 +1,22 @@
+module github.com/bnema/zerowrap/gqllog
+
+go 1.25
+
+require (
+	github.com/99designs/gqlgen v0.17.85
+	github.com/bnema/zerowrap v0.0.0-00010101000000-000000000000
+	github.com/rs/zerolog v1.34.0
+	github.com/vektah/gqlparser/v2 v2.5.31
+)
+
+require (
+	github.com/go-viper/mapstructure/v2 v2.4.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/mattn/go-colorable v0.1.14 // indirect
+	github.com/mattn/go-isatty v0.0.20 // indirect
+	github.com/sosodev/duration v1.3.1 // indirect
+	golang.org/x/sys v0.39.0 // indirect
+	gopkg.in/natefinch/lumberjack.v2 v2.2.1 // indirect
+)
+
+replace github.com/bnema/zerowrap => ../
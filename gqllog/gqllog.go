@@ -0,0 +1,219 @@
+package gqllog
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// Field names logged by the extension.
+const (
+	FieldOperationName = "graphql_operation"
+	FieldOperationType = "graphql_operation_type"
+	FieldQueryHash     = "query_hash"
+	FieldVariables     = "variables"
+	FieldComplexity    = "complexity"
+	FieldResolver      = "resolver"
+	FieldResolverPath  = "resolver_path"
+	FieldResponses     = "responses"
+)
+
+// Options configures the gqlgen logging extension.
+type Options struct {
+	// ResolverThreshold logs resolvers taking longer than this at warn
+	// level, with their path and duration.
+	// Disabled if 0.
+	ResolverThreshold time.Duration
+
+	// LogVariables adds the operation variables to the completion log,
+	// with the RedactVariables values replaced.
+	LogVariables bool
+
+	// RedactVariables lists variable names whose values are replaced by
+	// zerowrap.Redacted, at any depth.
+	// Defaults to zerowrap.DefaultRedactQueryKeys if nil.
+	RedactVariables []string
+}
+
+// tracer implements the gqlgen handler extension interfaces.
+type tracer struct {
+	opts Options
+}
+
+// NewExtension returns a gqlgen handler extension that attaches operation
+// fields (graphql_operation, graphql_operation_type, query_hash) to the
+// request context logger, so resolver code inherits them, and logs one
+// completion event per operation with its duration, complexity and
+// errors. Subscriptions log once, when they end.
+//
+//	srv := handler.New(generated.NewExecutableSchema(cfg))
+//	srv.Use(extension.FixedComplexityLimit(200)) // optional, adds complexity
+//	srv.Use(gqllog.NewExtension(gqllog.Options{LogVariables: true}))
+func NewExtension(opts Options) graphql.HandlerExtension {
+	return tracer{opts: opts}
+}
+
+func (tracer) ExtensionName() string {
+	return "ZerowrapLogger"
+}
+
+func (tracer) Validate(graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (x tracer) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	op := graphql.GetOperationContext(ctx)
+	start := time.Now()
+	opType := operationType(op)
+	ctx = zerowrap.CtxWithFields(ctx, map[string]any{
+		FieldOperationName: operationName(op),
+		FieldOperationType: opType,
+		FieldQueryHash:     QueryHash(op.RawQuery),
+	})
+
+	// Responses are read with the transport's context, so log with the
+	// enriched one.
+	logCtx := ctx
+	respond := next(ctx)
+	if opType == string(ast.Subscription) {
+		responses := 0
+		return func(ctx context.Context) *graphql.Response {
+			resp := respond(ctx)
+			if resp == nil {
+				x.log(logCtx, op, start, nil, responses)
+				return nil
+			}
+			responses++
+			return resp
+		}
+	}
+
+	logged := false
+	return func(ctx context.Context) *graphql.Response {
+		resp := respond(ctx)
+		if !logged {
+			logged = true
+			x.log(logCtx, op, start, resp, 0)
+		}
+		return resp
+	}
+}
+
+func (x tracer) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if x.opts.ResolverThreshold <= 0 || fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+	start := time.Now()
+	res, err := next(ctx)
+	if dur := time.Since(start); dur >= x.opts.ResolverThreshold {
+		log := zerowrap.FromCtx(ctx)
+		e := log.Warn().
+			Str(FieldResolver, fc.Object+"."+fc.Field.Name).
+			Str(FieldResolverPath, fc.Path().String())
+		if err != nil {
+			e = e.Err(err)
+		}
+		zerowrap.AddDuration(e, dur).Msg("slow resolver")
+	}
+	return res, err
+}
+
+// log writes the completion event of op. resp is nil for subscriptions,
+// which log the count of responses sent instead.
+func (x tracer) log(ctx context.Context, op *graphql.OperationContext, start time.Time, resp *graphql.Response, responses int) {
+	level := zerolog.InfoLevel
+	if resp != nil && len(resp.Errors) > 0 {
+		level = zerolog.WarnLevel
+	}
+	log := zerowrap.FromCtx(ctx)
+	e := log.WithLevel(level)
+	if !e.Enabled() {
+		return
+	}
+	if stats := extension.GetComplexityStats(ctx); stats != nil {
+		e = e.Int(FieldComplexity, stats.Complexity)
+	}
+	if x.opts.LogVariables && len(op.Variables) > 0 {
+		e = e.Interface(FieldVariables, x.redact(op.Variables))
+	}
+	if resp == nil {
+		e = e.Int(FieldResponses, responses)
+	} else if len(resp.Errors) > 0 {
+		e = e.Int(zerowrap.FieldCount, len(resp.Errors)).Err(resp.Errors)
+	}
+	zerowrap.AddDuration(e, time.Since(start)).Msg("graphql operation completed")
+}
+
+// redact returns a copy of vars with the RedactVariables values replaced.
+func (x tracer) redact(vars map[string]any) map[string]any {
+	keys := x.opts.RedactVariables
+	if keys == nil {
+		keys = zerowrap.DefaultRedactQueryKeys
+	}
+	return redactValue(vars, keys).(map[string]any)
+}
+
+func redactValue(v any, keys []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, item := range v {
+			if containsFold(keys, k) {
+				out[k] = zerowrap.Redacted
+				zerowrap.ReportRedacted(1)
+			} else {
+				out[k] = redactValue(item, keys)
+			}
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = redactValue(item, keys)
+		}
+		return out
+	}
+	return v
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryHash returns the hex SHA-256 of query, as used by automatic
+// persisted queries, so identical operations can be grouped in logs.
+func QueryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// operationName returns the requested operation name, or the name of
+// the executed operation when the request did not set one.
+func operationName(op *graphql.OperationContext) string {
+	if op.OperationName == "" && op.Operation != nil {
+		return op.Operation.Name
+	}
+	return op.OperationName
+}
+
+// operationType returns "query", "mutation" or "subscription".
+func operationType(op *graphql.OperationContext) string {
+	if op.Operation == nil {
+		return ""
+	}
+	return string(op.Operation.Operation)
+}
@@ -0,0 +1,24 @@
+// Package group provides an errgroup-like task group with per-task logging.
+//
+// Each task started with Go receives a context whose logger carries the
+// task name (worker) and start order (worker_index). The group logs each
+// task's duration, logs failures at error level, and recovers panics into
+// errors so one misbehaving goroutine cannot crash the process.
+//
+// # Usage
+//
+//	g, ctx := group.WithContext(ctx)
+//	g.SetLimit(8)
+//
+//	for _, id := range ids {
+//	    g.Go("fetch-user", func(ctx context.Context) error {
+//	        log := zerowrap.FromCtx(ctx) // includes worker, worker_index
+//	        log.Debug().Str("id", id).Msg("fetching")
+//	        return fetchUser(ctx, id)
+//	    })
+//	}
+//
+//	if err := g.Wait(); err != nil {
+//	    return err
+//	}
+package group
@@ -0,0 +1,133 @@
+package group
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"github.com/bnema/zerowrap"
+)
+
+// Group runs named tasks concurrently, like errgroup.Group, with a
+// per-task context logger. A zero Group is valid, does not cancel on
+// error and uses a background context carrying no logger.
+type Group struct {
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+
+	wg  sync.WaitGroup
+	sem chan struct{}
+
+	mu    sync.Mutex
+	index int
+
+	errOnce sync.Once
+	err     error
+}
+
+// WithContext returns a Group and a derived context. The derived context
+// is canceled the first time a task returns an error or panics, or when
+// Wait returns, whichever occurs first.
+// Task contexts inherit the logger from ctx.
+func WithContext(ctx context.Context) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	return &Group{ctx: ctx, cancel: cancel}, ctx
+}
+
+// SetLimit limits the number of concurrently running tasks to n.
+// A negative value removes the limit. Must not be called while tasks are running.
+func (g *Group) SetLimit(n int) {
+	if n < 0 {
+		g.sem = nil
+		return
+	}
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs fn in a new goroutine. The context passed to fn carries worker
+// (the task name) and worker_index fields. Go logs the task's duration on
+// completion, logs failures at error level and recovers panics into errors.
+// The first error is returned by Wait.
+//
+//	g, ctx := group.WithContext(ctx)
+//	for _, id := range ids {
+//	    g.Go("fetch-user", func(ctx context.Context) error {
+//	        return fetchUser(ctx, id)
+//	    })
+//	}
+//	err := g.Wait()
+func (g *Group) Go(name string, fn func(ctx context.Context) error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	index := g.index
+	g.index++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := g.run(name, index, fn); err != nil {
+			g.errOnce.Do(func() {
+				g.err = err
+				if g.cancel != nil {
+					g.cancel(err)
+				}
+			})
+		}
+	}()
+}
+
+// Wait blocks until all tasks have returned, then returns the first
+// error (if any) from them.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(g.err)
+	}
+	return g.err
+}
+
+// run executes a single task with its enriched context and logging.
+func (g *Group) run(name string, index int, fn func(ctx context.Context) error) (err error) {
+	ctx := g.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx = zerowrap.CtxWithFields(ctx, map[string]any{
+		zerowrap.FieldWorker:      name,
+		zerowrap.FieldWorkerIndex: index,
+	})
+	log := zerowrap.FromCtx(ctx)
+	start := time.Now()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("task %s panicked: %v", name, r)
+			e := log.Error().
+				Err(err).
+				Str(zerowrap.FieldStack, string(debug.Stack()))
+			zerowrap.AddDuration(e, time.Since(start)).Msg("task panicked")
+			return
+		}
+		if err != nil {
+			zerowrap.AddDuration(log.Error().Err(err), time.Since(start)).Msg("task failed")
+			return
+		}
+		zerowrap.AddDuration(log.Debug(), time.Since(start)).Msg("task completed")
+	}()
+
+	return fn(ctx)
+}
+
+// done releases the task's semaphore slot and wait group entry.
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
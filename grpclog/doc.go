@@ -0,0 +1,73 @@
+// Package grpclog provides gRPC server logging interceptors for zerowrap.
+//
+// The interceptors enrich the call context logger with request_id,
+// grpc_service, grpc_method and client_ip, so every downstream log
+// carries them, and log one completion event per call with grpc_code and
+// duration. Server-side failures log at error level, client errors
+// (InvalidArgument, NotFound, ...) and slow calls at warn.
+//
+// # Usage
+//
+//	opts := grpclog.Options{SlowThreshold: 500 * time.Millisecond}
+//	srv := grpc.NewServer(
+//	    grpc.ChainUnaryInterceptor(grpclog.UnaryServerInterceptor(opts)),
+//	    grpc.ChainStreamInterceptor(grpclog.StreamServerInterceptor(opts)),
+//	)
+//
+// The logger is taken from the call context, so an outer interceptor (or
+// a stats handler) must attach one with zerowrap.WithCtx.
+//
+// # ID Propagation
+//
+// The request ID is read from the x-request-id metadata key (or
+// generated) and the correlation ID from x-correlation-id, as
+// zerowrap.Extract does, so zerowrap.Inject forwards them downstream.
+//
+// # Stream Messages
+//
+// Set LogMessages to log each message sent and received on streams with
+// its direction, index and size. Entries are written at MessageLevel
+// (debug by default) and cost nothing when that level is disabled.
+// MaxMessages caps the entries per stream; the rest are counted in the
+// completion's suppressed field:
+//
+//	opts := grpclog.Options{
+//	    LogMessages:    true,
+//	    CapturePayload: true, // protobuf messages as JSON, payload field
+//	    RedactFields:   []string{"password", "card_number"},
+//	}
+//
+// # Excluding Noise
+//
+// ExcludeMethods drops successful calls to health checks and reflection;
+// failed and slow calls are still logged:
+//
+//	opts := grpclog.Options{ExcludeMethods: []string{"/grpc.health.v1.Health/*"}}
+//
+// ExcludeFunc covers rules a method list cannot express, and
+// DemoteExcluded logs excluded calls at trace level instead of dropping
+// them, as in httplog.
+//
+// # Debug Tokens
+//
+// With DebugKey set, calls carrying a token made by httplog.NewDebugToken
+// in the x-debug-token metadata key get their context logger elevated to
+// the token's level (debug or trace) until it expires. The same key works
+// for HTTP and gRPC endpoints, and grpc-gateway forwards the header:
+//
+//	opts := grpclog.Options{DebugKey: key}
+//	// grpcurl -H "x-debug-token: $token" api.internal:443 orders.v1.Orders/Get
+//
+// # HTTP Gateways
+//
+// Calls made through grpc-gateway carry the HTTP route template in the
+// x-http-route metadata key when the gateway uses gatewaylog.Metadata,
+// so entries have both route and grpc_method. For connect-go handlers,
+// use the connectlog sub-package, which shares these Options.
+//
+// # Other Frameworks
+//
+// Options exposes its building blocks (WithCall, MessageLog, Level, Log)
+// so interceptors for other RPC frameworks can share the same fields and
+// level rules.
+package grpclog
@@ -0,0 +1,314 @@
+package grpclog
+
+import (
+	"context"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Field names logged by the interceptors.
+const (
+	FieldService      = "grpc_service"
+	FieldMethod       = "grpc_method"
+	FieldCode         = "grpc_code"
+	FieldDirection    = "direction"
+	FieldMessageIndex = "msg_index"
+	FieldMsgsSent     = "msgs_sent"
+	FieldMsgsReceived = "msgs_received"
+)
+
+// MetadataHTTPRoute is the metadata key read for the HTTP route template
+// of calls made through an HTTP gateway (see the gatewaylog sub-package).
+const MetadataHTTPRoute = "x-http-route"
+
+// DefaultDebugMetadata is the metadata key read for debug tokens, the
+// lowercase form of httplog.DefaultDebugHeader, so tokens forwarded by an
+// HTTP gateway are honored too.
+const DefaultDebugMetadata = "x-debug-token"
+
+// Message directions logged in the direction field.
+const (
+	DirectionSent     = "sent"
+	DirectionReceived = "received"
+)
+
+// DefaultMaxMessages is the number of messages logged per stream.
+const DefaultMaxMessages = 100
+
+// DefaultMaxPayloadBytes is the captured payload size per message.
+const DefaultMaxPayloadBytes = 4096
+
+// Options configures the gRPC logging interceptors.
+type Options struct {
+	// SlowThreshold logs calls taking longer than this at warn level.
+	// Disabled if 0.
+	SlowThreshold time.Duration
+
+	// ExcludeMethods lists full method names (e.g.
+	// "/grpc.health.v1.Health/Check") whose successful calls are not
+	// logged. A trailing "*" matches a prefix, e.g. "/grpc.reflection.*".
+	// Failed and slow calls are still logged.
+	ExcludeMethods []string
+
+	// ExcludeFunc excludes successful calls like ExcludeMethods, for
+	// rules a method list cannot express.
+	ExcludeFunc func(fullMethod string) bool
+
+	// DemoteExcluded logs excluded calls at trace level instead of
+	// dropping them.
+	DemoteExcluded bool
+
+	// DebugKey enables per-call debug logging: calls carrying a token
+	// signed with this key (see httplog.NewDebugToken) get their context
+	// logger elevated to the token's level. Disabled if empty.
+	DebugKey []byte
+
+	// DebugMetadata is the metadata key read for debug tokens.
+	// Defaults to DefaultDebugMetadata if empty.
+	DebugMetadata string
+
+	// LogMessages logs every message sent and received on streams, with
+	// its direction, index and size.
+	LogMessages bool
+
+	// MessageLevel is the level of per-message entries. Message payloads
+	// are only sized and encoded when the logger is enabled at this level.
+	// Defaults to debug if 0.
+	MessageLevel zerolog.Level
+
+	// MaxMessages caps the messages logged per stream; the count of the
+	// others is logged with the completion (suppressed field).
+	// Defaults to DefaultMaxMessages if 0.
+	MaxMessages int
+
+	// CapturePayload adds each protobuf message, encoded as JSON with
+	// its .proto field names, to message entries (payload field).
+	CapturePayload bool
+
+	// RedactFields lists .proto field names whose values are replaced by
+	// zerowrap.Redacted in captured payloads, at any depth.
+	RedactFields []string
+
+	// MaxPayloadBytes caps the captured payload size; longer payloads
+	// are cut and logged as a string.
+	// Defaults to DefaultMaxPayloadBytes if 0.
+	MaxPayloadBytes int
+}
+
+// Completion describes a finished call, as passed to Options.Log.
+type Completion struct {
+	FullMethod string
+	Code       codes.Code
+	Err        error
+	Duration   time.Duration
+
+	// Messages holds the stream message counts; nil for unary calls.
+	Messages *MessageLog
+}
+
+// UnaryServerInterceptor returns a gRPC interceptor that attaches call
+// fields (request_id, grpc_service, grpc_method, client_ip) to the context
+// logger and logs completion with the status code and duration.
+//
+// The logger is taken from the call context, so the server must attach
+// one, e.g. with a base interceptor or zerowrap.WithCtx.
+func UnaryServerInterceptor(opts Options) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		get := incomingMetadata(ctx)
+		ctx = WithCall(ctx, info.FullMethod, get, peerAddr(ctx))
+		ctx = opts.WithDebug(ctx, get)
+
+		resp, err := handler(ctx, req)
+
+		opts.Log(zerowrap.FromCtx(ctx), Completion{
+			FullMethod: info.FullMethod,
+			Code:       status.Code(err),
+			Err:        err,
+			Duration:   time.Since(start),
+		})
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor returns a gRPC interceptor that enriches the
+// stream context logger like UnaryServerInterceptor and logs completion
+// with the count of messages sent and received. With Options.LogMessages
+// every message is logged as well.
+func StreamServerInterceptor(opts Options) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		get := incomingMetadata(ss.Context())
+		ctx := WithCall(ss.Context(), info.FullMethod, get, peerAddr(ss.Context()))
+		ctx = opts.WithDebug(ctx, get)
+		log := zerowrap.FromCtx(ctx)
+		ws := &stream{ServerStream: ss, ctx: ctx, messages: opts.MessageLog(log)}
+
+		err := handler(srv, ws)
+
+		opts.Log(log, Completion{
+			FullMethod: info.FullMethod,
+			Code:       status.Code(err),
+			Err:        err,
+			Duration:   time.Since(start),
+			Messages:   ws.messages,
+		})
+		return err
+	}
+}
+
+// Log writes the call completion event for c.
+// Successful excluded calls (see Excluded) are skipped or logged at trace
+// level.
+func (o Options) Log(log zerowrap.Logger, c Completion) {
+	level := o.Level(c.Code, c.Duration)
+	if level <= zerolog.InfoLevel && o.Excluded(c.FullMethod) {
+		if !o.DemoteExcluded {
+			return
+		}
+		level = zerolog.TraceLevel
+	}
+	e := log.WithLevel(level)
+	if m := c.Messages; m != nil {
+		e = e.Int64(FieldMsgsSent, m.sent.Load()).Int64(FieldMsgsReceived, m.received.Load())
+		if n := m.suppressed.Load(); n > 0 {
+			e = e.Int64(zerowrap.FieldSuppressed, n)
+		}
+	}
+	e = e.Str(FieldCode, c.Code.String())
+	if c.Err != nil {
+		e = e.Err(c.Err)
+	}
+	zerowrap.AddDuration(e, c.Duration).Msg("rpc completed")
+}
+
+// Level returns the completion log level for a status code and duration.
+// Server-side failures log at error level, client errors and slow calls
+// at warn, everything else at info.
+func (o Options) Level(code codes.Code, dur time.Duration) zerolog.Level {
+	switch code {
+	case codes.OK:
+	case codes.Canceled, codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.PermissionDenied, codes.Unauthenticated, codes.FailedPrecondition,
+		codes.OutOfRange, codes.ResourceExhausted, codes.Aborted:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+	if o.SlowThreshold > 0 && dur >= o.SlowThreshold {
+		return zerolog.WarnLevel
+	}
+	return zerolog.InfoLevel
+}
+
+// Excluded reports whether fullMethod matches ExcludeMethods or
+// ExcludeFunc.
+func (o Options) Excluded(fullMethod string) bool {
+	for _, m := range o.ExcludeMethods {
+		if prefix, ok := strings.CutSuffix(m, "*"); ok {
+			if strings.HasPrefix(fullMethod, prefix) {
+				return true
+			}
+		} else if m == fullMethod {
+			return true
+		}
+	}
+	return o.ExcludeFunc != nil && o.ExcludeFunc(fullMethod)
+}
+
+// DebugLevel returns the level of a valid debug token read through get,
+// verified as httplog.Options.DebugLevel does.
+func (o Options) DebugLevel(get func(string) string) (zerolog.Level, bool) {
+	return o.httpDebug().DebugLevel(get)
+}
+
+// WithDebug returns ctx with its logger set to the level of a valid debug
+// token read through get, or ctx unchanged if there is none.
+func (o Options) WithDebug(ctx context.Context, get func(string) string) context.Context {
+	return o.httpDebug().WithDebug(ctx, get)
+}
+
+// httpDebug returns the httplog options verifying debug tokens, shared so
+// a token works the same on HTTP and gRPC endpoints.
+func (o Options) httpDebug() httplog.Options {
+	key := o.DebugMetadata
+	if key == "" {
+		key = DefaultDebugMetadata
+	}
+	return httplog.Options{DebugKey: o.DebugKey, DebugHeader: key}
+}
+
+// WithCall returns ctx carrying the request and correlation IDs read
+// through get (see zerowrap.Extract) and a logger with the call fields:
+// grpc_service and grpc_method from fullMethod, client_ip from peerAddr,
+// and route when the call came through an HTTP gateway that set the
+// MetadataHTTPRoute key.
+//
+// When ctx already carries a request ID, as with connect handlers behind
+// httplog.Middleware, the IDs and client_ip bound by that middleware are
+// kept and not logged twice.
+func WithCall(ctx context.Context, fullMethod string, get func(string) string, peerAddr string) context.Context {
+	service, method := splitMethod(fullMethod)
+	fields := map[string]any{
+		FieldService: service,
+		FieldMethod:  method,
+	}
+	if zerowrap.RequestIDFromCtx(ctx) == "" {
+		ctx = zerowrap.Extract(ctx, get)
+		if peerAddr != "" {
+			fields[zerowrap.FieldClientIP] = remoteIP(peerAddr)
+		}
+	}
+	if route := get(MetadataHTTPRoute); route != "" {
+		fields[zerowrap.FieldRoute] = route
+	}
+	return zerowrap.CtxWithFields(ctx, fields)
+}
+
+// incomingMetadata returns a getter for the first value of incoming
+// metadata keys.
+func incomingMetadata(ctx context.Context) func(string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return func(k string) string {
+		if v := md.Get(k); len(v) > 0 {
+			return v[0]
+		}
+		return ""
+	}
+}
+
+// peerAddr returns the remote address of the call, if known.
+func peerAddr(ctx context.Context) string {
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		return p.Addr.String()
+	}
+	return ""
+}
+
+// splitMethod splits "/pkg.Service/Method" into its service and method.
+func splitMethod(fullMethod string) (service, method string) {
+	fullMethod = strings.TrimPrefix(fullMethod, "/")
+	if i := strings.LastIndexByte(fullMethod, '/'); i >= 0 {
+		return fullMethod[:i], fullMethod[i+1:]
+	}
+	return "", fullMethod
+}
+
+// remoteIP strips the port from a peer address.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
@@ -0,0 +1,89 @@
+package grpclog_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/grpclog"
+	"github.com/bnema/zerowrap/httplog"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// call runs a unary call to fullMethod through the interceptor with md as
+// incoming metadata, logging at info level to the returned buffer.
+func call(t *testing.T, opts grpclog.Options, fullMethod string, md metadata.MD, handler grpc.UnaryHandler) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log := zerolog.New(&buf).Level(zerolog.InfoLevel)
+	ctx := metadata.NewIncomingContext(zerowrap.WithCtxZerolog(context.Background(), log), md)
+	if handler == nil {
+		handler = func(context.Context, any) (any, error) { return nil, nil }
+	}
+	info := &grpc.UnaryServerInfo{FullMethod: fullMethod}
+	_, _ = grpclog.UnaryServerInterceptor(opts)(ctx, nil, info, handler)
+	return &buf
+}
+
+func TestExcludeFunc(t *testing.T) {
+	ping := func(m string) bool { return strings.HasSuffix(m, "/Ping") }
+
+	if out := call(t, grpclog.Options{ExcludeFunc: ping}, "/svc.v1.Svc/Ping", nil, nil).String(); out != "" {
+		t.Errorf("excluded call logged: %s", out)
+	}
+	if out := call(t, grpclog.Options{ExcludeFunc: ping}, "/svc.v1.Svc/Get", nil, nil).String(); !strings.Contains(out, "rpc completed") {
+		t.Errorf("call not matching ExcludeFunc not logged: %q", out)
+	}
+	failing := func(context.Context, any) (any, error) { return nil, status.Error(codes.Internal, "boom") }
+	if out := call(t, grpclog.Options{ExcludeFunc: ping}, "/svc.v1.Svc/Ping", nil, failing).String(); !strings.Contains(out, `"level":"error"`) {
+		t.Errorf("failed excluded call not logged at error: %q", out)
+	}
+}
+
+func TestDemoteExcluded(t *testing.T) {
+	opts := grpclog.Options{
+		ExcludeMethods: []string{"/grpc.health.v1.Health/*"},
+		DemoteExcluded: true,
+	}
+	var buf bytes.Buffer
+	opts.Log(zerowrap.Logger{Logger: zerolog.New(&buf)}, grpclog.Completion{FullMethod: "/grpc.health.v1.Health/Check", Code: codes.OK})
+	if !strings.Contains(buf.String(), `"level":"trace"`) {
+		t.Errorf("demoted call = %q, want trace level", buf.String())
+	}
+}
+
+func TestDebugToken(t *testing.T) {
+	key := []byte("secret")
+	opts := grpclog.Options{DebugKey: key}
+	debugging := func(ctx context.Context, _ any) (any, error) {
+		log := zerowrap.FromCtx(ctx)
+		log.Debug().Msg("inside")
+		return nil, nil
+	}
+
+	tests := []struct {
+		name string
+		md   metadata.MD
+		want bool
+	}{
+		{"valid", metadata.Pairs("x-debug-token", httplog.NewDebugToken(key, zerolog.DebugLevel, time.Minute)), true},
+		{"missing", nil, false},
+		{"other key", metadata.Pairs("x-debug-token", httplog.NewDebugToken([]byte("other"), zerolog.DebugLevel, time.Minute)), false},
+		{"expired", metadata.Pairs("x-debug-token", httplog.NewDebugToken(key, zerolog.DebugLevel, -time.Minute)), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := call(t, opts, "/svc.v1.Svc/Get", tt.md, debugging).String()
+			if got := strings.Contains(out, "inside"); got != tt.want {
+				t.Errorf("debug entry logged = %v, want %v: %s", got, tt.want, out)
+			}
+		})
+	}
+}
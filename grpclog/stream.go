@@ -0,0 +1,164 @@
+package grpclog
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// stream wraps a server stream to carry the enriched context and log
+// the messages it sends and receives.
+type stream struct {
+	grpc.ServerStream
+	ctx      context.Context
+	messages *MessageLog
+}
+
+func (s *stream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *stream) SendMsg(m any) error {
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		s.messages.Sent(m)
+	}
+	return err
+}
+
+func (s *stream) RecvMsg(m any) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		s.messages.Received(m)
+	}
+	return err
+}
+
+// MessageLog counts, and with Options.LogMessages logs, the messages of
+// one stream. Sent and Received may be called concurrently, as gRPC
+// allows sending and receiving from different goroutines.
+type MessageLog struct {
+	opts Options
+	log  zerowrap.Logger
+
+	sent       atomic.Int64
+	received   atomic.Int64
+	logged     atomic.Int64
+	suppressed atomic.Int64
+}
+
+// MessageLog returns a MessageLog for a stream logging to log. Pass it in
+// Completion.Messages so the completion carries the counts.
+func (o Options) MessageLog(log zerowrap.Logger) *MessageLog {
+	return &MessageLog{opts: o, log: log}
+}
+
+// Sent records message m sent on the stream.
+func (l *MessageLog) Sent(m any) {
+	l.logMessage(DirectionSent, l.sent.Add(1), m)
+}
+
+// Received records message m received on the stream.
+func (l *MessageLog) Received(m any) {
+	l.logMessage(DirectionReceived, l.received.Add(1), m)
+}
+
+// logMessage logs message m, the index-th in its direction, when
+// LogMessages is set, the logger is enabled at MessageLevel and the
+// MaxMessages cap is not reached.
+func (l *MessageLog) logMessage(direction string, index int64, m any) {
+	if !l.opts.LogMessages {
+		return
+	}
+	e := l.log.WithLevel(l.opts.MessageLevel)
+	if !e.Enabled() {
+		return
+	}
+	limit := l.opts.MaxMessages
+	if limit == 0 {
+		limit = DefaultMaxMessages
+	}
+	if l.logged.Add(1) > int64(limit) {
+		l.suppressed.Add(1)
+		zerowrap.ReportSuppressed(1)
+		e.Discard()
+		return
+	}
+
+	e = e.Str(FieldDirection, direction).Int64(FieldMessageIndex, index)
+	if pm, ok := m.(proto.Message); ok {
+		e = zerowrap.AddSize(e, int64(proto.Size(pm)))
+		if l.opts.CapturePayload {
+			e = l.opts.addPayload(e, pm)
+		}
+	}
+	e.Msg("stream message")
+}
+
+// addPayload adds m encoded as JSON to e, redacted and capped.
+func (o Options) addPayload(e *zerolog.Event, m proto.Message) *zerolog.Event {
+	b, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(m)
+	if err != nil {
+		return e.Str(zerowrap.FieldPayload, err.Error())
+	}
+	if len(o.RedactFields) > 0 {
+		b = redactJSON(b, o.RedactFields)
+	}
+	limit := o.MaxPayloadBytes
+	if limit == 0 {
+		limit = DefaultMaxPayloadBytes
+	}
+	if len(b) > limit {
+		return e.Str(zerowrap.FieldPayload, string(b[:limit])).Bool(zerowrap.FieldTruncated, true)
+	}
+	return e.RawJSON(zerowrap.FieldPayload, b)
+}
+
+// redactJSON replaces the values of keys in b, at any depth, with
+// zerowrap.Redacted. b is returned unchanged if it cannot be decoded.
+func redactJSON(b []byte, keys []string) []byte {
+	var v any
+	if err := json.Unmarshal(b, &v); err != nil {
+		return b
+	}
+	out, err := json.Marshal(redactValue(v, keys))
+	if err != nil {
+		return b
+	}
+	return out
+}
+
+func redactValue(v any, keys []string) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			if containsFold(keys, k) {
+				v[k] = zerowrap.Redacted
+				zerowrap.ReportRedacted(1)
+			} else {
+				v[k] = redactValue(item, keys)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = redactValue(item, keys)
+		}
+	}
+	return v
+}
+
+func containsFold(list []string, s string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,82 @@
+package zerowrap
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// HookFunc adapts an ordinary function to a zerolog.Hook.
+type HookFunc func(e *zerolog.Event, level zerolog.Level, msg string)
+
+// Run implements zerolog.Hook.
+func (f HookFunc) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	f(e, level, msg)
+}
+
+// WithHooks returns a new logger with all hooks attached, run in order.
+//
+//	log = zerowrap.WithHooks(log, otelHook, metricsHook, redactHook)
+func WithHooks(log Logger, hooks ...zerolog.Hook) Logger {
+	return Logger{log.Hook(hooks...)}
+}
+
+// HookIf returns a hook that runs hook only when pred returns true.
+// The predicate receives the event level and message.
+//
+//	zerowrap.HookIf(func(level zerolog.Level, msg string) bool {
+//	    return strings.HasPrefix(msg, "audit:")
+//	}, auditHook)
+func HookIf(pred func(level zerolog.Level, msg string) bool, hook zerolog.Hook) zerolog.Hook {
+	return HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+		if pred(level, msg) {
+			hook.Run(e, level, msg)
+		}
+	})
+}
+
+// HookIfCtx returns a hook that runs hook only when pred returns true for
+// the event's context (set with Event.Ctx). Events without a context are
+// checked against context.Background().
+func HookIfCtx(pred func(ctx context.Context) bool, hook zerolog.Hook) zerolog.Hook {
+	return HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+		ctx := e.GetCtx()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		if pred(ctx) {
+			hook.Run(e, level, msg)
+		}
+	})
+}
+
+// HookMinLevel returns a hook that runs hook only for events at or above min.
+//
+//	log = zerowrap.WithHook(log, zerowrap.HookMinLevel(zerolog.WarnLevel, alertHook))
+func HookMinLevel(min zerolog.Level, hook zerolog.Hook) zerolog.Hook {
+	return HookIf(func(level zerolog.Level, _ string) bool {
+		return level >= min && level < zerolog.NoLevel
+	}, hook)
+}
+
+// HookLevels returns a hook that runs hook only for the listed levels.
+func HookLevels(hook zerolog.Hook, levels ...zerolog.Level) zerolog.Hook {
+	return HookIf(func(level zerolog.Level, _ string) bool {
+		for _, l := range levels {
+			if l == level {
+				return true
+			}
+		}
+		return false
+	}, hook)
+}
+
+// HookChain combines hooks into a single hook that runs them in order.
+// Useful for passing several hooks where one is expected, e.g. to HookIf.
+func HookChain(hooks ...zerolog.Hook) zerolog.Hook {
+	return HookFunc(func(e *zerolog.Event, level zerolog.Level, msg string) {
+		for _, h := range hooks {
+			h.Run(e, level, msg)
+		}
+	})
+}
@@ -0,0 +1,219 @@
+package httplog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// FieldRequestBodyTruncated marks completion logs whose request body
+// was cut at Options.RequestBodyLimit.
+const FieldRequestBodyTruncated = "request_body_truncated"
+
+// DefaultRedactBodyPaths lists the request body paths redacted by
+// default: the keys of DefaultRedactQueryKeys at any depth.
+var DefaultRedactBodyPaths = []string{
+	"$..access_token",
+	"$..api_key",
+	"$..apikey",
+	"$..password",
+	"$..secret",
+	"$..token",
+}
+
+// CaptureBody reads up to Options.RequestBodyLimit bytes of the request
+// body for the completion log, leaving the full body readable by the
+// handler. truncated reports whether the body was longer.
+func (o Options) CaptureBody(r *http.Request) (body []byte, truncated bool) {
+	if o.RequestBodyLimit <= 0 || r.Body == nil || r.Body == http.NoBody {
+		return nil, false
+	}
+	body, _ = io.ReadAll(io.LimitReader(r.Body, int64(o.RequestBodyLimit)+1))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	if len(body) > o.RequestBodyLimit {
+		return body[:o.RequestBodyLimit], true
+	}
+	return body, false
+}
+
+// addRequestBody adds the captured request body of c to e, depending on
+// its content type:
+//
+//   - JSON is logged as JSON with RedactBodyPaths redacted; a truncated
+//     or invalid document is not logged, as it cannot be redacted.
+//   - Forms are logged encoded, with the keys of RedactBodyPaths
+//     redacted.
+//   - Multipart forms are logged as the name, file name, content type
+//     and size of each part, never their content.
+//   - Text and XML are logged as is.
+//
+// Other content types are not logged.
+func (o Options) addRequestBody(e *zerolog.Event, c Completion) *zerolog.Event {
+	if len(c.RequestBody) == 0 {
+		return e
+	}
+	if c.RequestBodyTruncated {
+		e = e.Bool(FieldRequestBodyTruncated, true)
+	}
+	mediaType, params, _ := mime.ParseMediaType(c.RequestContentType)
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		if c.RequestBodyTruncated {
+			break
+		}
+		if body, ok := o.redactJSONBody(c.RequestBody); ok {
+			e = e.RawJSON(zerowrap.FieldRequestBody, body)
+		}
+	case mediaType == "application/x-www-form-urlencoded":
+		e = e.Str(zerowrap.FieldRequestBody, zerowrap.RedactQuery(string(c.RequestBody), o.redactBodyKeys()))
+	case mediaType == "multipart/form-data":
+		parts := multipartSummary(c.RequestBody, params["boundary"])
+		e = e.Fields(map[string]any{zerowrap.FieldRequestBody: parts})
+	case strings.HasPrefix(mediaType, "text/") || mediaType == "application/xml" || strings.HasSuffix(mediaType, "+xml"):
+		e = e.Bytes(zerowrap.FieldRequestBody, c.RequestBody)
+	}
+	return e
+}
+
+// redactBodyPaths returns RedactBodyPaths, or DefaultRedactBodyPaths.
+func (o Options) redactBodyPaths() []string {
+	if o.RedactBodyPaths == nil {
+		return DefaultRedactBodyPaths
+	}
+	return o.RedactBodyPaths
+}
+
+// redactBodyKeys returns the keys of the top-level and any-depth paths of
+// RedactBodyPaths, redacted in form bodies.
+func (o Options) redactBodyKeys() []string {
+	keys := []string{}
+	for _, p := range o.redactBodyPaths() {
+		segs := parseBodyPath(p)
+		switch {
+		case len(segs) == 1 && segs[0] != "*":
+			keys = append(keys, segs[0])
+		case len(segs) == 2 && segs[0] == "**":
+			keys = append(keys, segs[1])
+		}
+	}
+	return keys
+}
+
+// redactJSONBody returns body with the values at RedactBodyPaths
+// replaced by zerowrap.Redacted. ok is false if body is not valid JSON.
+func (o Options) redactJSONBody(body []byte) (out []byte, ok bool) {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil || dec.More() {
+		return nil, false
+	}
+	redacted := 0
+	for _, p := range o.redactBodyPaths() {
+		var n int
+		v, n = redactPath(v, parseBodyPath(p))
+		redacted += n
+	}
+	if redacted == 0 {
+		return bytes.TrimSpace(body), true
+	}
+	zerowrap.ReportRedacted(redacted)
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, false
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), true
+}
+
+// parseBodyPath splits a path such as "$.user.password",
+// "$.cards[*].number" or "$..token" into segments, with "*" matching any
+// key or index and "**" any depth.
+func parseBodyPath(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+	path = strings.ReplaceAll(path, "..", ".**.")
+	var segs []string
+	for _, s := range strings.Split(path, ".") {
+		if s != "" {
+			segs = append(segs, s)
+		}
+	}
+	return segs
+}
+
+// redactPath replaces the values of v at path with zerowrap.Redacted,
+// returning the new v and the number of values replaced. Keys match
+// case-insensitively.
+func redactPath(v any, path []string) (any, int) {
+	if len(path) == 0 {
+		return zerowrap.Redacted, 1
+	}
+	seg, rest := path[0], path[1:]
+	n := 0
+	if seg == "**" {
+		v, n = redactPath(v, rest)
+		rest = path // descend keeping the any-depth segment
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		for k, child := range t {
+			if seg == "**" || seg == "*" || strings.EqualFold(k, seg) {
+				var m int
+				t[k], m = redactPath(child, rest)
+				n += m
+			}
+		}
+	case []any:
+		for i, child := range t {
+			if seg == "**" || seg == "*" || seg == strconv.Itoa(i) {
+				var m int
+				t[i], m = redactPath(child, rest)
+				n += m
+			}
+		}
+	}
+	return v, n
+}
+
+// multipartSummary returns the name, file name, content type and size
+// of each part of a multipart body, stopping at the first part cut by
+// the capture limit.
+func multipartSummary(body []byte, boundary string) []map[string]any {
+	if boundary == "" {
+		return nil
+	}
+	var parts []map[string]any
+	mr := multipart.NewReader(bytes.NewReader(body), boundary)
+	for {
+		part, err := mr.NextPart()
+		if err != nil {
+			return parts
+		}
+		size, err := io.Copy(io.Discard, part)
+		if err != nil {
+			return parts
+		}
+		p := map[string]any{"name": part.FormName(), zerowrap.FieldSize: size}
+		if name := part.FileName(); name != "" {
+			p["filename"] = name
+		}
+		if ct := part.Header.Get("Content-Type"); ct != "" {
+			p["content_type"] = ct
+		}
+		parts = append(parts, p)
+	}
+}
@@ -0,0 +1,80 @@
+package httplog
+
+import (
+	"net"
+	"net/netip"
+	"strings"
+)
+
+// Client IP sources for Options.ClientIPSource.
+const (
+	// ClientIPRemoteAddr uses the connection's remote address.
+	ClientIPRemoteAddr = "remote_addr"
+
+	// ClientIPForwardedFor uses the rightmost X-Forwarded-For address
+	// that is not a trusted proxy.
+	ClientIPForwardedFor = "x-forwarded-for"
+
+	// ClientIPRealIP uses the X-Real-IP header (nginx).
+	ClientIPRealIP = "x-real-ip"
+
+	// ClientIPCloudflare uses the CF-Connecting-IP header.
+	ClientIPCloudflare = "cf-connecting-ip"
+)
+
+// ClientIP resolves the client IP of a request from its remote address
+// and headers read through get, following Options.ClientIPSource.
+// Headers are only trusted when the remote address is in
+// Options.TrustedProxies; otherwise, or when the header is missing or
+// invalid, the remote address is returned.
+func (o Options) ClientIP(remoteAddr string, get func(string) string) string {
+	remote := remoteIP(remoteAddr)
+	source := strings.ToLower(o.ClientIPSource)
+	if source == "" || source == ClientIPRemoteAddr || !o.trusted(remote) {
+		return remote
+	}
+
+	switch source {
+	case ClientIPForwardedFor:
+		hops := strings.Split(get("X-Forwarded-For"), ",")
+		for i := len(hops) - 1; i >= 0; i-- {
+			hop := strings.TrimSpace(hops[i])
+			if _, err := netip.ParseAddr(hop); err != nil {
+				break
+			}
+			if !o.trusted(hop) {
+				return hop
+			}
+		}
+	case ClientIPRealIP, ClientIPCloudflare:
+		ip := strings.TrimSpace(get(source))
+		if _, err := netip.ParseAddr(ip); err == nil {
+			return ip
+		}
+	}
+	return remote
+}
+
+// trusted reports whether ip is in TrustedProxies.
+func (o Options) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range o.TrustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP strips the port from a RemoteAddr value.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
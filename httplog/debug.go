@@ -0,0 +1,84 @@
+package httplog
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// DefaultDebugHeader is the header read for debug tokens.
+const DefaultDebugHeader = "X-Debug-Token"
+
+// NewDebugToken returns a token elevating the logger of requests carrying
+// it to level (debug or trace) until ttl has passed. It is signed with
+// key, which must match Options.DebugKey.
+//
+//	token := httplog.NewDebugToken(key, zerolog.DebugLevel, 15*time.Minute)
+//	// curl -H "X-Debug-Token: $token" https://api.example.com/orders
+func NewDebugToken(key []byte, level zerolog.Level, ttl time.Duration) string {
+	payload := level.String() + "." + strconv.FormatInt(time.Now().Add(ttl).Unix(), 10)
+	return payload + "." + debugSignature(key, payload)
+}
+
+// DebugLevel returns the level of a valid debug token read through get.
+// Returns false if debug tokens are disabled, or the token is absent,
+// expired, badly signed or not for debug or trace level.
+func (o Options) DebugLevel(get func(string) string) (zerolog.Level, bool) {
+	if len(o.DebugKey) == 0 {
+		return zerolog.NoLevel, false
+	}
+	name := o.DebugHeader
+	if name == "" {
+		name = DefaultDebugHeader
+	}
+	token := get(name)
+	if token == "" {
+		return zerolog.NoLevel, false
+	}
+
+	i := strings.LastIndexByte(token, '.')
+	if i < 0 {
+		return zerolog.NoLevel, false
+	}
+	payload, sig := token[:i], token[i+1:]
+	if !hmac.Equal([]byte(sig), []byte(debugSignature(o.DebugKey, payload))) {
+		return zerolog.NoLevel, false
+	}
+	levelName, exp, ok := strings.Cut(payload, ".")
+	if !ok {
+		return zerolog.NoLevel, false
+	}
+	expiry, err := strconv.ParseInt(exp, 10, 64)
+	if err != nil || time.Now().Unix() > expiry {
+		return zerolog.NoLevel, false
+	}
+	level, err := zerolog.ParseLevel(levelName)
+	if err != nil || (level != zerolog.DebugLevel && level != zerolog.TraceLevel) {
+		return zerolog.NoLevel, false
+	}
+	return level, true
+}
+
+// WithDebug returns ctx with its logger set to the level of a valid debug
+// token read through get, or ctx unchanged if there is none.
+func (o Options) WithDebug(ctx context.Context, get func(string) string) context.Context {
+	level, ok := o.DebugLevel(get)
+	if !ok {
+		return ctx
+	}
+	return zerowrap.WithCtxZerolog(ctx, zerolog.Ctx(ctx).Level(level))
+}
+
+// debugSignature returns the base64url HMAC-SHA256 of payload.
+func debugSignature(key []byte, payload string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,151 @@
+// Package httplog provides net/http request logging middleware for zerowrap.
+//
+// The middleware enriches the request context logger with request_id,
+// method, path and client_ip, so every downstream log carries them, and
+// logs one completion event per request with status, size and duration.
+// Server errors log at error level, client errors and slow requests at warn.
+//
+// # Usage
+//
+//	mux := http.NewServeMux()
+//	mux.HandleFunc("GET /users/{id}", getUser)
+//
+//	handler := httplog.Middleware(httplog.Options{
+//	    SlowThreshold: 500 * time.Millisecond,
+//	    Headers:       []string{"User-Agent", "Authorization"}, // Authorization is redacted
+//	})(mux)
+//
+//	srv := &http.Server{
+//	    Handler: handler,
+//	    BaseContext: func(net.Listener) context.Context {
+//	        return zerowrap.WithCtx(context.Background(), log)
+//	    },
+//	}
+//
+// # ID Propagation
+//
+// The request ID is read from X-Request-ID (or generated) and the
+// correlation ID from X-Correlation-ID. Both are stored in the request
+// context, so outbound calls made with zerowrap.InjectHTTP or a
+// LoggingTransport with PropagateIDs carry them to downstream services.
+//
+// Set TraceParent to also log trace_id and span_id from the W3C
+// traceparent header, for services without a tracing SDK.
+//
+// # Excluding Noise
+//
+// Drop successful health checks, metrics scrapes, static assets and CORS
+// preflights (or log them at trace with DemoteExcluded). Errors and slow
+// requests on those paths are still logged:
+//
+//	opts := httplog.Options{
+//	    ExcludePaths:   []string{"/healthz", "/metrics", "/static/*"},
+//	    ExcludeMethods: []string{http.MethodOptions},
+//	}
+//
+// # Sampling
+//
+// On high-traffic routes, log only a fraction of successful requests;
+// errors and slow requests are always logged:
+//
+//	opts := httplog.Options{
+//	    SampleRate:  0.1,                                   // 10% of 2xx/3xx requests
+//	    SampleRates: map[string]float64{"GET /healthz": 0}, // none for health checks
+//	}
+//
+// # Client IP
+//
+// By default client_ip is the connection's remote address. Behind load
+// balancers, read it from a header set by trusted proxies:
+//
+//	opts := httplog.Options{
+//	    ClientIPSource: httplog.ClientIPForwardedFor,
+//	    TrustedProxies: []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+//	}
+//
+// Headers from other remote addresses are ignored, so clients cannot
+// spoof their IP.
+//
+// # Geo Context
+//
+// Set GeoIP to add the country, city and ASN of the client IP to the
+// request logger (geo_country, geo_city, asn, as_org), resolved with
+// the application's MaxMind reader (see zerowrap.GeoResolver):
+//
+//	opts := httplog.Options{GeoIP: geoDB}
+//
+// # User Agents
+//
+// Set UserAgent to parse the User-Agent header into ua_browser, ua_os and
+// ua_device fields. BasicUAParser recognizes major browsers, systems and
+// bots without dependencies; wrap a parser library for more:
+//
+//	opts := httplog.Options{UserAgent: httplog.BasicUAParser{}}
+//
+// # Route Templates
+//
+// With net/http's ServeMux the matched pattern is logged as route.
+// For other routers set Options.RoutePattern, or use the chilog and
+// echolog sub-packages.
+//
+// # API Operations
+//
+// Set Operations to log the OpenAPI operationId of each request as
+// operation, on every entry of the request, so logs and metrics join on
+// stable names instead of URLs holding IDs:
+//
+//	spec, _ := os.ReadFile("openapi.json")
+//	ops, err := httplog.OperationsFromOpenAPI(spec)
+//	opts := httplog.Options{Operations: ops}
+//
+// NewOperationTable builds the table from method and path templates;
+// OperationMatcherFunc wraps an OpenAPI router.
+//
+// # Per-Request Debug
+//
+// Set DebugKey to let a request opt into debug logging in production,
+// without changing global verbosity. Requests carrying a valid signed
+// token in the X-Debug-Token header get their context logger elevated
+// to the token's level (debug or trace) until the token expires:
+//
+//	opts := httplog.Options{DebugKey: []byte(os.Getenv("LOG_DEBUG_KEY"))}
+//
+//	// issue a token, e.g. from an admin endpoint or CLI
+//	token := httplog.NewDebugToken(opts.DebugKey, zerolog.DebugLevel, 15*time.Minute)
+//
+// zerolog's global level (zerolog.SetGlobalLevel) still applies.
+//
+// # Error Responses
+//
+// Set ErrorBodyLimit to log the start of 5xx response bodies with the
+// completion event (response_body field):
+//
+//	opts := httplog.Options{ErrorBodyLimit: 4096}
+//
+// # Request Bodies
+//
+// Set RequestBodyLimit to log the start of request bodies (request_body
+// field), depending on their content type: JSON with RedactBodyPaths
+// redacted, forms with those keys redacted, multipart forms as part
+// names and sizes only, and text as is:
+//
+//	opts := httplog.Options{
+//	    RequestBodyLimit: 8192,
+//	    RedactBodyPaths:  []string{"$..password", "$.cards[*].number"},
+//	}
+//
+// # Panic Recovery
+//
+// Recoverer recovers handler panics, logs them at error level with the
+// panic value, stack and redacted request headers, and replies 500.
+// Place it inside Middleware so the completion log records the 500:
+//
+//	opts := httplog.Options{DumpOnPanic: true} // add a replayable request dump
+//	handler := httplog.Middleware(opts)(httplog.Recoverer(opts)(mux))
+//
+// # Other Routers
+//
+// Options exposes its building blocks (RequestID, WithIDs, HeaderFields,
+// Query, WithDebug, WithGeo, WithUserAgent, Level, Log) so adapters for routers that do not use
+// net/http can share the same redaction and level rules.
+package httplog
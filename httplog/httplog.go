@@ -0,0 +1,447 @@
+package httplog
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"net/netip"
+	"strings"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// DefaultRequestIDHeader is the header used to read incoming request IDs.
+const DefaultRequestIDHeader = "X-Request-ID"
+
+// DefaultRedactHeaders lists headers redacted by default.
+var DefaultRedactHeaders = []string{
+	"Authorization",
+	"Cookie",
+	"Proxy-Authorization",
+	"Set-Cookie",
+	"X-Api-Key",
+}
+
+// Options configures the HTTP request logging middleware.
+type Options struct {
+	// RoutePattern returns the matched route template (e.g. "/users/{id}").
+	// It is called after the handler returns, once routing has completed.
+	// Defaults to the net/http ServeMux pattern (r.Pattern).
+	RoutePattern func(r *http.Request) string
+
+	// SlowThreshold logs requests taking longer than this at warn level.
+	// Disabled if 0.
+	SlowThreshold time.Duration
+
+	// SampleRate is the fraction (0 to 1) of successful requests whose
+	// completion is logged. Errors (status >= 400) and slow requests are
+	// always logged.
+	// Defaults to 1 (every request) if 0.
+	SampleRate float64
+
+	// SampleRates overrides SampleRate per route, keyed by the route as
+	// logged (e.g. "GET /healthz" with ServeMux, "/healthz" with chi).
+	// A rate of 0 drops every successful request on that route.
+	SampleRates map[string]float64
+
+	// ExcludePaths lists paths whose successful requests are not logged,
+	// such as "/healthz" and "/metrics". A trailing "*" matches a prefix,
+	// e.g. "/static/*". Errors and slow requests are still logged.
+	ExcludePaths []string
+
+	// ExcludeMethods lists methods whose successful requests are not
+	// logged, such as "OPTIONS" for CORS preflights.
+	ExcludeMethods []string
+
+	// ExcludeFunc excludes successful requests like ExcludePaths, for
+	// rules a path list cannot express.
+	ExcludeFunc func(method, path string) bool
+
+	// DemoteExcluded logs excluded requests at trace level instead of
+	// dropping them.
+	DemoteExcluded bool
+
+	// RequestIDHeader is the header used to read the request ID.
+	// Defaults to DefaultRequestIDHeader. A new ID is generated if absent.
+	RequestIDHeader string
+
+	// Headers lists request headers to include in the completion log.
+	Headers []string
+
+	// ClientIPSource selects where the client_ip field is read from:
+	// ClientIPRemoteAddr, ClientIPForwardedFor, ClientIPRealIP or
+	// ClientIPCloudflare. Headers are only read on requests coming from
+	// TrustedProxies.
+	// Defaults to ClientIPRemoteAddr if empty.
+	ClientIPSource string
+
+	// TrustedProxies lists the load balancer and proxy networks whose
+	// client IP headers are trusted (see ClientIPSource).
+	TrustedProxies []netip.Prefix
+
+	// GeoIP adds the country, city and ASN of the client IP to the
+	// request logger (geo_country, geo_city, asn, as_org fields), e.g.
+	// from a MaxMind database (see zerowrap.GeoResolver). Private and
+	// loopback addresses are not looked up.
+	GeoIP zerowrap.GeoResolver
+
+	// UserAgent parses the User-Agent header into ua_browser, ua_os and
+	// ua_device fields on the request logger, for API analytics from
+	// logs. Use BasicUAParser, or wrap a parser library. Disabled if nil.
+	UserAgent UAParser
+
+	// RedactHeaders lists headers whose values are replaced by zerowrap.Redacted.
+	// Defaults to DefaultRedactHeaders if nil.
+	RedactHeaders []string
+
+	// RedactQuery lists query parameters whose values are replaced by zerowrap.Redacted.
+	// Defaults to zerowrap.DefaultRedactQueryKeys if nil.
+	RedactQuery []string
+
+	// TraceParent adds trace_id and span_id from the W3C traceparent
+	// header to the request logger, for log/trace correlation without a
+	// tracing SDK (see zerowrap.CtxWithTraceParent). Leave it off when an
+	// OTel middleware already binds spans to the context.
+	TraceParent bool
+
+	// DebugKey enables per-request debug logging: requests carrying a
+	// token signed with this key (see NewDebugToken) get their context
+	// logger elevated to the token's level. Disabled if empty.
+	DebugKey []byte
+
+	// DebugHeader is the header read for debug tokens.
+	// Defaults to DefaultDebugHeader if empty.
+	DebugHeader string
+
+	// DumpOnPanic makes Recoverer log a replayable dump of the request
+	// (redacted headers and query, and the body) with each panic. The
+	// body is buffered before the handler runs.
+	DumpOnPanic bool
+
+	// MaxDumpBody caps the body bytes buffered for DumpOnPanic.
+	// Defaults to DefaultMaxDumpBody if 0.
+	MaxDumpBody int64
+
+	// ErrorBodyLimit captures up to this many bytes of 5xx response
+	// bodies into the completion log (response_body field), so production
+	// errors can be debugged without reproducing the request.
+	// Disabled if 0.
+	ErrorBodyLimit int
+
+	// Operations resolves the API operation of each request, e.g. from
+	// an OpenAPI document (see OperationsFromOpenAPI), and adds its
+	// operationId to the request logger (operation field), so logs and
+	// metrics join on stable operation names rather than raw URLs. The
+	// matched path template is also logged as route when RoutePattern
+	// finds none. Ignored by fiberlog, which does not use net/http.
+	Operations OperationMatcher
+
+	// RequestBodyLimit captures up to this many bytes of request bodies
+	// into the completion log (request_body field), for debugging
+	// integrations. JSON bodies are logged with RedactBodyPaths redacted,
+	// forms with their keys redacted, multipart forms as part names and
+	// sizes only, text as is; other content types are not logged. The
+	// body is buffered before the handler runs.
+	// Disabled if 0.
+	RequestBodyLimit int
+
+	// RedactBodyPaths lists the JSON paths of request body values
+	// replaced by zerowrap.Redacted, e.g. "$.password",
+	// "$.cards[*].number", or "$..token" for a key at any depth. Keys
+	// match case-insensitively. Top-level and any-depth keys are also
+	// redacted in forms.
+	// Defaults to DefaultRedactBodyPaths if nil.
+	RedactBodyPaths []string
+}
+
+// Completion describes a finished request, as passed to Options.Log.
+type Completion struct {
+	// Method and Path identify the request for ExcludePaths,
+	// ExcludeMethods and ExcludeFunc. They are not logged again, as the
+	// context logger carries them.
+	Method string
+	Path   string
+
+	Route    string
+	Query    string
+	Status   int
+	Bytes    int64
+	Duration time.Duration
+	Headers  map[string]any
+	Err      error
+
+	// ResponseBody is the captured start of a 5xx response body
+	// (see Options.ErrorBodyLimit).
+	ResponseBody []byte
+
+	// RequestBody is the captured start of the request body, of type
+	// RequestContentType, cut if RequestBodyTruncated
+	// (see Options.RequestBodyLimit).
+	RequestBody          []byte
+	RequestContentType   string
+	RequestBodyTruncated bool
+}
+
+// Middleware returns net/http middleware that attaches request fields
+// (request_id, method, path, client_ip) to the context logger and logs
+// request completion with status, size and duration.
+//
+// The logger is taken from the request context, so the server's base
+// context (or an outer middleware) must carry one:
+//
+//	handler := httplog.Middleware(httplog.Options{})(mux)
+func Middleware(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+
+			ip := opts.ClientIP(r.RemoteAddr, r.Header.Get)
+			ctx := opts.WithIDs(r.Context(), r.Header.Get)
+			ctx = zerowrap.CtxWithFields(ctx, map[string]any{
+				zerowrap.FieldMethod:   r.Method,
+				zerowrap.FieldPath:     r.URL.Path,
+				zerowrap.FieldClientIP: ip,
+			})
+			ctx = opts.WithGeo(ctx, ip)
+			ctx = opts.WithUserAgent(ctx, r.Header.Get)
+			ctx, op := opts.WithOperation(ctx, r)
+			ctx = opts.WithDebug(ctx, r.Header.Get)
+			r = r.WithContext(ctx)
+			body, truncated := opts.CaptureBody(r)
+
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK, bodyLimit: opts.ErrorBodyLimit}
+			next.ServeHTTP(rw, r)
+
+			route := opts.routePattern(r)
+			if route == "" {
+				route = op.Route
+			}
+			opts.Log(zerowrap.FromCtx(ctx), Completion{
+				Method:               r.Method,
+				Path:                 r.URL.Path,
+				Route:                route,
+				Query:                opts.Query(r.URL.RawQuery),
+				Status:               rw.status,
+				Bytes:                rw.bytes,
+				Duration:             time.Since(start),
+				Headers:              opts.HeaderFields(r.Header.Get),
+				ResponseBody:         rw.body,
+				RequestBody:          body,
+				RequestContentType:   r.Header.Get("Content-Type"),
+				RequestBodyTruncated: truncated,
+			})
+		})
+	}
+}
+
+// Log writes the request completion event for c.
+// Server errors log at error level, client errors and slow requests at warn,
+// everything else at info.
+// Successful excluded requests (see Excluded) are skipped or logged at
+// trace level, and completions not kept by Sampled are skipped.
+func (o Options) Log(log zerowrap.Logger, c Completion) {
+	level := o.Level(c.Status, c.Duration)
+	switch {
+	case level <= zerolog.InfoLevel && o.Excluded(c.Method, c.Path):
+		if !o.DemoteExcluded {
+			return
+		}
+		level = zerolog.TraceLevel
+	case !o.Sampled(c):
+		zerowrap.ReportSuppressed(1)
+		return
+	}
+	e := log.WithLevel(level)
+	if c.Route != "" {
+		e = e.Str(zerowrap.FieldRoute, c.Route)
+	}
+	if c.Query != "" {
+		e = e.Str(zerowrap.FieldQueryStr, c.Query)
+	}
+	if len(c.Headers) > 0 {
+		e = e.Fields(map[string]any{zerowrap.FieldHeaders: c.Headers})
+	}
+	if c.Err != nil {
+		e = e.Err(c.Err)
+	}
+	e = o.addRequestBody(e, c)
+	if len(c.ResponseBody) > 0 {
+		e = e.Bytes(zerowrap.FieldResponseBody, c.ResponseBody)
+	}
+	e = zerowrap.AddSize(e.Int(zerowrap.FieldStatus, c.Status), c.Bytes)
+	zerowrap.AddDuration(e, c.Duration).Msg("request completed")
+}
+
+// Excluded reports whether requests with method and path match
+// ExcludeMethods, ExcludePaths or ExcludeFunc.
+func (o Options) Excluded(method, path string) bool {
+	for _, m := range o.ExcludeMethods {
+		if strings.EqualFold(m, method) {
+			return true
+		}
+	}
+	for _, p := range o.ExcludePaths {
+		if prefix, ok := strings.CutSuffix(p, "*"); ok {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+		} else if p == path {
+			return true
+		}
+	}
+	return o.ExcludeFunc != nil && o.ExcludeFunc(method, path)
+}
+
+// Sampled reports whether the completion c is logged under the sampling
+// rates. Requests logged above info level are always kept.
+func (o Options) Sampled(c Completion) bool {
+	if o.Level(c.Status, c.Duration) > zerolog.InfoLevel {
+		return true
+	}
+	rate, ok := o.SampleRates[c.Route]
+	if !ok {
+		rate = o.SampleRate
+		if rate == 0 {
+			return true
+		}
+	}
+	return rate >= 1 || rand.Float64() < rate
+}
+
+// Level returns the completion log level for a response status and duration.
+func (o Options) Level(status int, dur time.Duration) zerolog.Level {
+	switch {
+	case status >= 500:
+		return zerolog.ErrorLevel
+	case status >= 400:
+		return zerolog.WarnLevel
+	case o.SlowThreshold > 0 && dur >= o.SlowThreshold:
+		return zerolog.WarnLevel
+	default:
+		return zerolog.InfoLevel
+	}
+}
+
+// RequestID returns the request ID read through get, or a new ID if absent.
+func (o Options) RequestID(get func(string) string) string {
+	name := o.RequestIDHeader
+	if name == "" {
+		name = DefaultRequestIDHeader
+	}
+	if id := get(name); id != "" {
+		return id
+	}
+	return zerowrap.NewID()
+}
+
+// WithIDs returns ctx carrying the request ID (see RequestID) and the
+// correlation ID read through get, if any, so they are logged and
+// propagated to outbound calls by zerowrap.InjectHTTP.
+// With Options.TraceParent, the W3C trace context is read as well.
+func (o Options) WithIDs(ctx context.Context, get func(string) string) context.Context {
+	ctx = zerowrap.CtxWithRequestID(ctx, o.RequestID(get))
+	if id := get(zerowrap.HeaderCorrelationID); id != "" {
+		ctx = zerowrap.CtxWithCorrelationID(ctx, id)
+	}
+	if o.TraceParent {
+		ctx = zerowrap.CtxWithTraceParent(ctx, get)
+	}
+	return ctx
+}
+
+// WithGeo returns ctx with the geo fields of the client ip added to its
+// logger when Options.GeoIP is set.
+func (o Options) WithGeo(ctx context.Context, ip string) context.Context {
+	return zerowrap.CtxWithGeo(ctx, o.GeoIP, ip)
+}
+
+// HeaderFields returns the configured headers read through get, redacted.
+// Returns nil if no headers are configured or present.
+func (o Options) HeaderFields(get func(string) string) map[string]any {
+	if len(o.Headers) == 0 {
+		return nil
+	}
+	var fields map[string]any
+	for _, name := range o.Headers {
+		v := get(name)
+		if v == "" {
+			continue
+		}
+		if fields == nil {
+			fields = make(map[string]any, len(o.Headers))
+		}
+		fields[http.CanonicalHeaderKey(name)] = o.HeaderValue(name, v)
+	}
+	return fields
+}
+
+// HeaderValue returns value, or zerowrap.Redacted if name is a redacted header.
+func (o Options) HeaderValue(name, value string) string {
+	redact := o.RedactHeaders
+	if redact == nil {
+		redact = DefaultRedactHeaders
+	}
+	for _, h := range redact {
+		if http.CanonicalHeaderKey(h) == http.CanonicalHeaderKey(name) {
+			zerowrap.ReportRedacted(1)
+			return zerowrap.Redacted
+		}
+	}
+	return value
+}
+
+// Query returns rawQuery with the configured parameters redacted.
+func (o Options) Query(rawQuery string) string {
+	return zerowrap.RedactQuery(rawQuery, o.RedactQuery)
+}
+
+// routePattern resolves the route template for r.
+func (o Options) routePattern(r *http.Request) string {
+	if o.RoutePattern != nil {
+		return o.RoutePattern(r)
+	}
+	return r.Pattern
+}
+
+// responseWriter records the status code and body size of a response,
+// and the start of 5xx bodies up to bodyLimit bytes.
+type responseWriter struct {
+	http.ResponseWriter
+	status      int
+	bytes       int64
+	wroteHeader bool
+	bodyLimit   int
+	body        []byte
+}
+
+func (w *responseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status = status
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	if w.status >= 500 && len(w.body) < w.bodyLimit {
+		w.body = append(w.body, b[:min(n, w.bodyLimit-len(w.body))]...)
+	}
+	return n, err
+}
+
+// Flush implements http.Flusher when the underlying writer supports it.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the underlying writer for http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,223 @@
+package httplog
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+
+	"github.com/bnema/zerowrap"
+)
+
+// Operation is the API operation matched for a request.
+type Operation struct {
+	// ID is the OpenAPI operationId, e.g. "getUser".
+	ID string
+
+	// Route is the path template, e.g. "/users/{id}".
+	Route string
+}
+
+// OperationMatcher resolves the API operation of a request for
+// Options.Operations. OperationTable implements it from an OpenAPI
+// document; wrap an OpenAPI router (e.g. kin-openapi) to use its
+// matching instead:
+//
+//	router, _ := gorillamux.NewRouter(doc)
+//	opts := httplog.Options{Operations: httplog.OperationMatcherFunc(func(r *http.Request) (httplog.Operation, bool) {
+//	    route, _, err := router.FindRoute(r)
+//	    if err != nil {
+//	        return httplog.Operation{}, false
+//	    }
+//	    return httplog.Operation{ID: route.Operation.OperationID, Route: route.Path}, true
+//	})}
+type OperationMatcher interface {
+	MatchOperation(r *http.Request) (Operation, bool)
+}
+
+// OperationMatcherFunc adapts a function to an OperationMatcher.
+type OperationMatcherFunc func(r *http.Request) (Operation, bool)
+
+// MatchOperation implements OperationMatcher.
+func (f OperationMatcherFunc) MatchOperation(r *http.Request) (Operation, bool) {
+	return f(r)
+}
+
+// OperationTable matches requests against path templates, without
+// depending on a router. Literal segments take precedence over
+// templated ones, as in OpenAPI: "/users/me" matches before
+// "/users/{id}".
+type OperationTable struct {
+	ops []tableOp
+}
+
+// tableOp is an operation of an OperationTable.
+type tableOp struct {
+	method string
+	segs   []string
+	op     Operation
+}
+
+// NewOperationTable returns a table of operation IDs keyed by method and
+// path template.
+//
+//	ops := httplog.NewOperationTable(map[string]string{
+//	    "GET /users/{id}":  "getUser",
+//	    "POST /users":      "createUser",
+//	    "GET /users/me":    "getCurrentUser",
+//	})
+func NewOperationTable(ops map[string]string) *OperationTable {
+	t := &OperationTable{}
+	for key, id := range ops {
+		method, path, ok := strings.Cut(key, " ")
+		if !ok {
+			method, path = "", key
+		}
+		t.add(method, path, id)
+	}
+	t.sort()
+	return t
+}
+
+// OperationsFromOpenAPI returns a table of the operations of an OpenAPI 3
+// or Swagger 2 document in JSON. Paths are prefixed with the path of the
+// first server URL (or basePath). Operations without an operationId are
+// matched for their route only.
+//
+//	spec, _ := os.ReadFile("openapi.json")
+//	ops, err := httplog.OperationsFromOpenAPI(spec)
+//	opts := httplog.Options{Operations: ops}
+func OperationsFromOpenAPI(spec []byte) (*OperationTable, error) {
+	var doc struct {
+		Servers []struct {
+			URL string `json:"url"`
+		} `json:"servers"`
+		BasePath string                                `json:"basePath"`
+		Paths    map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("httplog: parse OpenAPI document: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, errors.New("httplog: OpenAPI document has no paths")
+	}
+	prefix := doc.BasePath
+	if len(doc.Servers) > 0 {
+		if u, err := url.Parse(doc.Servers[0].URL); err == nil {
+			prefix = u.Path
+		}
+	}
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	t := &OperationTable{}
+	for path, item := range doc.Paths {
+		for method, raw := range item {
+			switch method {
+			case "get", "put", "post", "delete", "options", "head", "patch", "trace":
+			default:
+				continue // parameters, servers, summary...
+			}
+			var op struct {
+				OperationID string `json:"operationId"`
+			}
+			_ = json.Unmarshal(raw, &op)
+			t.add(strings.ToUpper(method), prefix+path, op.OperationID)
+		}
+	}
+	t.sort()
+	return t, nil
+}
+
+// MatchOperation implements OperationMatcher.
+func (t *OperationTable) MatchOperation(r *http.Request) (Operation, bool) {
+	segs := splitPath(r.URL.Path)
+	for _, op := range t.ops {
+		if op.method != "" && op.method != r.Method {
+			continue
+		}
+		if matchSegments(op.segs, segs) {
+			return op.op, true
+		}
+	}
+	return Operation{}, false
+}
+
+// add adds an operation to the table.
+func (t *OperationTable) add(method, path, id string) {
+	t.ops = append(t.ops, tableOp{
+		method: strings.ToUpper(method),
+		segs:   splitPath(path),
+		op:     Operation{ID: id, Route: path},
+	})
+}
+
+// sort orders the operations so that literal segments are tried before
+// templated ones, left to right.
+func (t *OperationTable) sort() {
+	slices.SortStableFunc(t.ops, func(a, b tableOp) int {
+		for i := 0; i < min(len(a.segs), len(b.segs)); i++ {
+			if pa, pb := isParam(a.segs[i]), isParam(b.segs[i]); pa != pb {
+				if pb {
+					return -1
+				}
+				return 1
+			}
+		}
+		if c := cmp.Compare(len(b.segs), len(a.segs)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.op.Route, b.op.Route)
+	})
+}
+
+// matchSegments reports whether path segments match template segments,
+// each {param} matching one non-empty segment.
+func matchSegments(template, path []string) bool {
+	if len(template) != len(path) {
+		return false
+	}
+	for i, seg := range template {
+		if isParam(seg) {
+			if path[i] == "" {
+				return false
+			}
+			continue
+		}
+		if seg != path[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// isParam reports whether a template segment is a {param}.
+func isParam(seg string) bool {
+	return strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}")
+}
+
+// splitPath splits a path into segments, ignoring the leading slash.
+func splitPath(path string) []string {
+	return strings.Split(strings.TrimPrefix(path, "/"), "/")
+}
+
+// WithOperation returns ctx with the operation ID matched by
+// Options.Operations added to its logger (operation field), and the
+// matched operation.
+func (o Options) WithOperation(ctx context.Context, r *http.Request) (context.Context, Operation) {
+	if o.Operations == nil {
+		return ctx, Operation{}
+	}
+	op, ok := o.Operations.MatchOperation(r)
+	if !ok {
+		return ctx, Operation{}
+	}
+	if op.ID != "" {
+		ctx = zerowrap.CtxWithField(ctx, zerowrap.FieldOperation, op.ID)
+	}
+	return ctx, op
+}
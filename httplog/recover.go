@@ -0,0 +1,123 @@
+package httplog
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"runtime/debug"
+
+	"github.com/bnema/zerowrap"
+)
+
+// Field names logged by Recoverer.
+const (
+	FieldPanic       = "panic"
+	FieldRequestDump = "request_dump"
+)
+
+// DefaultMaxDumpBody is the request body size kept for panic dumps.
+const DefaultMaxDumpBody = 64 << 10
+
+// Recoverer returns net/http middleware that recovers panics in next,
+// logs them at error level with the panic value, stack and request
+// headers (redacted as configured), and replies 500 if no response was
+// started. http.ErrAbortHandler is re-panicked, as net/http expects.
+//
+// Place it inside Middleware so the panic entry carries the request
+// fields and the completion log records the 500:
+//
+//	handler := httplog.Middleware(opts)(httplog.Recoverer(opts)(mux))
+//
+// With Options.DumpOnPanic the entry also holds a replayable dump of the
+// request (request_dump field), body included up to Options.MaxDumpBody.
+func Recoverer(opts Options) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body []byte
+			if opts.DumpOnPanic && r.Body != nil && r.Body != http.NoBody {
+				body = opts.bufferBody(r)
+			}
+			rw := &responseWriter{ResponseWriter: w, status: http.StatusOK}
+
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				log := zerowrap.FromCtx(r.Context())
+				e := log.Error().
+					Str(FieldPanic, fmt.Sprint(rec)).
+					Str(zerowrap.FieldStack, string(debug.Stack()))
+				if headers := opts.allHeaderFields(r.Header); len(headers) > 0 {
+					e = e.Fields(map[string]any{zerowrap.FieldHeaders: headers})
+				}
+				if opts.DumpOnPanic {
+					e = e.Str(FieldRequestDump, opts.dump(r, body))
+				}
+				e.Msg("panic recovered")
+
+				if !rw.wroteHeader {
+					http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+				}
+			}()
+
+			next.ServeHTTP(rw, r)
+		})
+	}
+}
+
+// bufferBody reads up to MaxDumpBody bytes of the request body for a
+// panic dump, leaving the full body readable by the handler.
+func (o Options) bufferBody(r *http.Request) []byte {
+	limit := o.MaxDumpBody
+	if limit == 0 {
+		limit = DefaultMaxDumpBody
+	}
+	body, _ := io.ReadAll(io.LimitReader(r.Body, limit))
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(body), r.Body), r.Body}
+	return body
+}
+
+// allHeaderFields returns every request header, redacted.
+func (o Options) allHeaderFields(h http.Header) map[string]any {
+	if len(h) == 0 {
+		return nil
+	}
+	fields := make(map[string]any, len(h))
+	for name, values := range h {
+		if len(values) > 0 {
+			fields[name] = o.HeaderValue(name, values[0])
+		}
+	}
+	return fields
+}
+
+// dump renders r in wire format with redacted headers and query, and the
+// buffered body.
+func (o Options) dump(r *http.Request, body []byte) string {
+	c := r.Clone(r.Context())
+	c.URL.RawQuery = o.Query(r.URL.RawQuery)
+	c.RequestURI = ""
+	for name, values := range c.Header {
+		for i, v := range values {
+			values[i] = o.HeaderValue(name, v)
+		}
+	}
+	c.Body = io.NopCloser(bytes.NewReader(body))
+	c.ContentLength = int64(len(body))
+	c.TransferEncoding = nil
+	b, err := httputil.DumpRequest(c, true)
+	if err != nil {
+		return err.Error()
+	}
+	return string(b)
+}
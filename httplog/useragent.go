@@ -0,0 +1,146 @@
+package httplog
+
+import (
+	"context"
+	"strings"
+
+	"github.com/bnema/zerowrap"
+)
+
+// UserAgent is a parsed User-Agent header. Empty values are not logged.
+type UserAgent struct {
+	// Browser is the client name, e.g. "Chrome", "Firefox" or "curl".
+	Browser string
+
+	// OS is the operating system, e.g. "Windows", "iOS" or "Linux".
+	OS string
+
+	// Device is the device class: "desktop", "mobile", "tablet" or "bot".
+	Device string
+}
+
+// UAParser parses User-Agent headers for Options.UserAgent. Wrap a
+// full parser library (e.g. ua-parser) to recognize more clients than
+// BasicUAParser:
+//
+//	parser := uaparser.NewFromSaved()
+//	opts := httplog.Options{UserAgent: httplog.UAParserFunc(func(ua string) httplog.UserAgent {
+//	    c := parser.Parse(ua)
+//	    return httplog.UserAgent{Browser: c.UserAgent.Family, OS: c.Os.Family, Device: c.Device.Family}
+//	})}
+type UAParser interface {
+	ParseUA(ua string) UserAgent
+}
+
+// UAParserFunc adapts a function to a UAParser.
+type UAParserFunc func(ua string) UserAgent
+
+// ParseUA implements UAParser.
+func (f UAParserFunc) ParseUA(ua string) UserAgent {
+	return f(ua)
+}
+
+// BasicUAParser recognizes the major browsers, operating systems, bots
+// and command-line clients by substring, without dependencies.
+type BasicUAParser struct{}
+
+// uaBrowsers maps User-Agent tokens to browser names, in match order:
+// most browsers also announce the ones they derive from.
+var uaBrowsers = []struct{ token, name string }{
+	{"Googlebot", "Googlebot"},
+	{"bingbot", "Bingbot"},
+	{"Edg/", "Edge"},
+	{"OPR/", "Opera"},
+	{"SamsungBrowser/", "Samsung Internet"},
+	{"Firefox/", "Firefox"},
+	{"Chrome/", "Chrome"},
+	{"CriOS/", "Chrome"},
+	{"Safari/", "Safari"},
+	{"curl/", "curl"},
+	{"Wget/", "Wget"},
+	{"Go-http-client/", "Go"},
+	{"python-requests/", "python-requests"},
+	{"okhttp/", "OkHttp"},
+	{"PostmanRuntime/", "Postman"},
+}
+
+// uaOSes maps User-Agent tokens to operating systems, in match order.
+var uaOSes = []struct{ token, name string }{
+	{"Windows", "Windows"},
+	{"iPhone", "iOS"},
+	{"iPad", "iOS"},
+	{"Android", "Android"},
+	{"CrOS", "ChromeOS"},
+	{"Mac OS X", "macOS"},
+	{"Macintosh", "macOS"},
+	{"Linux", "Linux"},
+}
+
+// ParseUA implements UAParser.
+func (BasicUAParser) ParseUA(ua string) UserAgent {
+	if ua == "" {
+		return UserAgent{}
+	}
+	var u UserAgent
+	for _, b := range uaBrowsers {
+		if strings.Contains(ua, b.token) {
+			u.Browser = b.name
+			break
+		}
+	}
+	for _, o := range uaOSes {
+		if strings.Contains(ua, o.token) {
+			u.OS = o.name
+			break
+		}
+	}
+
+	lower := strings.ToLower(ua)
+	switch {
+	case strings.Contains(lower, "bot") || strings.Contains(lower, "spider") || strings.Contains(lower, "crawler"):
+		u.Device = "bot"
+	case strings.Contains(ua, "iPad") || strings.Contains(ua, "Tablet") ||
+		strings.Contains(ua, "Android") && !strings.Contains(ua, "Mobile"):
+		u.Device = "tablet"
+	case strings.Contains(ua, "Mobi") || strings.Contains(ua, "iPhone"):
+		u.Device = "mobile"
+	case u.OS != "":
+		u.Device = "desktop"
+	}
+	return u
+}
+
+// UserAgentFields returns the ua_browser, ua_os and ua_device fields of
+// the User-Agent header read through get, parsed with Options.UserAgent.
+// Returns nil if no parser is set or nothing was recognized.
+func (o Options) UserAgentFields(get func(string) string) []zerowrap.Field {
+	if o.UserAgent == nil {
+		return nil
+	}
+	ua := get("User-Agent")
+	if ua == "" {
+		return nil
+	}
+	u := o.UserAgent.ParseUA(ua)
+	var fields []zerowrap.Field
+	if u.Browser != "" {
+		fields = append(fields, zerowrap.Str(zerowrap.FieldUABrowser, u.Browser))
+	}
+	if u.OS != "" {
+		fields = append(fields, zerowrap.Str(zerowrap.FieldUAOS, u.OS))
+	}
+	if u.Device != "" {
+		fields = append(fields, zerowrap.Str(zerowrap.FieldUADevice, u.Device))
+	}
+	return fields
+}
+
+// WithUserAgent returns ctx with the User-Agent fields (see
+// UserAgentFields) added to its logger.
+func (o Options) WithUserAgent(ctx context.Context, get func(string) string) context.Context {
+	fields := o.UserAgentFields(get)
+	if len(fields) == 0 {
+		return ctx
+	}
+	return zerowrap.CtxWith(ctx, fields...)
+}
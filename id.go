@@ -0,0 +1,14 @@
+package zerowrap
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// NewID returns a random 16-byte hex-encoded identifier.
+// Suitable for request IDs, run IDs and other correlation values.
+func NewID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
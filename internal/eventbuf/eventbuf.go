@@ -0,0 +1,78 @@
+// Package eventbuf reads the fields buffered in a zerolog.Event, for hooks
+// that forward events to other backends.
+package eventbuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"reflect"
+	"unsafe"
+
+	"github.com/rs/zerolog"
+)
+
+// invalid is the bufOffset of an unexpected zerolog.Event layout.
+const invalid = ^uintptr(0)
+
+// bufOffset is the offset of zerolog.Event's unexported buf field, or
+// invalid if the field layout is not the expected one. The offset is
+// checked against a real event, so a zerolog release moving or changing
+// the field disables Bytes instead of reading other memory. The layout
+// is asserted for the zerolog version required by go.mod by the package
+// tests.
+var bufOffset = func() uintptr {
+	f, ok := reflect.TypeOf(zerolog.Event{}).FieldByName("buf")
+	if !ok || f.Type != reflect.TypeOf([]byte(nil)) {
+		return invalid
+	}
+	log := zerolog.New(io.Discard)
+	e := log.Log().Str("eventbuf", "probe")
+	defer e.Send()
+	buf := *(*[]byte)(unsafe.Add(unsafe.Pointer(e), f.Offset))
+	if !bytes.Equal(buf, []byte(`{"eventbuf":"probe"`)) {
+		return invalid
+	}
+	return f.Offset
+}()
+
+// Bytes returns the JSON fields buffered in e so far. Hooks run before
+// the message is appended, so the buffer holds an unterminated object
+// such as `{"level":"info","user_id":42`. The returned slice aliases the
+// event's buffer and must not be retained or modified.
+func Bytes(e *zerolog.Event) []byte {
+	if e == nil || bufOffset == invalid {
+		return nil
+	}
+	return *(*[]byte)(unsafe.Add(unsafe.Pointer(e), bufOffset))
+}
+
+// JSON returns a terminated copy of the fields buffered in e, or nil if
+// the buffer is not a JSON object (e.g. when zerolog is built with binary
+// encoding).
+func JSON(e *zerolog.Event) []byte {
+	buf := Bytes(e)
+	if len(buf) < 2 || buf[0] != '{' {
+		return nil
+	}
+	data := make([]byte, len(buf)+1)
+	copy(data, buf)
+	data[len(buf)] = '}'
+	return data
+}
+
+// Fields decodes the fields buffered in e. Numbers decode as json.Number.
+// Returns nil if the buffer cannot be parsed.
+func Fields(e *zerolog.Event) map[string]any {
+	data := JSON(e)
+	if data == nil {
+		return nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil
+	}
+	return fields
+}
@@ -0,0 +1,53 @@
+package eventbuf
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// TestEventLayout asserts that the zerolog version required by go.mod
+// keeps the event fields where Bytes reads them. Upgrading zerolog must
+// keep this test passing.
+func TestEventLayout(t *testing.T) {
+	if bufOffset == invalid {
+		t.Fatal("zerolog.Event has no buf field at the expected layout; update internal/eventbuf for this zerolog version")
+	}
+
+	log := zerolog.New(io.Discard)
+	e := log.Info().Str("user", "ada").Int("attempt", 2)
+	defer e.Send()
+	if got, want := string(Bytes(e)), `{"level":"info","user":"ada","attempt":2`; got != want {
+		t.Errorf("Bytes = %s, want %s", got, want)
+	}
+	if got, want := string(JSON(e)), `{"level":"info","user":"ada","attempt":2}`; got != want {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+	if got := Fields(e); got["user"] != "ada" || got["attempt"] != json.Number("2") {
+		t.Errorf("Fields = %v", got)
+	}
+}
+
+// TestHookSeesFields checks Bytes where the hooks of otel and sentry call
+// it: in a hook, before the message is appended.
+func TestHookSeesFields(t *testing.T) {
+	var seen []byte
+	hook := zerolog.HookFunc(func(e *zerolog.Event, _ zerolog.Level, _ string) {
+		seen = bytes.Clone(Bytes(e))
+	})
+	log := zerolog.New(io.Discard).Hook(hook).With().Str("service", "billing").Logger()
+	log.Warn().Str("order", "o-1").Msg("late")
+
+	if want := `{"level":"warn","service":"billing","order":"o-1"`; string(seen) != want {
+		t.Errorf("Bytes in hook = %s, want %s", seen, want)
+	}
+}
+
+func TestNilEvent(t *testing.T) {
+	if Bytes(nil) != nil || JSON(nil) != nil || Fields(nil) != nil {
+		t.Error("nil event returned fields")
+	}
+}
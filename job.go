@@ -0,0 +1,45 @@
+package zerowrap
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"time"
+)
+
+// Job runs fn as a named background job with a per-run context logger.
+// The context passed to fn carries the job and run_id fields. Job logs
+// start and finish events with the duration, recovers panics into errors,
+// and logs any returned error. The error from fn (or the panic) is returned.
+//
+//	err := zerowrap.Job(ctx, "cleanup-sessions", func(ctx context.Context) error {
+//	    log := zerowrap.FromCtx(ctx) // includes job=cleanup-sessions run_id=...
+//	    return store.DeleteExpired(ctx)
+//	})
+func Job(ctx context.Context, name string, fn func(ctx context.Context) error) (err error) {
+	ctx = CtxWithFields(ctx, map[string]any{
+		FieldJob:   name,
+		FieldRunID: NewID(),
+	})
+	log := FromCtx(ctx)
+	start := time.Now()
+	log.Info().Msg("job started")
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job %s panicked: %v", name, r)
+			e := log.Error().
+				Err(err).
+				Str(FieldStack, string(debug.Stack()))
+			AddDuration(e, time.Since(start)).Msg("job panicked")
+			return
+		}
+		if err != nil {
+			AddDuration(log.Error().Err(err), time.Since(start)).Msg("job failed")
+			return
+		}
+		AddDuration(log.Info(), time.Since(start)).Msg("job finished")
+	}()
+
+	return fn(ctx)
+}
@@ -0,0 +1,180 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultJournaldSocket is the systemd journal's native protocol socket.
+const DefaultJournaldSocket = "/run/systemd/journal/socket"
+
+// JournaldConfig holds configuration for journald output.
+type JournaldConfig struct {
+	// Identifier is the SYSLOG_IDENTIFIER of each entry.
+	// Defaults to the executable name if empty.
+	Identifier string
+
+	// Socket is the journal socket path.
+	// Defaults to DefaultJournaldSocket if empty.
+	Socket string
+}
+
+// JournaldWriter is a zerolog.LevelWriter that sends entries to the
+// systemd journal using its native protocol. The message becomes MESSAGE,
+// the level becomes PRIORITY, and other fields are sent as journal fields
+// with upper-cased names (user_id becomes USER_ID), so they can be
+// queried with journalctl USER_ID=42 or shown with journalctl -o json.
+type JournaldWriter struct {
+	identifier string
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewJournaldWriter connects to the systemd journal.
+//
+//	journal, err := zerowrap.NewJournaldWriter(zerowrap.JournaldConfig{Identifier: "billing"})
+//	if err != nil {
+//	    return err
+//	}
+//	defer journal.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: journal})
+func NewJournaldWriter(cfg JournaldConfig) (*JournaldWriter, error) {
+	socket := cfg.Socket
+	if socket == "" {
+		socket = DefaultJournaldSocket
+	}
+	identifier := cfg.Identifier
+	if identifier == "" {
+		identifier = filepath.Base(os.Args[0])
+	}
+
+	conn, err := net.Dial("unixgram", socket)
+	if err != nil {
+		return nil, fmt.Errorf("zerowrap: journald: %w", err)
+	}
+	return &JournaldWriter{identifier: identifier, conn: conn}, nil
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (w *JournaldWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Entries that are not JSON
+// objects are sent as the MESSAGE field.
+func (w *JournaldWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var buf bytes.Buffer
+	journalField(&buf, "PRIORITY", strconv.Itoa(syslogSeverity(level)))
+	journalField(&buf, "SYSLOG_IDENTIFIER", w.identifier)
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		journalField(&buf, "MESSAGE", string(bytes.TrimRight(p, "\n")))
+	} else {
+		for key, val := range fields {
+			switch key {
+			case zerolog.LevelFieldName:
+				continue
+			case zerolog.MessageFieldName:
+				journalField(&buf, "MESSAGE", journalValue(val))
+			default:
+				journalField(&buf, journalKey(key), journalValue(val))
+			}
+		}
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return 0, net.ErrClosed
+	}
+	if _, err := w.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the journal socket.
+func (w *JournaldWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// journalField appends a field in the journal native format. Values with
+// newlines use the length-prefixed binary form.
+func journalField(buf *bytes.Buffer, key, value string) {
+	buf.WriteString(key)
+	if !strings.Contains(value, "\n") {
+		buf.WriteByte('=')
+		buf.WriteString(value)
+		buf.WriteByte('\n')
+		return
+	}
+	buf.WriteByte('\n')
+	_ = binary.Write(buf, binary.LittleEndian, uint64(len(value)))
+	buf.WriteString(value)
+	buf.WriteByte('\n')
+}
+
+// journalKey converts a field name to a valid journal field name:
+// upper-case letters, digits and underscores, not starting with an
+// underscore or digit.
+func journalKey(key string) string {
+	b := make([]byte, 0, len(key))
+	for i := 0; i < len(key); i++ {
+		c := key[i]
+		switch {
+		case c >= 'a' && c <= 'z':
+			b = append(b, c-'a'+'A')
+		case c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+			b = append(b, c)
+		default:
+			b = append(b, '_')
+		}
+	}
+	s := strings.TrimLeft(string(b), "_")
+	if s == "" || (s[0] >= '0' && s[0] <= '9') {
+		s = "F_" + s
+	}
+	if len(s) > 64 {
+		s = s[:64]
+	}
+	return s
+}
+
+// journalValue formats a decoded JSON value: strings as-is, everything
+// else as JSON.
+func journalValue(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(b)
+	}
+}
@@ -0,0 +1,169 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ANSI colors used by the json-pretty format, besides the pretty ones.
+const (
+	colorGreen   = 32
+	colorYellow  = 33
+	colorMagenta = 35
+)
+
+// jsonPrettyWriter writes JSON entries with syntax highlighting, and
+// optionally indented, keeping every field as logged.
+type jsonPrettyWriter struct {
+	out         io.Writer
+	indent      bool
+	noColor     bool
+	levelColors map[zerolog.Level]int
+}
+
+// newJSONPrettyWriter returns a writer rendering the "json-pretty" format.
+func newJSONPrettyWriter(out io.Writer, cfg ConsoleConfig) io.Writer {
+	return jsonPrettyWriter{
+		out:         out,
+		indent:      cfg.Indent,
+		noColor:     cfg.NoColor || os.Getenv("NO_COLOR") != "",
+		levelColors: cfg.LevelColors,
+	}
+}
+
+// Write implements io.Writer. Entries that are not valid JSON are written
+// unchanged.
+func (w jsonPrettyWriter) Write(p []byte) (int, error) {
+	src := bytes.TrimRight(p, "\n")
+	if !json.Valid(src) {
+		return w.out.Write(p)
+	}
+	if w.indent {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, src, "", "  "); err == nil {
+			src = buf.Bytes()
+		}
+	}
+
+	out := src
+	if !w.noColor {
+		out = w.highlight(src)
+	}
+	if _, err := w.out.Write(append(out, '\n')); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// highlight colors the keys and values of the valid JSON src: keys in
+// cyan, strings in green, numbers in yellow, booleans and null in
+// magenta, and the top-level level value in its level color.
+func (w jsonPrettyWriter) highlight(src []byte) []byte {
+	dst := make([]byte, 0, len(src)*2)
+	depth := 0
+	levelValue := false
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == '"':
+			end := stringEnd(src, i)
+			token := src[i:end]
+			if isKey(src, end) {
+				levelValue = depth == 1 && string(token) == `"`+zerolog.LevelFieldName+`"`
+				dst = w.appendColor(dst, token, colorCyan)
+			} else {
+				color := colorGreen
+				if levelValue {
+					color = w.levelColor(rawString(token))
+				}
+				dst = w.appendColor(dst, token, color)
+				levelValue = false
+			}
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(src) && strings.IndexByte("0123456789.eE+-", src[end]) >= 0 {
+				end++
+			}
+			dst = w.appendColor(dst, src[i:end], colorYellow)
+			levelValue = false
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i + 1
+			for end < len(src) && src[end] >= 'a' && src[end] <= 'z' {
+				end++
+			}
+			dst = w.appendColor(dst, src[i:end], colorMagenta)
+			levelValue = false
+			i = end
+		default:
+			switch c {
+			case '{', '[':
+				depth++
+			case '}', ']':
+				depth--
+			}
+			dst = append(dst, c)
+			i++
+		}
+	}
+	return dst
+}
+
+// levelColor returns the color of a level name.
+func (w jsonPrettyWriter) levelColor(name string) int {
+	level, err := zerolog.ParseLevel(name)
+	if err != nil {
+		return colorGreen
+	}
+	if c, ok := w.levelColors[level]; ok {
+		return c
+	}
+	return zerolog.LevelColors[level]
+}
+
+// appendColor appends token to dst wrapped in an ANSI color sequence.
+func (w jsonPrettyWriter) appendColor(dst, token []byte, c int) []byte {
+	if c == 0 {
+		return append(dst, token...)
+	}
+	dst = append(dst, "\x1b["...)
+	dst = strconv.AppendInt(dst, int64(c), 10)
+	dst = append(dst, 'm')
+	dst = append(dst, token...)
+	return append(dst, "\x1b[0m"...)
+}
+
+// stringEnd returns the index after the JSON string starting at src[i].
+func stringEnd(src []byte, i int) int {
+	for j := i + 1; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '"':
+			return j + 1
+		}
+	}
+	return len(src)
+}
+
+// isKey reports whether the string ending at src[end] is an object key,
+// i.e. is followed by a colon.
+func isKey(src []byte, end int) bool {
+	for ; end < len(src); end++ {
+		switch src[end] {
+		case ' ', '\t', '\n', '\r':
+			continue
+		case ':':
+			return true
+		}
+		return false
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package zerowrap
+
+import (
+	"os"
+	"strings"
+)
+
+// k8sNamespaceFile holds the pod namespace in pods with a mounted
+// service account token.
+const k8sNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// K8sEnv lists, for each Kubernetes field, the environment variables
+// read for its value, in order. Expose them in the pod spec with the
+// downward API:
+//
+//	env:
+//	  - name: POD_NAME
+//	    valueFrom: {fieldRef: {fieldPath: metadata.name}}
+//	  - name: POD_NAMESPACE
+//	    valueFrom: {fieldRef: {fieldPath: metadata.namespace}}
+//	  - name: NODE_NAME
+//	    valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+//	  - name: CONTAINER_NAME
+//	    value: api
+var K8sEnv = map[string][]string{
+	FieldK8sPod:       {"POD_NAME", "K8S_POD_NAME", "MY_POD_NAME"},
+	FieldK8sNamespace: {"POD_NAMESPACE", "K8S_NAMESPACE", "MY_POD_NAMESPACE"},
+	FieldK8sNode:      {"NODE_NAME", "K8S_NODE_NAME", "MY_NODE_NAME"},
+	FieldK8sContainer: {"CONTAINER_NAME", "K8S_CONTAINER_NAME"},
+}
+
+// InKubernetes reports whether the process runs in a Kubernetes pod.
+func InKubernetes() bool {
+	return os.Getenv("KUBERNETES_SERVICE_HOST") != ""
+}
+
+// K8sFields returns the pod, namespace, node and container of the
+// current pod, read from the environment variables in K8sEnv. Outside
+// Kubernetes it returns nil. In a pod, the pod name falls back to
+// HOSTNAME and the namespace to the service account namespace file.
+func K8sFields() map[string]any {
+	if !InKubernetes() {
+		return nil
+	}
+	fields := make(map[string]any, len(K8sEnv))
+	for field, names := range K8sEnv {
+		for _, name := range names {
+			if v := os.Getenv(name); v != "" {
+				fields[field] = v
+				break
+			}
+		}
+	}
+	if _, ok := fields[FieldK8sPod]; !ok {
+		if host := os.Getenv("HOSTNAME"); host != "" {
+			fields[FieldK8sPod] = host
+		}
+	}
+	if _, ok := fields[FieldK8sNamespace]; !ok {
+		if b, err := os.ReadFile(k8sNamespaceFile); err == nil {
+			fields[FieldK8sNamespace] = strings.TrimSpace(string(b))
+		}
+	}
+	return fields
+}
+
+// WithK8sFields returns a new logger with the Kubernetes fields of
+// K8sFields, so logs can be correlated without relying on the collector
+// to add them. The logger is returned unchanged outside Kubernetes.
+//
+//	log = zerowrap.WithK8sFields(log)
+func WithK8sFields(log Logger) Logger {
+	fields := K8sFields()
+	if len(fields) == 0 {
+		return log
+	}
+	return log.WithFields(fields)
+}
@@ -0,0 +1,24 @@
+package zerowrap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestIncludeK8sFieldOrder(t *testing.T) {
+	t.Setenv("KUBERNETES_SERVICE_HOST", "10.0.0.1")
+	t.Setenv("POD_NAME", "api-7d9f")
+	t.Setenv("POD_NAMESPACE", "shop")
+	t.Setenv("NODE_NAME", "node-1")
+	t.Setenv("CONTAINER_NAME", "api")
+
+	want := `"k8s_container":"api","k8s_namespace":"shop","k8s_node":"node-1","k8s_pod":"api-7d9f"`
+	for range 20 {
+		var buf bytes.Buffer
+		log := New(Config{Format: "json", Output: &buf, IncludeK8s: true})
+		log.Info().Msg("ready")
+		if !bytes.Contains(buf.Bytes(), []byte(want)) {
+			t.Fatalf("entry %s, want fields in key order %s", buf.Bytes(), want)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+// Package kafkalog publishes zerolog JSON entries to a Kafka topic, for
+// log pipelines that start at Kafka.
+//
+// The package does not depend on a Kafka client: implement Producer on
+// top of the client you already use (segmentio/kafka-go, franz-go,
+// confluent-kafka-go, sarama).
+//
+// # Usage
+//
+//	kw, err := kafkalog.NewWriter(kafkalog.Config{
+//	    Producer: producer,
+//	    Topic:    "app-logs",
+//	    KeyField: zerowrap.FieldRequestID, // same request, same partition
+//	    Fallback: os.Stderr,               // failed batches are written here
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer kw.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: kw,
+//	})
+//
+// # Delivery
+//
+// Entries are batched (BatchSize or FlushInterval, whichever comes first)
+// and published from a background goroutine. Batches the producer fails to
+// publish are written to Fallback and reported through OnError; entries
+// are dropped when the queue is full (see Dropped).
+package kafkalog
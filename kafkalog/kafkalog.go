@@ -0,0 +1,304 @@
+package kafkalog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bnema/zerowrap"
+)
+
+// ErrClosed is returned by Flush after the writer was closed.
+var ErrClosed = errors.New("kafkalog: writer is closed")
+
+// Message is a record published to Kafka.
+type Message struct {
+	Topic string
+	Key   []byte
+	Value []byte
+}
+
+// Producer publishes batches of messages. Adapt your Kafka client to it,
+// e.g. for segmentio/kafka-go:
+//
+//	type producer struct{ w *kafka.Writer }
+//
+//	func (p producer) Produce(ctx context.Context, msgs []kafkalog.Message) error {
+//	    out := make([]kafka.Message, len(msgs))
+//	    for i, m := range msgs {
+//	        out[i] = kafka.Message{Topic: m.Topic, Key: m.Key, Value: m.Value}
+//	    }
+//	    return p.w.WriteMessages(ctx, out...)
+//	}
+type Producer interface {
+	Produce(ctx context.Context, msgs []Message) error
+}
+
+// Config holds configuration for the Kafka writer.
+type Config struct {
+	// Producer publishes the messages. Required.
+	Producer Producer
+
+	// Topic is the destination topic. Required.
+	Topic string
+
+	// KeyField is the entry field used as message key, so related entries
+	// land in the same partition and keep their order.
+	// Defaults to zerowrap.FieldRequestID if empty. Entries without the
+	// field are sent without key.
+	KeyField string
+
+	// BatchSize is the number of entries per Produce call.
+	// Defaults to 100 if 0.
+	BatchSize int
+
+	// FlushInterval is the maximum time an entry waits before being sent.
+	// Defaults to 1 second if 0.
+	FlushInterval time.Duration
+
+	// QueueSize is the number of entries buffered while batches are in flight.
+	// Defaults to 10000 if 0.
+	QueueSize int
+
+	// Timeout bounds each Produce call.
+	// Defaults to 10 seconds if 0.
+	Timeout time.Duration
+
+	// Fallback receives the entries of batches that failed to publish,
+	// e.g. os.Stderr or a local file. Failed entries are dropped if nil.
+	Fallback io.Writer
+
+	// OnError is called when a batch fails to publish.
+	// Defaults to reporting the error with zerowrap.ReportError if nil.
+	OnError func(error)
+}
+
+// Writer is an io.Writer that publishes JSON entries to a Kafka topic.
+type Writer struct {
+	cfg Config
+	key []byte
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan []byte
+	flushes chan chan struct{}
+	done    chan struct{}
+	drops   atomic.Int64
+}
+
+// NewWriter creates a Kafka writer and starts its background publisher.
+// Call Close before exit to publish buffered entries.
+//
+//	kw, err := kafkalog.NewWriter(kafkalog.Config{
+//	    Producer: producer{w: &kafka.Writer{Addr: kafka.TCP("kafka:9092")}},
+//	    Topic:    "app-logs",
+//	    Fallback: os.Stderr,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer kw.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: kw})
+func NewWriter(cfg Config) (*Writer, error) {
+	if cfg.Producer == nil {
+		return nil, errors.New("kafkalog: producer is required")
+	}
+	if cfg.Topic == "" {
+		return nil, errors.New("kafkalog: topic is required")
+	}
+	if cfg.KeyField == "" {
+		cfg.KeyField = zerowrap.FieldRequestID
+	}
+	if cfg.BatchSize == 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.FlushInterval == 0 {
+		cfg.FlushInterval = time.Second
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = 10000
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error) {
+			zerowrap.ReportError(fmt.Errorf("%w: %w", zerowrap.ErrWrite, err))
+		}
+	}
+
+	w := &Writer{
+		cfg:     cfg,
+		key:     []byte(`"` + cfg.KeyField + `":`),
+		queue:   make(chan []byte, cfg.QueueSize),
+		flushes: make(chan chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write implements io.Writer. Entries are dropped (see Dropped) when the
+// queue is full.
+func (w *Writer) Write(p []byte) (int, error) {
+	entry := bytes.Clone(bytes.TrimRight(p, "\n"))
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.drop(1)
+		return len(p), nil
+	}
+	select {
+	case w.queue <- entry:
+	default:
+		w.drop(1)
+	}
+	return len(p), nil
+}
+
+// Flush publishes all buffered entries and waits for completion.
+func (w *Writer) Flush(ctx context.Context) error {
+	w.mu.RLock()
+	closed := w.closed
+	w.mu.RUnlock()
+	if closed {
+		return ErrClosed
+	}
+
+	flushed := make(chan struct{})
+	select {
+	case w.flushes <- flushed:
+	case <-w.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close publishes buffered entries and stops the writer.
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the queue was
+// full, or a batch failed without fallback writer.
+func (w *Writer) Dropped() int64 {
+	return w.drops.Load()
+}
+
+// drop counts n dropped entries and reports them with zerowrap.ErrDropped.
+func (w *Writer) drop(n int) {
+	w.drops.Add(int64(n))
+	zerowrap.ReportError(fmt.Errorf("%w: kafkalog: %d entries", zerowrap.ErrDropped, n))
+}
+
+// run batches queued entries into Produce calls.
+func (w *Writer) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.FlushInterval)
+	defer ticker.Stop()
+
+	var batch []Message
+	send := func() {
+		if len(batch) > 0 {
+			w.publish(batch)
+			batch = nil
+		}
+	}
+
+	for {
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				send()
+				return
+			}
+			batch = append(batch, w.message(entry))
+			if len(batch) >= w.cfg.BatchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case flushed := <-w.flushes:
+			for drained := false; !drained; {
+				select {
+				case entry, ok := <-w.queue:
+					if !ok {
+						drained = true
+						break
+					}
+					batch = append(batch, w.message(entry))
+				default:
+					drained = true
+				}
+			}
+			send()
+			close(flushed)
+		}
+	}
+}
+
+// message builds the Kafka message for an entry.
+func (w *Writer) message(entry []byte) Message {
+	return Message{Topic: w.cfg.Topic, Key: w.entryKey(entry), Value: entry}
+}
+
+// entryKey returns the value of the key field when it is a JSON string.
+func (w *Writer) entryKey(entry []byte) []byte {
+	i := bytes.Index(entry, w.key)
+	if i < 0 {
+		return nil
+	}
+	rest := entry[i+len(w.key):]
+	if len(rest) == 0 || rest[0] != '"' {
+		return nil
+	}
+	end := bytes.IndexByte(rest[1:], '"')
+	if end < 0 {
+		return nil
+	}
+	return rest[1 : end+1]
+}
+
+// publish sends a batch, writing it to the fallback writer on failure.
+func (w *Writer) publish(batch []Message) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.cfg.Timeout)
+	defer cancel()
+
+	err := w.cfg.Producer.Produce(ctx, batch)
+	if err == nil {
+		return
+	}
+	w.cfg.OnError(fmt.Errorf("kafkalog: publish %d entries: %w", len(batch), err))
+	if w.cfg.Fallback == nil {
+		w.drop(len(batch))
+		return
+	}
+	for _, m := range batch {
+		_, _ = w.cfg.Fallback.Write(append(m.Value, '\n'))
+	}
+}
@@ -0,0 +1,36 @@
+// Package lambdalog provides AWS Lambda handler logging for zerowrap.
+//
+// This is an optional sub-package that adds the aws-lambda-go dependency.
+// It wraps typed Lambda handlers so every invocation gets a context logger
+// carrying aws_request_id, function_name, function_version and cold_start,
+// logs one completion event per invocation, and flushes output before the
+// execution environment is frozen.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/aws/aws-lambda-go/lambda"
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/lambdalog"
+//	)
+//
+//	func main() {
+//	    log := lambdalog.NewLogger("info")
+//	    lambda.Start(lambdalog.Wrap(log, handle, lambdalog.Options{}))
+//	}
+//
+//	func handle(ctx context.Context, req Request) (Response, error) {
+//	    log := zerowrap.FromCtx(ctx)
+//	    log.Info().Msg("handling request") // includes aws_request_id, cold_start, ...
+//	    return Response{}, nil
+//	}
+//
+// # CloudWatch Logs Insights
+//
+// NewLogger emits one JSON object per line on stdout, which Logs Insights
+// parses into fields automatically:
+//
+//	fields @timestamp, level, message, aws_request_id, duration_ms
+//	| filter cold_start = 1
+//	| sort duration_ms desc
+package lambdalog
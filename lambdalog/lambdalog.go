@@ -0,0 +1,121 @@
+package lambdalog
+
+import (
+	"context"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"github.com/bnema/zerowrap"
+)
+
+// Field names added to every invocation's context logger.
+const (
+	FieldAWSRequestID    = "aws_request_id"
+	FieldFunctionName    = "function_name"
+	FieldFunctionVersion = "function_version"
+	FieldColdStart       = "cold_start"
+	FieldXRayTraceID     = "xray_trace_id"
+)
+
+// Handler is a typed Lambda handler, as accepted by lambda.Start.
+type Handler[TIn, TOut any] func(ctx context.Context, in TIn) (TOut, error)
+
+// Options configures the Lambda handler wrapper.
+type Options struct {
+	// Flush is called before each invocation returns, so buffered log output
+	// reaches CloudWatch before the execution environment is frozen.
+	// If nil, stdout is synced.
+	Flush func() error
+}
+
+// coldStart is true until the first invocation in this execution environment.
+var coldStart atomic.Bool
+
+func init() {
+	coldStart.Store(true)
+}
+
+// NewLogger returns a JSON logger writing to stdout in a shape CloudWatch
+// Logs Insights discovers automatically (level, message, time with
+// sub-second precision).
+func NewLogger(level string) zerowrap.Logger {
+	return zerowrap.New(zerowrap.Config{
+		Level:      level,
+		Format:     "json",
+		TimeFormat: time.RFC3339Nano,
+		Output:     os.Stdout,
+	})
+}
+
+// Wrap returns a handler that seeds each invocation's context with log and
+// the invocation metadata (aws_request_id, function_name, function_version,
+// cold_start, xray_trace_id), logs completion with duration and error,
+// and flushes output before returning.
+//
+//	func main() {
+//	    log := lambdalog.NewLogger("info")
+//	    lambda.Start(lambdalog.Wrap(log, handle, lambdalog.Options{}))
+//	}
+//
+//	func handle(ctx context.Context, evt events.SQSEvent) (string, error) {
+//	    log := zerowrap.FromCtx(ctx) // includes aws_request_id, cold_start, ...
+//	    log.Info().Int("records", len(evt.Records)).Msg("processing batch")
+//	    return "ok", nil
+//	}
+func Wrap[TIn, TOut any](log zerowrap.Logger, h Handler[TIn, TOut], opts Options) Handler[TIn, TOut] {
+	return func(ctx context.Context, in TIn) (out TOut, err error) {
+		start := time.Now()
+		ctx = zerowrap.WithCtx(ctx, log)
+		ctx = zerowrap.CtxWithFields(ctx, invocationFields(ctx))
+		l := zerowrap.FromCtx(ctx)
+
+		defer func() {
+			if r := recover(); r != nil {
+				zerowrap.AddDuration(l.Error().Interface("panic", r), time.Since(start)).
+					Msg("invocation panicked")
+				flush(opts)
+				panic(r)
+			}
+
+			e := l.Info()
+			if err != nil {
+				e = l.Error().Err(err)
+			}
+			zerowrap.AddDuration(e, time.Since(start)).Msg("invocation completed")
+			flush(opts)
+		}()
+
+		return h(ctx, in)
+	}
+}
+
+// invocationFields returns the Lambda metadata fields for ctx.
+func invocationFields(ctx context.Context) map[string]any {
+	fields := map[string]any{
+		FieldColdStart: coldStart.Swap(false),
+	}
+	if lambdacontext.FunctionName != "" {
+		fields[FieldFunctionName] = lambdacontext.FunctionName
+	}
+	if lambdacontext.FunctionVersion != "" {
+		fields[FieldFunctionVersion] = lambdacontext.FunctionVersion
+	}
+	if lc, ok := lambdacontext.FromContext(ctx); ok {
+		fields[FieldAWSRequestID] = lc.AwsRequestID
+	}
+	if traceID, ok := ctx.Value("x-amzn-trace-id").(string); ok && traceID != "" {
+		fields[FieldXRayTraceID] = traceID
+	}
+	return fields
+}
+
+// flush runs the configured flush function, or syncs stdout.
+func flush(opts Options) {
+	if opts.Flush != nil {
+		_ = opts.Flush()
+		return
+	}
+	_ = os.Stdout.Sync()
+}
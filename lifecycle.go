@@ -0,0 +1,114 @@
+package zerowrap
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// LogLifecycle logs the process start with the pid, Go version and build
+// info (module version, VCS revision), then watches for SIGINT and
+// SIGTERM. The returned context is canceled on the first signal, which
+// is logged; a second signal terminates the process as usual. Call stop
+// once shutdown is complete to log it with the shutdown duration (from
+// the signal) and the process uptime:
+//
+//	ctx, stop := zerowrap.LogLifecycle(zerowrap.WithCtx(context.Background(), log))
+//	defer stop()
+//
+//	go srv.ListenAndServe()
+//	<-ctx.Done()
+//	zerowrap.Shutdown(context.Background(), 10*time.Second, ...)
+func LogLifecycle(ctx context.Context) (context.Context, func()) {
+	log := FromCtx(ctx)
+	start := time.Now()
+
+	started := log.WithTyped(buildInfoFields()...)
+	started.Info().
+		Int(FieldPID, os.Getpid()).
+		Str(FieldGoVersion, runtime.Version()).
+		Msg("process started")
+
+	ctx, cancel := context.WithCancel(ctx)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	var (
+		mu       sync.Mutex
+		signaled time.Time
+		quit     = make(chan struct{})
+	)
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			mu.Lock()
+			signaled = time.Now()
+			mu.Unlock()
+			log.Info().Str(FieldSignal, sig.String()).Msg("signal received, shutting down")
+			cancel()
+		case <-quit:
+		}
+	}()
+
+	var once sync.Once
+	return ctx, func() {
+		once.Do(func() {
+			signal.Stop(signals)
+			close(quit)
+			cancel()
+
+			e := log.Info()
+			mu.Lock()
+			if !signaled.IsZero() {
+				e = AddDuration(e, time.Since(signaled))
+			}
+			mu.Unlock()
+			durToEvent(e, FieldUptime, time.Since(start))
+			e.Msg("process stopped")
+		})
+	}
+}
+
+// ShutdownStep is a subsystem closed by Shutdown.
+type ShutdownStep struct {
+	// Name identifies the subsystem, logged as component.
+	Name string
+
+	// Close releases the subsystem. ctx expires with the shutdown
+	// timeout.
+	Close func(ctx context.Context) error
+}
+
+// Shutdown closes steps in order within timeout, logging each result
+// with the subsystem name and close duration: at info level on success,
+// at error level on failure. Every step is called even if an earlier
+// one failed or the timeout expired. The errors are returned joined.
+//
+//	err := zerowrap.Shutdown(ctx, 10*time.Second,
+//	    zerowrap.ShutdownStep{Name: "http", Close: srv.Shutdown},
+//	    zerowrap.ShutdownStep{Name: "db", Close: func(context.Context) error { return db.Close() }},
+//	)
+func Shutdown(ctx context.Context, timeout time.Duration, steps ...ShutdownStep) error {
+	log := FromCtx(ctx)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeout)
+	defer cancel()
+
+	var errs []error
+	for _, step := range steps {
+		start := time.Now()
+		err := step.Close(ctx)
+		if err != nil {
+			errs = append(errs, err)
+			AddDuration(log.Error().Str(FieldComponent, step.Name).Err(err), time.Since(start)).Msg("subsystem close failed")
+			continue
+		}
+		AddDuration(log.Info().Str(FieldComponent, step.Name), time.Since(start)).Msg("subsystem closed")
+	}
+	return errors.Join(errs...)
+}
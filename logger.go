@@ -1,6 +1,7 @@
 package zerowrap
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"strings"
@@ -16,20 +17,107 @@ type Config struct {
 	// Defaults to "info" if empty or invalid.
 	Level string
 
-	// Format is the output format: "json" or "console".
+	// Format is the output format: "json", "console", "pretty",
+	// "json-pretty", "gcp", "ltsv", "cbor" or "discard".
+	// "pretty" prints the message on one line and fields as an indented
+	// block below it, for local development with many fields.
+	// "json-pretty" prints the JSON entries syntax-highlighted, and
+	// indented with Console.Indent, for local development without losing
+	// any field.
+	// "gcp" writes JSON with the field names Google Cloud Logging expects
+	// (severity, logging.googleapis.com/trace, httpRequest, sourceLocation).
+	// "cbor" writes each entry as a CBOR map (RFC 8949), smaller than its
+	// JSON; decode it with the binlog sub-package. Hooks and writers still
+	// see JSON, so building with zerolog's binary_log tag is not needed.
+	// "ltsv" writes Labeled Tab-separated Values (see LTSVEncoder).
+	// "discard" drops the output, like Discard.
+	// Formats added with RegisterFormat are selected by their name.
 	// Defaults to "console" if empty or invalid.
 	Format string
 
+	// Discard drops all output while events are still built, hooked and
+	// counted (see Counts and Diagnostics), for benchmarking the
+	// logging overhead of an application, or for tests that only check
+	// that something was logged. With NewWithFile, only the console
+	// output is dropped.
+	Discard bool
+
+	// Counts, if set, counts the events of the logger by level.
+	Counts *LevelCounts
+
+	// Diagnostics counts the events of the logger by level in
+	// DiagnosticStats, and enables diagnostics of writer errors (see
+	// EnableDiagnostics).
+	Diagnostics bool
+
 	// TimeFormat is the time format string.
 	// Defaults to time.RFC3339 if empty.
 	TimeFormat string
 
-	// Output is the writer for log output.
+	// Output is the writer for log output. Outputs implementing
+	// JSONOutput, such as logtest.Recorder, always get JSON.
 	// Defaults to os.Stderr if nil.
 	Output io.Writer
 
 	// Caller adds caller information (file:line) to log entries.
 	Caller bool
+
+	// Console customizes the "console" format: colors, part order,
+	// hidden fields and custom formatters.
+	Console ConsoleConfig
+
+	// GCPProject is the Google Cloud project used to build trace resource
+	// names in "gcp" format.
+	// Defaults to the GOOGLE_CLOUD_PROJECT environment variable if empty.
+	GCPProject string
+
+	// IncludeHost adds the hostname to every entry (host field).
+	IncludeHost bool
+
+	// IncludePID adds the process ID to every entry (pid field).
+	IncludePID bool
+
+	// IncludeGoVersion adds the Go runtime version to every entry
+	// (go_version field).
+	IncludeGoVersion bool
+
+	// IncludeBuildInfo adds the main module version and the VCS revision,
+	// commit time and dirty flag embedded at build time to every entry
+	// (module_version, vcs_revision, vcs_time, vcs_modified fields), so
+	// each line identifies the binary that produced it. VCS fields are
+	// only available when built from a repository with go build.
+	IncludeBuildInfo bool
+
+	// IncludeK8s adds the Kubernetes pod, namespace, node and container
+	// to every entry when running in a pod (see K8sFields).
+	IncludeK8s bool
+
+	// GoroutineID adds the ID of the logging goroutine to every event
+	// (goroutine_id field), for debugging concurrency issues. It reads
+	// the goroutine stack on each event, so keep it off in hot paths.
+	GoroutineID bool
+
+	// MaxFieldBytes cuts top-level string and byte field values longer
+	// than this many bytes, and marks the entry with _truncated: true,
+	// protecting sinks from accidentally logged payloads.
+	// Defaults to 0 (no limit).
+	MaxFieldBytes int
+
+	// MaxMessageBytes cuts messages longer than this many bytes, like
+	// MaxFieldBytes.
+	// Defaults to 0 (no limit).
+	MaxMessageBytes int
+
+	// MaxEventBytes caps the size of a whole entry, e.g. to 256 KB for
+	// CloudWatch. Larger entries are replaced by a summary keeping the
+	// level, time, caller and message, with dropped_fields_count and
+	// event_bytes fields, so shipping pipelines never reject them silently.
+	// Defaults to 0 (no limit).
+	MaxEventBytes int
+
+	// DropOversized drops entries over MaxEventBytes instead of
+	// summarizing them, reporting each with ErrDropped.
+	DropOversized bool
 }
 
 // FileConfig holds configuration for file-based logging.
@@ -54,6 +142,30 @@ type FileConfig struct {
 
 	// Compress determines if rotated files should be compressed.
 	Compress bool
+
+	// BufferSize enables buffering of file writes, in bytes. Buffered
+	// entries are written when the buffer fills, every FlushInterval, on
+	// an entry at warn level or above, and by the cleanup function.
+	// Entries below warn still buffered when the process crashes or exits
+	// without calling cleanup are lost.
+	// Defaults to 0 (every entry is written to the file directly).
+	BufferSize int
+
+	// FlushInterval is the maximum time entries stay buffered.
+	// Only used with BufferSize. Defaults to 1 second if 0.
+	FlushInterval time.Duration
+
+	// WriteTimeout bounds each write to the file, e.g. on an NFS mount
+	// that stops responding. Timed-out writes are reported with
+	// ErrWriteTimeout and the entry is dropped from the file while the
+	// console output keeps working (see TimeoutWriter).
+	// Defaults to 0 (no timeout).
+	WriteTimeout time.Duration
+
+	// Sign, if set, signs each file entry for tamper evidence (see
+	// SignedWriter). Unless Sign.Previous is set, a chain continues from
+	// the last entry already in the file.
+	Sign *SignConfig
 }
 
 // New creates a new Logger with the given configuration.
@@ -68,14 +180,29 @@ func New(cfg Config) Logger {
 		timeFormat = time.RFC3339
 	}
 
-	format := strings.ToLower(cfg.Format)
-	if format == "console" || format == "" {
-		output = zerolog.ConsoleWriter{
-			Out:        output,
-			TimeFormat: timeFormat,
+	format := outputFormat(cfg)
+	switch format {
+	case "discard":
+		output = io.Discard
+	case "console", "":
+		output = newConsoleWriter(output, timeFormat, cfg.Console)
+	case "pretty":
+		output = newPrettyWriter(output, timeFormat, cfg.Console)
+	case "json-pretty":
+		output = newJSONPrettyWriter(output, cfg.Console)
+	case "gcp":
+		output = newGCPWriter(output, cfg.GCPProject)
+	case "ltsv":
+		output = NewEncoderWriter(output, LTSVEncoder{})
+	case "cbor":
+		output = newCBORWriter(output)
+	default:
+		if enc, ok := LookupFormat(format); ok {
+			output = NewEncoderWriter(output, enc)
 		}
 	}
 
+	output = limitOutput(output, cfg)
 	level := parseLevel(cfg.Level)
 
 	logger := zerolog.New(output).
@@ -87,6 +214,7 @@ func New(cfg Config) Logger {
 	if cfg.Caller {
 		logger = logger.With().Caller().Logger()
 	}
+	logger = enrich(logger, cfg)
 
 	return Logger{logger}
 }
@@ -141,8 +269,39 @@ func NewWithFile(cfg Config, fileCfg FileConfig) (Logger, func(), error) {
 		Compress:   fileCfg.Compress,
 	}
 
+	var file io.Writer = fileWriter
+	var closer io.Closer = fileWriter
+	if fileCfg.WriteTimeout > 0 {
+		timeout := NewTimeoutWriter(fileWriter, fileCfg.WriteTimeout)
+		file, closer = timeout, timeout
+	}
 	cleanup := func() {
-		_ = fileWriter.Close()
+		_ = closer.Close()
+	}
+	if fileCfg.BufferSize > 0 {
+		interval := fileCfg.FlushInterval
+		if interval == 0 {
+			interval = time.Second
+		}
+		buffered := newBufferedWriter(file, fileCfg.BufferSize, interval)
+		file = buffered
+		cleanup = func() {
+			_ = buffered.Close()
+			_ = closer.Close()
+		}
+	}
+
+	if fileCfg.Sign != nil {
+		sign := *fileCfg.Sign
+		if sign.Previous == "" {
+			prev, err := LastSignature(fileCfg.Path)
+			if err != nil {
+				cleanup()
+				return Logger{}, func() {}, fmt.Errorf("zerowrap: resume signature chain: %w", err)
+			}
+			sign.Previous = prev
+		}
+		file = NewSignedWriter(file, sign)
 	}
 
 	// Determine console output
@@ -156,23 +315,41 @@ func NewWithFile(cfg Config, fileCfg FileConfig) (Logger, func(), error) {
 		timeFormat = time.RFC3339
 	}
 
-	// Create multi-writer: console (formatted) + file (JSON)
-	var writers []io.Writer
-
-	format := strings.ToLower(cfg.Format)
-	if format == "console" || format == "" {
-		writers = append(writers, zerolog.ConsoleWriter{
-			Out:        consoleOutput,
-			TimeFormat: timeFormat,
-		})
-	} else {
-		writers = append(writers, consoleOutput)
+	// Create multi-writer: console (formatted) + file (JSON). Sinks fail
+	// independently, so a read-only file keeps the console working.
+	var console io.Writer
+
+	format := outputFormat(cfg)
+	switch format {
+	case "discard":
+	case "console", "":
+		console = newConsoleWriter(consoleOutput, timeFormat, cfg.Console)
+	case "pretty":
+		console = newPrettyWriter(consoleOutput, timeFormat, cfg.Console)
+	case "json-pretty":
+		console = newJSONPrettyWriter(consoleOutput, cfg.Console)
+	case "gcp":
+		console = newGCPWriter(consoleOutput, cfg.GCPProject)
+	case "ltsv":
+		console = NewEncoderWriter(consoleOutput, LTSVEncoder{})
+	case "cbor":
+		console = newCBORWriter(consoleOutput)
+	default:
+		if enc, ok := LookupFormat(format); ok {
+			console = NewEncoderWriter(consoleOutput, enc)
+		} else {
+			console = consoleOutput
+		}
 	}
 
+	multi := NewMultiWriter()
+	if console != nil {
+		multi.Sink("console", console)
+	}
 	// File always gets JSON format for easy parsing
-	writers = append(writers, fileWriter)
+	multi.Sink("file", file)
 
-	multiWriter := zerolog.MultiLevelWriter(writers...)
+	multiWriter := limitOutput(multi, cfg)
 
 	level := parseLevel(cfg.Level)
 
@@ -185,15 +362,48 @@ func NewWithFile(cfg Config, fileCfg FileConfig) (Logger, func(), error) {
 	if cfg.Caller {
 		logger = logger.With().Caller().Logger()
 	}
+	logger = enrich(logger, cfg)
 
 	return Logger{logger}, cleanup, nil
 }
 
+// JSONOutput is implemented by outputs that parse the entries they
+// receive, such as logtest.Recorder. New and NewWithFile write JSON to
+// them whatever the Format.
+type JSONOutput interface {
+	io.Writer
+	JSONOutput()
+}
+
+// outputFormat returns the lowercased format of cfg: "discard" with
+// Discard, and "json" for a JSONOutput.
+func outputFormat(cfg Config) string {
+	if cfg.Discard {
+		return "discard"
+	}
+	if _, ok := cfg.Output.(JSONOutput); ok {
+		return "json"
+	}
+	return strings.ToLower(cfg.Format)
+}
+
 // WithHook returns a new logger with the hook attached.
 func WithHook(log Logger, hook zerolog.Hook) Logger {
 	return Logger{log.Hook(hook)}
 }
 
+// limitOutput applies the size limits of cfg to output. Fields are cut
+// before the entry size is checked.
+func limitOutput(output io.Writer, cfg Config) io.Writer {
+	if cfg.MaxEventBytes > 0 {
+		output = newSizeLimitWriter(output, cfg.MaxEventBytes, cfg.DropOversized)
+	}
+	if cfg.MaxFieldBytes > 0 || cfg.MaxMessageBytes > 0 {
+		output = newTruncateWriter(output, cfg.MaxFieldBytes, cfg.MaxMessageBytes)
+	}
+	return output
+}
+
 // parseLevel converts a level string to zerolog.Level.
 func parseLevel(level string) zerolog.Level {
 	switch strings.ToLower(level) {
@@ -13,7 +13,8 @@ type Logger struct {
 }
 
 // WrapErr logs the error and returns a wrapped error with the message.
-// Uses fmt.Errorf with %w for unwrapping support.
+// Uses fmt.Errorf with %w for unwrapping support, or a *WrappedError
+// recording the call site when SetErrorCallers is enabled.
 //
 //	log := zerowrap.FromCtx(ctx)
 //	if err != nil {
@@ -23,8 +24,7 @@ func (l Logger) WrapErr(err error, msg string) error {
 	if err == nil {
 		return nil
 	}
-	l.Error().Err(err).Msg(msg)
-	return fmt.Errorf("%s: %w", msg, err)
+	return logAndWrap(&l.Logger, err, msg, 1)
 }
 
 // WrapErrWithFields logs with fields and returns a wrapped error.
@@ -37,12 +37,9 @@ func (l Logger) WrapErrWithFields(err error, msg string, fields map[string]any)
 		return nil
 	}
 	c := l.With()
-	for k, v := range fields {
-		c = addToContext(c, k, v)
-	}
+	c = addFields(c, fields)
 	logger := c.Logger()
-	logger.Error().Err(err).Msg(msg)
-	return fmt.Errorf("%s: %w", msg, err)
+	return logAndWrap(&logger, err, msg, 1)
 }
 
 // WrapErrf logs the error and returns a wrapped error with a formatted message.
@@ -54,9 +51,7 @@ func (l Logger) WrapErrf(err error, format string, args ...any) error {
 	if err == nil {
 		return nil
 	}
-	msg := fmt.Sprintf(format, args...)
-	l.Error().Err(err).Msg(msg)
-	return fmt.Errorf("%s: %w", msg, err)
+	return logAndWrap(&l.Logger, err, fmt.Sprintf(format, args...), 1)
 }
 
 // WithField returns a new Logger with the field added.
@@ -64,15 +59,33 @@ func (l Logger) WithField(key string, value any) Logger {
 	return Logger{addToContext(l.With(), key, value).Logger()}
 }
 
-// WithFields returns a new Logger with the fields added.
+// WithFields returns a new Logger with the fields added, in key order.
 func (l Logger) WithFields(fields map[string]any) Logger {
 	c := l.With()
-	for k, v := range fields {
-		c = addToContext(c, k, v)
-	}
+	c = addFields(c, fields)
 	return Logger{c.Logger()}
 }
 
+// WithRawJSON returns a new Logger with raw embedded as JSON rather than
+// an escaped string, for pre-serialized payloads such as webhook bodies
+// or API responses. Invalid JSON is logged as a string.
+//
+//	log.WithRawJSON(zerowrap.FieldResponseBody, body).Debug().Msg("api response")
+func (l Logger) WithRawJSON(key string, raw []byte) Logger {
+	return Logger{rawJSONToContext(l.With(), key, raw).Logger()}
+}
+
+// WithFieldsOrdered returns a new Logger with the fields added in the
+// given order.
+//
+//	log = log.WithFieldsOrdered([]zerowrap.Field{
+//	    zerowrap.Str(zerowrap.FieldMethod, r.Method),
+//	    zerowrap.Str(zerowrap.FieldPath, r.URL.Path),
+//	})
+func (l Logger) WithFieldsOrdered(fields []Field) Logger {
+	return l.WithTyped(fields...)
+}
+
 // WithStruct returns a new Logger with fields extracted from struct tags.
 func (l Logger) WithStruct(s any) Logger {
 	return l.WithFields(extractFields(s))
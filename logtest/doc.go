@@ -0,0 +1,58 @@
+// Package logtest provides in-memory log recording and assertions for tests.
+//
+// A Recorder is an io.Writer that parses each JSON log line into an Entry,
+// so tests can assert on levels, messages and fields instead of matching
+// raw JSON with regular expressions. zerowrap loggers write JSON to a
+// Recorder whatever their Format.
+//
+// # Usage
+//
+//	func TestPaymentFailure(t *testing.T) {
+//	    rec := logtest.NewRecorder()
+//	    log := zerowrap.New(zerowrap.Config{Output: rec})
+//	    ctx := zerowrap.WithCtx(context.Background(), log)
+//
+//	    _ = svc.Charge(ctx, order)
+//
+//	    rec.AssertContains(t, zerolog.ErrorLevel, "payment failed", map[string]any{
+//	        "order_id": order.ID,
+//	    })
+//
+//	    last, _ := rec.LastEntry()
+//	    if last.Fields["status"] != "declined" {
+//	        t.Errorf("unexpected status %v", last.Fields["status"])
+//	    }
+//	}
+//
+// Recorder.Logger returns a ready-to-use JSON logger at trace level.
+//
+// # Test Loggers
+//
+// NewTestLogger writes through t.Log instead, so logs interleave with the
+// test output and only show for failing tests (or with go test -v);
+// TestConfig.FailOnError also fails the test on error-level events:
+//
+//	log := logtest.NewTestLoggerWithConfig(t, logtest.TestConfig{FailOnError: true})
+//
+// # Golden Files
+//
+// AssertGolden compares the whole log stream against a golden file, which
+// is useful for verifying log contracts consumed by downstream pipelines.
+// Volatile values (timestamps, durations, request and run IDs) are replaced
+// by scrubbers before comparison, and keys are sorted for stable output:
+//
+//	rec.AssertGolden(t, "testdata/checkout.golden")
+//
+//	// Extra scrubbers for application-specific volatile fields
+//	rec.AssertGolden(t, "testdata/checkout.golden", append(logtest.DefaultScrubbers,
+//	    logtest.ScrubField("order_id", "<order_id>"),
+//	    logtest.ScrubRegexp(uuidRE, "<uuid>"),
+//	)...)
+//
+// Run the tests with LOGTEST_UPDATE=1 to write or refresh golden files:
+//
+//	LOGTEST_UPDATE=1 go test ./...
+//
+// logtest registers no flag; if the test package defines its own -update
+// flag, as the common golden-file idiom does, it is honored as well.
+package logtest
@@ -0,0 +1,159 @@
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden write
+// golden files instead of comparing them, when set to a true value
+// ("1", "true"...).
+const UpdateEnv = "LOGTEST_UPDATE"
+
+// Scrubber normalizes a decoded log event before golden comparison.
+// The map holds every field, including level and message.
+type Scrubber func(event map[string]any)
+
+// DefaultScrubbers replaces volatile values: timestamps, durations,
+// request IDs and run IDs.
+var DefaultScrubbers = []Scrubber{
+	ScrubField(zerolog.TimestampFieldName, "<time>"),
+	ScrubField(zerowrap.FieldDuration, "<duration>"),
+	ScrubField(zerowrap.FieldDurationText, "<duration>"),
+	ScrubField(zerowrap.FieldDurationNs, "<duration>"),
+	ScrubField(zerowrap.FieldRequestID, "<request_id>"),
+	ScrubField(zerowrap.FieldRunID, "<run_id>"),
+}
+
+// ScrubField returns a Scrubber that replaces the value of key with
+// placeholder when the field is present.
+func ScrubField(key, placeholder string) Scrubber {
+	return func(event map[string]any) {
+		if _, ok := event[key]; ok {
+			event[key] = placeholder
+		}
+	}
+}
+
+// ScrubRegexp returns a Scrubber that replaces matches of re in all
+// top-level string values (including the message) with replacement.
+func ScrubRegexp(re *regexp.Regexp, replacement string) Scrubber {
+	return func(event map[string]any) {
+		for k, v := range event {
+			if s, ok := v.(string); ok {
+				event[k] = re.ReplaceAllString(s, replacement)
+			}
+		}
+	}
+}
+
+// Golden renders the recorded stream as normalized JSON lines: scrubbers
+// are applied to each event and keys are sorted, so the output is stable
+// across runs.
+// If scrubbers is nil, DefaultScrubbers is used; pass an empty slice to
+// disable scrubbing.
+func (r *Recorder) Golden(scrubbers ...Scrubber) []byte {
+	if scrubbers == nil {
+		scrubbers = DefaultScrubbers
+	}
+
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	for _, e := range r.Entries() {
+		event := make(map[string]any, len(e.Fields)+2)
+		for k, v := range e.Fields {
+			event[k] = v
+		}
+		if e.Level != zerolog.NoLevel {
+			event[zerolog.LevelFieldName] = e.Level.String()
+		}
+		if e.Message != "" {
+			event[zerolog.MessageFieldName] = e.Message
+		}
+		for _, scrub := range scrubbers {
+			scrub(event)
+		}
+		if err := enc.Encode(event); err != nil {
+			b.Write(e.Raw)
+			b.WriteByte('\n')
+		}
+	}
+	return b.Bytes()
+}
+
+// AssertGolden compares the normalized log stream (see Golden) with the
+// golden file at path. Run the tests with LOGTEST_UPDATE=1 (or with the
+// test package's own -update flag) to (re)write the file.
+//
+//	rec.AssertGolden(t, "testdata/checkout.golden")
+//	rec.AssertGolden(t, "testdata/checkout.golden",
+//	    append(logtest.DefaultScrubbers, logtest.ScrubField("order_id", "<order_id>"))...)
+func (r *Recorder) AssertGolden(t testing.TB, path string, scrubbers ...Scrubber) bool {
+	t.Helper()
+	got := r.Golden(scrubbers...)
+
+	if shouldUpdate() {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("logtest: create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("logtest: write golden file: %v", err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Errorf("logtest: read golden file (run with LOGTEST_UPDATE=1 to create it): %v", err)
+		return false
+	}
+	if string(got) == string(want) {
+		return true
+	}
+
+	t.Errorf("log stream does not match %s (run with LOGTEST_UPDATE=1 to accept)\n%s",
+		path, diffLines(string(want), string(got)))
+	return false
+}
+
+// shouldUpdate reports whether golden files should be rewritten: when
+// UpdateEnv is true, or when the test package defines its own -update
+// flag and it is set. logtest registers no flag, so it never conflicts
+// with the test package's.
+func shouldUpdate() bool {
+	if ok, err := strconv.ParseBool(os.Getenv(UpdateEnv)); err == nil && ok {
+		return true
+	}
+	f := flag.Lookup("update")
+	return f != nil && f.Value.String() == "true"
+}
+
+// diffLines describes the first differing line between want and got.
+func diffLines(want, got string) string {
+	wl := strings.Split(want, "\n")
+	gl := strings.Split(got, "\n")
+	for i := 0; i < len(wl) || i < len(gl); i++ {
+		var w, g string
+		if i < len(wl) {
+			w = wl[i]
+		}
+		if i < len(gl) {
+			g = gl[i]
+		}
+		if w != g {
+			return "line " + strconv.Itoa(i+1) + ":\n  want: " + w + "\n  got:  " + g
+		}
+	}
+	return ""
+}
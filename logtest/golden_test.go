@@ -0,0 +1,89 @@
+package logtest_test
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/logtest"
+)
+
+// update is the usual golden-file flag of a test package. Declaring it
+// must not conflict with logtest.
+var update = flag.Bool("update", false, "update golden files")
+
+func record() *logtest.Recorder {
+	rec := logtest.NewRecorder()
+	log := rec.Logger()
+	log.Info().Str(zerowrap.FieldRequestID, "r-1").Float64(zerowrap.FieldDuration, 12.5).Msg("checkout")
+	log.Warn().Int("items", 3).Msg("low stock")
+	return rec
+}
+
+func TestGoldenScrubsVolatileFields(t *testing.T) {
+	got := string(record().Golden())
+	want := `{"duration_ms":"<duration>","level":"info","message":"checkout","request_id":"<request_id>","time":"<time>"}
+{"items":3,"level":"warn","message":"low stock","time":"<time>"}
+`
+	if got != want {
+		t.Errorf("Golden() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAssertGoldenUpdateEnv(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "checkout.golden")
+
+	t.Setenv(logtest.UpdateEnv, "1")
+	if !record().AssertGolden(t, path) {
+		t.Fatal("AssertGolden with LOGTEST_UPDATE=1 failed")
+	}
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden file not written: %v", err)
+	}
+
+	t.Setenv(logtest.UpdateEnv, "")
+	if !record().AssertGolden(t, path) {
+		t.Error("AssertGolden does not match the file it wrote")
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Replace(string(written), "low stock", "out of stock", 1)), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tb := &failRecorder{TB: t}
+	if record().AssertGolden(tb, path) || !strings.Contains(tb.msg, "out of stock") {
+		t.Errorf("AssertGolden matched a different golden file (error: %q)", tb.msg)
+	}
+}
+
+// failRecorder records the failure of an assertion instead of failing
+// the test.
+type failRecorder struct {
+	testing.TB
+	msg string
+}
+
+func (f *failRecorder) Helper() {}
+
+func (f *failRecorder) Errorf(format string, args ...any) {
+	f.msg = fmt.Sprintf(format, args...)
+}
+
+func TestAssertGoldenTestPackageFlag(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "flag.golden")
+	t.Setenv(logtest.UpdateEnv, "")
+
+	*update = true
+	defer func() { *update = false }()
+
+	if !record().AssertGolden(t, path) {
+		t.Fatal("AssertGolden with -update failed")
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("golden file not written with the test package's -update flag: %v", err)
+	}
+}
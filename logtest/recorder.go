@@ -0,0 +1,238 @@
+package logtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Entry is a parsed log event.
+type Entry struct {
+	// Level is the event level. NoLevel if the event has none.
+	Level zerolog.Level
+
+	// Message is the event message.
+	Message string
+
+	// Fields holds all other fields, decoded from JSON
+	// (numbers are float64, objects are map[string]any).
+	Fields map[string]any
+
+	// Raw is the original JSON line.
+	Raw []byte
+}
+
+// Recorder is an io.Writer that records JSON log events in memory.
+// It is safe for concurrent use.
+//
+// It implements zerowrap.JSONOutput, so zerowrap loggers write JSON to it
+// whatever their Format:
+//
+//	rec := logtest.NewRecorder()
+//	log := zerowrap.New(zerowrap.Config{Output: rec})
+type Recorder struct {
+	mu      sync.Mutex
+	entries []Entry
+	errs    []error
+}
+
+// NewRecorder creates an empty Recorder.
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Logger returns a JSON logger at trace level writing to the recorder.
+func (r *Recorder) Logger() zerowrap.Logger {
+	return zerowrap.New(zerowrap.Config{
+		Level:  "trace",
+		Format: "json",
+		Output: r,
+	})
+}
+
+// JSONOutput implements zerowrap.JSONOutput.
+func (r *Recorder) JSONOutput() {}
+
+// Write implements io.Writer. Each line of p is parsed as one event.
+// Lines that are not valid JSON are kept as parse errors (see Errors).
+func (r *Recorder) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	sc := bufio.NewScanner(bytes.NewReader(p))
+	sc.Buffer(make([]byte, 0, 64*1024), len(p)+1)
+	for sc.Scan() {
+		line := bytes.TrimSpace(sc.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		e, err := parseEntry(line)
+		if err != nil {
+			r.errs = append(r.errs, err)
+			continue
+		}
+		r.entries = append(r.entries, e)
+	}
+	return len(p), nil
+}
+
+// Entries returns a copy of all recorded entries, in order.
+func (r *Recorder) Entries() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Entry(nil), r.entries...)
+}
+
+// FilterLevel returns the recorded entries at the given level.
+func (r *Recorder) FilterLevel(level zerolog.Level) []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []Entry
+	for _, e := range r.entries {
+		if e.Level == level {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// LastEntry returns the most recent entry, or false if none was recorded.
+func (r *Recorder) LastEntry() (Entry, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.entries) == 0 {
+		return Entry{}, false
+	}
+	return r.entries[len(r.entries)-1], true
+}
+
+// Errors returns parse errors for lines that were not valid JSON.
+func (r *Recorder) Errors() []error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]error(nil), r.errs...)
+}
+
+// Reset discards all recorded entries and errors.
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.entries = nil
+	r.errs = nil
+}
+
+// Find returns the first entry at level whose message contains msgSubstr
+// and whose fields include all of fields. Returns false if none match.
+func (r *Recorder) Find(level zerolog.Level, msgSubstr string, fields map[string]any) (Entry, bool) {
+	want := normalize(fields)
+	for _, e := range r.Entries() {
+		if e.Matches(level, msgSubstr, want) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// AssertContains fails t unless an entry at level contains msgSubstr in
+// its message and all of fields. Field values are compared after a JSON
+// round-trip, so Go ints match the decoded float64 values.
+//
+//	rec.AssertContains(t, zerolog.ErrorLevel, "payment failed", map[string]any{
+//	    "order_id": 42,
+//	})
+func (r *Recorder) AssertContains(t testing.TB, level zerolog.Level, msgSubstr string, fields map[string]any) bool {
+	t.Helper()
+	if _, ok := r.Find(level, msgSubstr, fields); ok {
+		return true
+	}
+	t.Errorf("no %s log entry with message containing %q and fields %v\nrecorded:\n%s",
+		level, msgSubstr, fields, r.dump())
+	return false
+}
+
+// AssertNotContains fails t if any entry at level contains msgSubstr in
+// its message and all of fields.
+func (r *Recorder) AssertNotContains(t testing.TB, level zerolog.Level, msgSubstr string, fields map[string]any) bool {
+	t.Helper()
+	e, ok := r.Find(level, msgSubstr, fields)
+	if !ok {
+		return true
+	}
+	t.Errorf("unexpected %s log entry: %s", level, e.Raw)
+	return false
+}
+
+// Matches reports whether e is at level, its message contains msgSubstr,
+// and its fields include all of fields (compared as decoded JSON).
+func (e Entry) Matches(level zerolog.Level, msgSubstr string, fields map[string]any) bool {
+	if e.Level != level || !strings.Contains(e.Message, msgSubstr) {
+		return false
+	}
+	for k, v := range normalize(fields) {
+		got, ok := e.Fields[k]
+		if !ok || !reflect.DeepEqual(got, v) {
+			return false
+		}
+	}
+	return true
+}
+
+// dump renders all recorded entries for failure messages.
+func (r *Recorder) dump() string {
+	var b strings.Builder
+	for _, e := range r.Entries() {
+		b.Write(e.Raw)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// parseEntry decodes one JSON log line.
+func parseEntry(line []byte) (Entry, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(line, &fields); err != nil {
+		return Entry{}, fmt.Errorf("logtest: invalid JSON log line %q: %w", line, err)
+	}
+
+	e := Entry{
+		Level:  zerolog.NoLevel,
+		Fields: fields,
+		Raw:    append([]byte(nil), line...),
+	}
+	if lvl, ok := fields[zerolog.LevelFieldName].(string); ok {
+		if l, err := zerolog.ParseLevel(lvl); err == nil {
+			e.Level = l
+		}
+		delete(fields, zerolog.LevelFieldName)
+	}
+	if msg, ok := fields[zerolog.MessageFieldName].(string); ok {
+		e.Message = msg
+		delete(fields, zerolog.MessageFieldName)
+	}
+	return e, nil
+}
+
+// normalize round-trips fields through JSON so they compare equal to
+// decoded entry fields.
+func normalize(fields map[string]any) map[string]any {
+	if len(fields) == 0 {
+		return nil
+	}
+	b, err := json.Marshal(fields)
+	if err != nil {
+		return fields
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return fields
+	}
+	return out
+}
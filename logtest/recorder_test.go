@@ -0,0 +1,38 @@
+package logtest_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/logtest"
+	"github.com/rs/zerolog"
+)
+
+func TestRecorderDefaultFormat(t *testing.T) {
+	rec := logtest.NewRecorder()
+	log := zerowrap.New(zerowrap.Config{Output: rec})
+	log.Info().Int("order_id", 42).Msg("paid")
+
+	if errs := rec.Errors(); len(errs) > 0 {
+		t.Fatalf("Recorder got non-JSON lines: %v", errs)
+	}
+	rec.AssertContains(t, zerolog.InfoLevel, "paid", map[string]any{"order_id": 42})
+}
+
+func TestRecorderWithFile(t *testing.T) {
+	rec := logtest.NewRecorder()
+	log, cleanup, err := zerowrap.NewWithFile(zerowrap.Config{Format: "pretty", Output: rec}, zerowrap.FileConfig{
+		Path: filepath.Join(t.TempDir(), "app.log"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cleanup()
+	log.Warn().Msg("disk low")
+
+	if errs := rec.Errors(); len(errs) > 0 {
+		t.Fatalf("Recorder got non-JSON lines: %v", errs)
+	}
+	rec.AssertContains(t, zerolog.WarnLevel, "disk low", nil)
+}
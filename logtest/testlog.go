@@ -0,0 +1,90 @@
+package logtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// TestConfig holds configuration for test loggers.
+type TestConfig struct {
+	// Level is the minimum log level, with the names of zerowrap.Config.
+	// Defaults to "debug" if empty.
+	Level string
+
+	// Format is the output format: "json" or "console".
+	// Defaults to "console" if empty or invalid.
+	Format string
+
+	// FailOnError marks the test as failed when an error-level (or higher)
+	// event is logged.
+	FailOnError bool
+}
+
+// NewTestLogger creates a logger that writes through t.Log, so output
+// interleaves with the test's own output and is only shown for failing
+// tests (or with go test -v).
+//
+//	func TestCreateUser(t *testing.T) {
+//	    ctx := zerowrap.WithCtx(context.Background(), logtest.NewTestLogger(t, "debug"))
+//	    if err := svc.CreateUser(ctx, user); err != nil {
+//	        t.Fatal(err)
+//	    }
+//	}
+func NewTestLogger(t testing.TB, level string) zerowrap.Logger {
+	return NewTestLoggerWithConfig(t, TestConfig{Level: level})
+}
+
+// NewTestLoggerWithConfig creates a test logger with the given configuration.
+func NewTestLoggerWithConfig(t testing.TB, cfg TestConfig) zerowrap.Logger {
+	level := zerolog.DebugLevel
+	if cfg.Level != "" {
+		level = parseLevel(cfg.Level)
+	}
+
+	var logger zerolog.Logger
+	if strings.ToLower(cfg.Format) == "json" {
+		logger = zerolog.New(zerolog.NewTestWriter(t))
+	} else {
+		logger = zerolog.New(zerolog.NewConsoleWriter(
+			zerolog.ConsoleTestWriter(t),
+			func(w *zerolog.ConsoleWriter) { w.NoColor = true },
+		))
+	}
+	logger = logger.Level(level).With().Timestamp().Logger()
+
+	if cfg.FailOnError {
+		logger = logger.Hook(failOnErrorHook{t: t})
+	}
+
+	return zerowrap.Logger{Logger: logger}
+}
+
+// failOnErrorHook fails the test when an error-level event is logged.
+type failOnErrorHook struct {
+	t testing.TB
+}
+
+// Run implements zerolog.Hook.
+func (h failOnErrorHook) Run(_ *zerolog.Event, level zerolog.Level, msg string) {
+	if level >= zerolog.ErrorLevel && level < zerolog.NoLevel {
+		h.t.Helper()
+		h.t.Errorf("unexpected %s log: %s", level, msg)
+	}
+}
+
+// parseLevel parses a level name as zerowrap.Config.Level does,
+// defaulting to info.
+func parseLevel(level string) zerolog.Level {
+	level = strings.ToLower(level)
+	if level == "warning" {
+		return zerolog.WarnLevel
+	}
+	l, err := zerolog.ParseLevel(level)
+	if err != nil || l == zerolog.NoLevel {
+		return zerolog.InfoLevel
+	}
+	return l
+}
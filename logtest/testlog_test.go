@@ -0,0 +1,41 @@
+package logtest_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/bnema/zerowrap/logtest"
+)
+
+// fakeT is a testing.TB recording the output and failures of a test.
+type fakeT struct {
+	testing.TB
+	logs   []string
+	errors []string
+}
+
+func (t *fakeT) Helper()         {}
+func (t *fakeT) Log(args ...any) { t.logs = append(t.logs, fmt.Sprint(args...)) }
+func (t *fakeT) Logf(f string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(f, args...))
+}
+func (t *fakeT) Errorf(f string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(f, args...))
+}
+
+func TestNewTestLoggerFailOnError(t *testing.T) {
+	ft := &fakeT{TB: t}
+	log := logtest.NewTestLoggerWithConfig(ft, logtest.TestConfig{Level: "warning", Format: "json", FailOnError: true})
+
+	log.Info().Msg("skipped")
+	log.Warn().Msg("retrying")
+	log.Error().Msg("payment failed")
+
+	if len(ft.logs) != 2 || !strings.Contains(ft.logs[0], `"message":"retrying"`) {
+		t.Errorf("logs = %q, want the warn and error entries", ft.logs)
+	}
+	if len(ft.errors) != 1 || !strings.Contains(ft.errors[0], "payment failed") {
+		t.Errorf("errors = %q, want one for the error entry", ft.errors)
+	}
+}
@@ -0,0 +1,62 @@
+package zerowrap
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ltsvValueEscaper removes the label delimiter and line breaks from
+// values.
+var ltsvValueEscaper = strings.NewReplacer("\t", " ", "\n", `\n`, "\r", `\r`)
+
+// LTSVEncoder encodes entries as Labeled Tab-separated Values, as read by
+// fluentd, Treasure Data and other nginx-style pipelines: one line per
+// entry, with tab-separated label:value pairs.
+//
+//	time:2025-01-01T12:00:00Z	level:info	message:request completed	status:200
+//
+// The time, level and message come first, then the fields in entry
+// order. Objects and arrays are written as JSON. It is used by the
+// "ltsv" format.
+type LTSVEncoder struct{}
+
+// Encode implements Encoder.
+func (LTSVEncoder) Encode(dst []byte, e *Entry) ([]byte, error) {
+	sep := false
+	add := func(label, value string) {
+		if sep {
+			dst = append(dst, '\t')
+		}
+		sep = true
+		dst = append(dst, ltsvLabel(label)...)
+		dst = append(dst, ':')
+		dst = append(dst, ltsvValueEscaper.Replace(value)...)
+	}
+	if !e.Time.IsZero() {
+		add(zerolog.TimestampFieldName, e.Time.Format(zerolog.TimeFieldFormat))
+	}
+	if e.Level != zerolog.NoLevel {
+		add(zerolog.LevelFieldName, e.Level.String())
+	}
+	if e.Message != "" {
+		add(zerolog.MessageFieldName, e.Message)
+	}
+	for _, f := range e.Fields {
+		add(f.Key, f.String())
+	}
+	return append(dst, '\n'), nil
+}
+
+// ltsvLabel returns label with the characters LTSV does not allow in
+// labels (anything but letters, digits, '_', '.' and '-') replaced by '_'.
+func ltsvLabel(label string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, label)
+}
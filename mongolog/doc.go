@@ -0,0 +1,45 @@
+// Package mongolog provides MongoDB driver logging for zerowrap.
+//
+// This is an optional sub-package that adds the mongo-driver dependency.
+// CommandMonitor logs every command with the logger of its context, so
+// commands run while handling a request carry the request fields, and
+// PoolMonitor logs connection pool events.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap/mongolog"
+//	    "go.mongodb.org/mongo-driver/mongo"
+//	    "go.mongodb.org/mongo-driver/mongo/options"
+//	)
+//
+//	opts := mongolog.Options{SlowThreshold: 100 * time.Millisecond}
+//	client, err := mongo.Connect(ctx, options.Client().
+//	    ApplyURI(uri).
+//	    SetMonitor(mongolog.CommandMonitor(opts)).
+//	    SetPoolMonitor(mongolog.PoolMonitor(ctx, opts)))
+//
+//	users.FindOne(ctx, bson.M{"email": email})
+//	// {"level":"debug","mongo_command":"find","database":"app","collection":"users",
+//	//  "query_hash":"...","command":{"find":"users","filter":{"email":"?"},"limit":"?"},
+//	//  "mongo_connection_id":"db:27017[-3]","duration_ms":0.8,"message":"mongo command"}
+//
+// Successful commands log at Options.Level (debug by default), slow ones
+// at warn and failed ones at error.
+//
+// # Redaction
+//
+// Command documents are logged with every value replaced by "?", except
+// the collection name, and arrays reduced to their first element: filters
+// and inserted documents never reach the logs, and commands differing
+// only in their values share one query_hash. Session and cluster
+// metadata (lsid, $clusterTime, $db...) are dropped. The driver already
+// leaves authentication commands empty.
+//
+// # Connection Pool
+//
+// PoolMonitor logs pool creation and closing at info, pool clearing and
+// failed checkouts at warn, and connections opened and closed at debug
+// (warn when closed on error). Set Options.LogCheckouts to log every
+// checkout and checkin as well.
+package mongolog
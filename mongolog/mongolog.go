@@ -0,0 +1,301 @@
+package mongolog
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// Field names logged by the monitors.
+const (
+	FieldCommandName  = "mongo_command"
+	FieldCommand      = "command"
+	FieldCollection   = "collection"
+	FieldConnectionID = "mongo_connection_id"
+	FieldAddress      = "mongo_address"
+	FieldPoolEvent    = "pool_event"
+	FieldReason       = "reason"
+)
+
+// DefaultMaxCommandBytes is the logged size of redacted commands.
+const DefaultMaxCommandBytes = 2048
+
+// Options configures MongoDB logging.
+type Options struct {
+	// Level is the level of successful commands. Failed commands log at
+	// error level.
+	// Defaults to debug if 0.
+	Level zerolog.Level
+
+	// SlowThreshold logs commands taking longer than this at warn level.
+	// Disabled if 0.
+	SlowThreshold time.Duration
+
+	// MaxCommandBytes caps the logged size of redacted commands; longer
+	// ones are cut and logged as a string.
+	// Defaults to DefaultMaxCommandBytes if 0.
+	MaxCommandBytes int
+
+	// LogCheckouts logs every connection checked out of and back into
+	// the pool at debug level. Other pool events are always logged.
+	LogCheckouts bool
+}
+
+// commandKeys are the fields of a command that are dropped from logs:
+// session and cluster metadata, which holds signatures and no detail of
+// the operation.
+var commandKeys = map[string]bool{
+	"lsid":            true,
+	"$clusterTime":    true,
+	"$db":             true,
+	"txnNumber":       true,
+	"$readPreference": true,
+	"signature":       true,
+	"apiVersion":      true,
+}
+
+// CommandMonitor returns a command monitor logging each command once it
+// completes, with the logger of its context: the command name,
+// database, collection, redacted command document, query_hash and
+// duration.
+//
+//	client, err := mongo.Connect(ctx, options.Client().
+//	    ApplyURI(uri).
+//	    SetMonitor(mongolog.CommandMonitor(mongolog.Options{})))
+func CommandMonitor(opts Options) *event.CommandMonitor {
+	m := &commandMonitor{opts: opts}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+// commandKey identifies a command between its started and finished
+// events.
+type commandKey struct {
+	conn string
+	id   int64
+}
+
+// startedCommand is the part of a started event logged on completion.
+type startedCommand struct {
+	collection string
+	command    []byte
+}
+
+// commandMonitor holds the commands in flight.
+type commandMonitor struct {
+	opts     Options
+	inFlight sync.Map // commandKey -> startedCommand
+}
+
+func (m *commandMonitor) started(_ context.Context, e *event.CommandStartedEvent) {
+	var sc startedCommand
+	if elems, err := e.Command.Elements(); err == nil && len(elems) > 0 {
+		if s, ok := elems[0].Value().StringValueOK(); ok {
+			sc.collection = s
+		}
+		sc.command = redactCommand(elems)
+	}
+	m.inFlight.Store(commandKey{e.ConnectionID, e.RequestID}, sc)
+}
+
+func (m *commandMonitor) succeeded(ctx context.Context, e *event.CommandSucceededEvent) {
+	m.log(ctx, &e.CommandFinishedEvent, nil)
+}
+
+func (m *commandMonitor) failed(ctx context.Context, e *event.CommandFailedEvent) {
+	m.log(ctx, &e.CommandFinishedEvent, errors.New(e.Failure))
+}
+
+// log writes the completion entry of a command.
+func (m *commandMonitor) log(ctx context.Context, e *event.CommandFinishedEvent, err error) {
+	v, _ := m.inFlight.LoadAndDelete(commandKey{e.ConnectionID, e.RequestID})
+	sc, _ := v.(startedCommand)
+
+	level := m.opts.Level
+	switch {
+	case err != nil:
+		level = zerolog.ErrorLevel
+	case m.opts.SlowThreshold > 0 && e.Duration >= m.opts.SlowThreshold:
+		level = zerolog.WarnLevel
+	}
+	log := zerowrap.FromCtx(ctx)
+	ev := log.WithLevel(level).Str(FieldCommandName, e.CommandName)
+	if e.DatabaseName != "" {
+		ev = ev.Str(zerowrap.FieldDatabase, e.DatabaseName)
+	}
+	if sc.collection != "" {
+		ev = ev.Str(FieldCollection, sc.collection)
+	}
+	if len(sc.command) > 0 {
+		ev = m.addCommand(ev, sc.command)
+	}
+	ev = ev.Str(FieldConnectionID, e.ConnectionID)
+	if err != nil {
+		ev = ev.Err(err)
+	}
+	zerowrap.AddDuration(ev, e.Duration).Msg("mongo command")
+}
+
+// addCommand adds the redacted command and its query_hash to e, cutting
+// commands longer than MaxCommandBytes.
+func (m *commandMonitor) addCommand(e *zerolog.Event, command []byte) *zerolog.Event {
+	sum := sha256.Sum256(command)
+	e = e.Str(zerowrap.FieldQueryHash, hex.EncodeToString(sum[:8]))
+	limit := m.opts.MaxCommandBytes
+	if limit <= 0 {
+		limit = DefaultMaxCommandBytes
+	}
+	if len(command) > limit {
+		return e.Bytes(FieldCommand, command[:limit]).Bool(zerowrap.FieldTruncated, true)
+	}
+	return e.RawJSON(FieldCommand, command)
+}
+
+// redactCommand returns the command as JSON with every value replaced by
+// "?", except the collection (the value of the first element), and
+// arrays reduced to their first element, so commands differing only in
+// their values or number of documents are logged, and hashed, the same.
+// Session and cluster metadata are dropped.
+func redactCommand(elems []bson.RawElement) []byte {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	n := 0
+	for i, el := range elems {
+		key := el.Key()
+		if commandKeys[key] {
+			continue
+		}
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		n++
+		writeKey(&buf, key)
+		if i == 0 {
+			if s, ok := el.Value().StringValueOK(); ok {
+				writeKey(&buf, s)
+				buf.Truncate(buf.Len() - 1) // drop the colon
+				continue
+			}
+		}
+		writeRedacted(&buf, el.Value(), 0)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// writeKey writes key and a colon to buf.
+func writeKey(buf *bytes.Buffer, key string) {
+	b, _ := json.Marshal(key)
+	buf.Write(b)
+	buf.WriteByte(':')
+}
+
+// writeRedacted writes the shape of v to buf: documents with their keys,
+// arrays with their first element, and "?" for other values.
+func writeRedacted(buf *bytes.Buffer, v bson.RawValue, depth int) {
+	if depth > 32 {
+		buf.WriteString(`"?"`)
+		return
+	}
+	switch v.Type {
+	case bson.TypeEmbeddedDocument:
+		elems, err := v.Document().Elements()
+		if err != nil {
+			buf.WriteString(`"?"`)
+			return
+		}
+		buf.WriteByte('{')
+		for i, el := range elems {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeKey(buf, el.Key())
+			writeRedacted(buf, el.Value(), depth+1)
+		}
+		buf.WriteByte('}')
+	case bson.TypeArray:
+		elems, err := v.Array().Elements()
+		if err != nil {
+			buf.WriteString(`"?"`)
+			return
+		}
+		buf.WriteByte('[')
+		if len(elems) > 0 {
+			writeRedacted(buf, elems[0].Value(), depth+1)
+		}
+		buf.WriteByte(']')
+	default:
+		buf.WriteString(`"?"`)
+	}
+}
+
+// PoolMonitor returns a connection pool monitor logging pool events with
+// the logger of ctx: pool creation, clearing and closing at info (warn
+// for clearing), connections opened and closed at debug (warn when
+// closed on error), and failed checkouts at warn. Checkouts are logged
+// with Options.LogCheckouts.
+//
+//	client, err := mongo.Connect(ctx, options.Client().
+//	    ApplyURI(uri).
+//	    SetPoolMonitor(mongolog.PoolMonitor(ctx, mongolog.Options{})))
+func PoolMonitor(ctx context.Context, opts Options) *event.PoolMonitor {
+	return &event.PoolMonitor{
+		Event: func(e *event.PoolEvent) {
+			logPoolEvent(zerowrap.FromCtx(ctx), opts, e)
+		},
+	}
+}
+
+// logPoolEvent writes the entry of a pool event.
+func logPoolEvent(log zerowrap.Logger, opts Options, e *event.PoolEvent) {
+	var level zerolog.Level
+	switch e.Type {
+	case event.PoolCreated, event.PoolReady, event.PoolClosedEvent:
+		level = zerolog.InfoLevel
+	case event.PoolCleared, event.GetFailed:
+		level = zerolog.WarnLevel
+	case event.ConnectionClosed:
+		level = zerolog.DebugLevel
+		if e.Reason == event.ReasonError || e.Reason == event.ReasonConnectionErrored {
+			level = zerolog.WarnLevel
+		}
+	case event.ConnectionCreated, event.ConnectionReady:
+		level = zerolog.DebugLevel
+	default: // checkout started, checked out, checked in
+		if !opts.LogCheckouts {
+			return
+		}
+		level = zerolog.DebugLevel
+	}
+
+	ev := log.WithLevel(level).
+		Str(FieldPoolEvent, e.Type).
+		Str(FieldAddress, e.Address)
+	if e.ConnectionID != 0 {
+		ev = ev.Str(FieldConnectionID, strconv.FormatUint(e.ConnectionID, 10))
+	}
+	if e.Reason != "" {
+		ev = ev.Str(FieldReason, e.Reason)
+	}
+	if e.Duration > 0 {
+		ev = zerowrap.AddDuration(ev, e.Duration)
+	}
+	if e.Error != nil {
+		ev = ev.Err(e.Error)
+	}
+	ev.Msg("mongo pool event")
+}
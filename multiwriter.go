@@ -0,0 +1,146 @@
+package zerowrap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// SinkStats holds the write counters of a MultiWriter sink.
+type SinkStats struct {
+	Name      string `json:"name"`
+	Writes    int64  `json:"writes"`
+	Errors    int64  `json:"errors"`
+	LastError string `json:"last_error,omitempty"`
+}
+
+// multiSink is a named destination of a MultiWriter.
+type multiSink struct {
+	name   string
+	out    io.Writer
+	writes atomic.Int64
+	errors atomic.Int64
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// MultiWriter is a zerolog.LevelWriter writing every entry to all its
+// sinks while isolating their failures: a sink returning an error, a
+// short write or panicking does not affect the others, and the entry
+// is reported as written. Each failure is reported with ErrWrite (see
+// SetErrorHandler) naming the sink, and counted per sink in Stats.
+//
+// Unlike zerolog.MultiLevelWriter, whose first error reaches zerolog's
+// error handler without saying which sink failed, a read-only log file
+// leaves the console output working and shows up in diagnostics.
+type MultiWriter struct {
+	sinks []*multiSink
+}
+
+// NewMultiWriter creates a multi-writer without sinks.
+//
+//	w := zerowrap.NewMultiWriter().
+//	    Sink("stdout", os.Stdout).
+//	    Sink("file", logFile).
+//	    Sink("kafka", kafkaWriter)
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: w})
+func NewMultiWriter() *MultiWriter {
+	return &MultiWriter{}
+}
+
+// Sink adds out as a sink named name, the name used in error reports and
+// Stats.
+func (m *MultiWriter) Sink(name string, out io.Writer) *MultiWriter {
+	m.sinks = append(m.sinks, &multiSink{name: name, out: out})
+	return m
+}
+
+// Write implements io.Writer.
+func (m *MultiWriter) Write(p []byte) (int, error) {
+	return m.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter, passing level on to sinks
+// that are zerolog.LevelWriters. It never returns an error; failures
+// are reported per sink instead.
+func (m *MultiWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	for _, s := range m.sinks {
+		s.write(level, p)
+	}
+	return len(p), nil
+}
+
+// write writes p to the sink, recording and reporting failures.
+func (s *multiSink) write(level zerolog.Level, p []byte) {
+	s.writes.Add(1)
+	err := func() (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic: %v", r)
+			}
+		}()
+		n, err := writeLevel(s.out, level, p)
+		if err == nil && n < len(p) {
+			err = io.ErrShortWrite
+		}
+		return err
+	}()
+	if err == nil {
+		return
+	}
+	s.errors.Add(1)
+	s.mu.Lock()
+	s.lastErr = err
+	s.mu.Unlock()
+	ReportError(fmt.Errorf("%w: sink %s: %w", ErrWrite, s.name, err))
+}
+
+// Stats returns the write and error counts of each sink, in the order
+// they were added, with the last error of each.
+func (m *MultiWriter) Stats() []SinkStats {
+	stats := make([]SinkStats, len(m.sinks))
+	for i, s := range m.sinks {
+		stats[i] = SinkStats{
+			Name:   s.name,
+			Writes: s.writes.Load(),
+			Errors: s.errors.Load(),
+		}
+		s.mu.Lock()
+		if s.lastErr != nil {
+			stats[i].LastError = s.lastErr.Error()
+		}
+		s.mu.Unlock()
+	}
+	return stats
+}
+
+// Close closes the sinks implementing io.Closer, except os.Stdout and
+// os.Stderr.
+func (m *MultiWriter) Close() error {
+	var errs []error
+	seen := map[io.Writer]bool{}
+	for _, s := range m.sinks {
+		out := s.out
+		if out == nil || out == os.Stdout || out == os.Stderr {
+			continue
+		}
+		if reflect.TypeOf(out).Comparable() {
+			if seen[out] {
+				continue
+			}
+			seen[out] = true
+		}
+		if c, ok := out.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,130 @@
+package zerowrap
+
+import (
+	"maps"
+	"math"
+	"net"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Dict returns a field holding a nested object. Values are added like
+// map fields, in key order, and may themselves be maps or slices.
+//
+//	log = log.WithTyped(zerowrap.Dict("user", map[string]any{
+//	    "id":    id,
+//	    "roles": []any{"admin", "billing"},
+//	}))
+//
+// Map-based helpers nest map[string]any and []any values the same way.
+func Dict(key string, fields map[string]any) Field {
+	return Field{Key: key, kind: fieldObject, any: fields}
+}
+
+// Array returns a field holding an array. Values are added like map
+// fields and may themselves be maps.
+func Array(key string, values []any) Field {
+	return Field{Key: key, kind: fieldArray, any: values}
+}
+
+// objectFields logs a map as a nested object, in key order.
+type objectFields map[string]any
+
+// MarshalZerologObject implements zerolog.LogObjectMarshaler.
+func (o objectFields) MarshalZerologObject(e *zerolog.Event) {
+	for _, k := range slices.Sorted(maps.Keys(o)) {
+		Any(k, o[k]).addToEvent(e)
+	}
+}
+
+// arrayValues logs a slice as an array.
+type arrayValues []any
+
+// MarshalZerologArray implements zerolog.LogArrayMarshaler.
+func (a arrayValues) MarshalZerologArray(arr *zerolog.Array) {
+	for _, v := range a {
+		Any("", v).appendTo(arr)
+	}
+}
+
+// addToEvent adds the field to a nested object, like addTo.
+func (f Field) addToEvent(e *zerolog.Event) {
+	switch f.kind {
+	case fieldStr:
+		e.Str(f.Key, f.str)
+	case fieldInt:
+		e.Int64(f.Key, int64(f.num))
+	case fieldUint:
+		e.Uint64(f.Key, f.num)
+	case fieldFloat:
+		e.Float64(f.Key, math.Float64frombits(f.num))
+	case fieldFloat32:
+		e.Float32(f.Key, math.Float32frombits(uint32(f.num)))
+	case fieldBool:
+		e.Bool(f.Key, f.num == 1)
+	case fieldDur:
+		e.Dur(f.Key, time.Duration(f.num))
+	case fieldTime:
+		e.Time(f.Key, f.time())
+	case fieldErr:
+		if f.any != nil {
+			e.AnErr(f.Key, f.any.(error))
+		}
+	case fieldRawJSON:
+		e.RawJSON(f.Key, f.bytes())
+	case fieldBytes:
+		e.Bytes(f.Key, f.bytes())
+	case fieldStrs:
+		e.Strs(f.Key, f.any.([]string))
+	case fieldObject:
+		e.Object(f.Key, objectFields(f.any.(map[string]any)))
+	case fieldArray:
+		e.Array(f.Key, arrayValues(f.any.([]any)))
+	case fieldIP:
+		e.IPAddr(f.Key, f.any.(net.IP))
+	default:
+		e.Interface(f.Key, f.any)
+	}
+}
+
+// appendTo appends the field value to an array, like addTo. zerolog
+// arrays have no string slice or array elements: those are marshaled
+// with encoding/json.
+func (f Field) appendTo(a *zerolog.Array) {
+	switch f.kind {
+	case fieldStr:
+		a.Str(f.str)
+	case fieldInt:
+		a.Int64(int64(f.num))
+	case fieldUint:
+		a.Uint64(f.num)
+	case fieldFloat:
+		a.Float64(math.Float64frombits(f.num))
+	case fieldFloat32:
+		a.Float32(math.Float32frombits(uint32(f.num)))
+	case fieldBool:
+		a.Bool(f.num == 1)
+	case fieldDur:
+		a.Dur(time.Duration(f.num))
+	case fieldTime:
+		a.Time(f.time())
+	case fieldErr:
+		if f.any == nil {
+			a.Interface(nil)
+			return
+		}
+		a.Err(f.any.(error))
+	case fieldRawJSON:
+		a.RawJSON(f.bytes())
+	case fieldBytes:
+		a.Bytes(f.bytes())
+	case fieldObject:
+		a.Object(objectFields(f.any.(map[string]any)))
+	case fieldIP:
+		a.IPAddr(f.any.(net.IP))
+	default:
+		a.Interface(f.any)
+	}
+}
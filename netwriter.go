@@ -0,0 +1,449 @@
+package zerowrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// NetConfig holds configuration for network output.
+type NetConfig struct {
+	// Network is "tcp", "udp", "unix" or "unixgram".
+	Network string
+
+	// Addr is the endpoint address, e.g. "127.0.0.1:9000" or "/var/run/vector.sock".
+	Addr string
+
+	// DialTimeout bounds each connection attempt.
+	// Defaults to 5 seconds if 0.
+	DialTimeout time.Duration
+
+	// WriteTimeout bounds each write to the connection, so a hung
+	// endpoint cannot stall delivery: the write is reported with
+	// ErrWriteTimeout, the connection is reopened and the entry is sent
+	// again on the new one.
+	// Defaults to 10 seconds if 0.
+	WriteTimeout time.Duration
+
+	// MaxBackoff caps the delay between reconnection attempts, which
+	// doubles from 100ms after each failure.
+	// Defaults to 30 seconds if 0.
+	MaxBackoff time.Duration
+
+	// QueueSize is the number of entries buffered in memory.
+	// Defaults to 1024 if 0.
+	QueueSize int
+
+	// SpoolPath enables an on-disk spool: entries that do not fit in the
+	// queue, while the endpoint is down or slower than the application,
+	// are appended to this file and replayed after the queued ones.
+	// Queued entries are spooled on Close if the endpoint is down.
+	// Entries that do not fit in the queue are dropped if empty.
+	SpoolPath string
+
+	// SpoolMaxBytes caps the spool file size; entries are dropped once
+	// it is full.
+	// Defaults to 64 MB if 0.
+	SpoolMaxBytes int64
+}
+
+// NetWriter is an io.Writer that ships entries to a TCP, UDP or Unix
+// socket endpoint, such as a local vector or fluent-bit agent. It
+// reconnects with exponential backoff and can spool entries to disk
+// while the endpoint is down, so logs survive agent restarts.
+type NetWriter struct {
+	cfg   NetConfig
+	queue chan []byte
+	stop  chan struct{}
+	done  chan struct{}
+	drops atomic.Int64
+
+	mu     sync.RWMutex
+	closed bool
+
+	conn net.Conn // owned by the run goroutine
+
+	spoolMu   sync.Mutex
+	spool     *os.File
+	spoolSize int64
+	spoolSent int64 // bytes of the spool already replayed
+
+	// spooled is set while the spool holds entries, so later entries
+	// follow them into the spool and are sent in order.
+	spooled atomic.Bool
+	// spoolNotify wakes run when an entry is spooled while connected.
+	spoolNotify chan struct{}
+}
+
+// NewNetWriter creates a network writer and starts connecting in the
+// background; it does not fail if the endpoint is down.
+//
+//	agent, err := zerowrap.NewNetWriter(zerowrap.NetConfig{
+//	    Network:   "tcp",
+//	    Addr:      "127.0.0.1:9000",
+//	    SpoolPath: "/var/spool/myapp/logs.spool",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer agent.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: agent})
+func NewNetWriter(cfg NetConfig) (*NetWriter, error) {
+	switch cfg.Network {
+	case "tcp", "tcp4", "tcp6", "udp", "udp4", "udp6", "unix", "unixgram":
+	default:
+		return nil, fmt.Errorf("zerowrap: unsupported network %q", cfg.Network)
+	}
+	if cfg.Addr == "" {
+		return nil, errors.New("zerowrap: network address is required")
+	}
+	if cfg.DialTimeout == 0 {
+		cfg.DialTimeout = 5 * time.Second
+	}
+	if cfg.WriteTimeout == 0 {
+		cfg.WriteTimeout = 10 * time.Second
+	}
+	if cfg.MaxBackoff == 0 {
+		cfg.MaxBackoff = 30 * time.Second
+	}
+	if cfg.QueueSize == 0 {
+		cfg.QueueSize = 1024
+	}
+	if cfg.SpoolMaxBytes == 0 {
+		cfg.SpoolMaxBytes = 64 << 20
+	}
+
+	w := &NetWriter{
+		cfg:         cfg,
+		queue:       make(chan []byte, cfg.QueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+		spoolNotify: make(chan struct{}, 1),
+	}
+	if cfg.SpoolPath != "" {
+		f, err := os.OpenFile(cfg.SpoolPath, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o600)
+		if err != nil {
+			return nil, fmt.Errorf("zerowrap: spool: %w", err)
+		}
+		info, err := f.Stat()
+		if err != nil {
+			_ = f.Close()
+			return nil, fmt.Errorf("zerowrap: spool: %w", err)
+		}
+		w.spool = f
+		w.spoolSize = info.Size()
+		w.spooled.Store(w.spoolSize > 0)
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write implements io.Writer. It never blocks: entries are queued, or
+// spooled (or dropped) when the queue is full. While the spool holds
+// entries, new ones are spooled after them, so entries are sent in the
+// order they were written.
+func (w *NetWriter) Write(p []byte) (int, error) {
+	entry := make([]byte, len(p), len(p)+1)
+	copy(entry, p)
+	if len(entry) == 0 || entry[len(entry)-1] != '\n' {
+		entry = append(entry, '\n')
+	}
+
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		w.drop()
+		return len(p), nil
+	}
+	if w.spooled.Load() {
+		w.toSpool(entry)
+		return len(p), nil
+	}
+	select {
+	case w.queue <- entry:
+	default:
+		w.toSpool(entry)
+	}
+	return len(p), nil
+}
+
+// Close sends queued and spooled entries, or spools the queued ones if
+// the endpoint is down, and closes the connection.
+func (w *NetWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	close(w.stop)
+	w.mu.Unlock()
+
+	<-w.done
+	if w.spool != nil {
+		return w.spool.Close()
+	}
+	return nil
+}
+
+// Dropped returns the number of entries dropped because the endpoint was
+// down without a spool, or the spool was full.
+func (w *NetWriter) Dropped() int64 {
+	return w.drops.Load()
+}
+
+// drop counts a dropped entry and reports it with ErrDropped.
+func (w *NetWriter) drop() {
+	w.drops.Add(1)
+	ReportError(fmt.Errorf("%w: network writer %s", ErrDropped, w.cfg.Addr))
+}
+
+// run connects and sends queued entries until Close, replaying the spool
+// whenever the queue is empty. Queued entries are older than spooled
+// ones, so they are kept in the queue while disconnected.
+func (w *NetWriter) run() {
+	defer close(w.done)
+	defer func() {
+		if w.conn != nil {
+			_ = w.conn.Close()
+		}
+	}()
+
+	var retry []byte // entry whose send failed, sent first on reconnection
+	backoff := 100 * time.Millisecond
+	for {
+		if w.conn == nil {
+			conn, err := net.DialTimeout(w.cfg.Network, w.cfg.Addr, w.cfg.DialTimeout)
+			if err != nil {
+				if !w.wait(backoff) {
+					w.spoolQueued(retry)
+					return
+				}
+				backoff = min(backoff*2, w.cfg.MaxBackoff)
+				continue
+			}
+			w.conn = conn
+			backoff = 100 * time.Millisecond
+		}
+
+		if retry != nil {
+			if err := w.send(retry); err != nil {
+				w.disconnect()
+				continue
+			}
+			retry = nil
+		}
+		if len(w.queue) == 0 && w.spooled.Load() {
+			if err := w.replay(); err != nil {
+				w.disconnect()
+				continue
+			}
+		}
+
+		select {
+		case entry, ok := <-w.queue:
+			if !ok {
+				if w.spooled.Load() {
+					_ = w.replay()
+				}
+				return
+			}
+			if err := w.send(entry); err != nil {
+				retry = entry
+				w.disconnect()
+			}
+		case <-w.spoolNotify:
+		}
+	}
+}
+
+// send writes p to the connection within WriteTimeout, reporting a
+// timeout with ErrWriteTimeout.
+func (w *NetWriter) send(p []byte) error {
+	if err := w.conn.SetWriteDeadline(time.Now().Add(w.cfg.WriteTimeout)); err != nil {
+		return err
+	}
+	_, err := w.conn.Write(p)
+	if errors.Is(err, os.ErrDeadlineExceeded) {
+		ReportError(fmt.Errorf("%w: network writer %s after %s", ErrWriteTimeout, w.cfg.Addr, w.cfg.WriteTimeout))
+	}
+	return err
+}
+
+// wait waits d before the next connection attempt. Returns false once
+// the writer is closed.
+func (w *NetWriter) wait(d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-w.stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// disconnect closes the current connection.
+func (w *NetWriter) disconnect() {
+	_ = w.conn.Close()
+	w.conn = nil
+}
+
+// spoolQueued moves retry and the queued entries of a closed writer in
+// front of the spooled ones, or drops them if there is no spool.
+func (w *NetWriter) spoolQueued(retry []byte) {
+	var entries [][]byte
+	if retry != nil {
+		entries = append(entries, retry)
+	}
+	for entry := range w.queue {
+		entries = append(entries, entry)
+	}
+	if w.spool == nil {
+		for range entries {
+			w.drop()
+		}
+		return
+	}
+
+	w.spoolMu.Lock()
+	defer w.spoolMu.Unlock()
+	rest := make([]byte, w.spoolSize-w.spoolSent)
+	if _, err := w.spool.ReadAt(rest, w.spoolSent); err != nil && err != io.EOF {
+		return
+	}
+	if err := w.spool.Truncate(0); err != nil {
+		return
+	}
+	w.spoolSize, w.spoolSent = 0, 0
+	for _, entry := range entries {
+		w.appendSpool(entry)
+	}
+	// The spooled entries fit before, keep them all.
+	n, _ := w.spool.Write(rest)
+	w.spoolSize += int64(n)
+	w.spooled.Store(w.spoolSize > 0)
+}
+
+// toSpool appends entry to the spool, or drops it if there is none or
+// it is full.
+func (w *NetWriter) toSpool(entry []byte) {
+	if w.spool == nil {
+		w.drop()
+		return
+	}
+	w.spoolMu.Lock()
+	w.appendSpool(entry)
+	w.spoolMu.Unlock()
+
+	select {
+	case w.spoolNotify <- struct{}{}:
+	default:
+	}
+}
+
+// appendSpool appends p to the spool, or drops it if it is full. The
+// caller holds spoolMu.
+func (w *NetWriter) appendSpool(p []byte) {
+	if len(p) == 0 {
+		return
+	}
+	if w.spoolSize+int64(len(p)) > w.cfg.SpoolMaxBytes {
+		w.drop()
+		return
+	}
+	n, err := w.spool.Write(p)
+	w.spoolSize += int64(n)
+	if err != nil {
+		w.drop()
+	}
+	if n > 0 {
+		w.spooled.Store(true)
+	}
+}
+
+// replayChunk is the size of the spool reads of replay.
+const replayChunk = 64 << 10
+
+// replay sends spooled entries and truncates the spool. Entries are sent
+// one line per write, so datagram endpoints receive one entry per packet.
+// The spool is read in chunks and not locked while sending, so Write can
+// keep appending to it; replay returns once it has sent them all.
+func (w *NetWriter) replay() error {
+	if w.spool == nil {
+		return nil
+	}
+	buf := make([]byte, replayChunk)
+	for {
+		w.spoolMu.Lock()
+		if w.spoolSent >= w.spoolSize {
+			err := w.spool.Truncate(0)
+			if err == nil {
+				w.spoolSize, w.spoolSent = 0, 0
+				w.spooled.Store(false)
+			}
+			w.spoolMu.Unlock()
+			return err
+		}
+		n, err := w.spool.ReadAt(buf[:min(int64(len(buf)), w.spoolSize-w.spoolSent)], w.spoolSent)
+		w.spoolMu.Unlock()
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		chunk := buf[:n]
+		end := bytes.LastIndexByte(chunk, '\n')
+		if end < 0 {
+			if n < len(buf) {
+				// An entry cut by a failed write: skip it.
+				w.markSent(int64(n))
+				continue
+			}
+			buf = make([]byte, 2*len(buf))
+			continue
+		}
+		for line := range bytes.Lines(chunk[:end+1]) {
+			if err := w.send(line); err != nil {
+				// Keep what was not sent for the next connection.
+				w.spoolMu.Lock()
+				defer w.spoolMu.Unlock()
+				return w.compactSpool(err)
+			}
+			w.markSent(int64(len(line)))
+		}
+	}
+}
+
+// markSent records n more replayed bytes of the spool.
+func (w *NetWriter) markSent(n int64) {
+	w.spoolMu.Lock()
+	w.spoolSent += n
+	w.spoolMu.Unlock()
+}
+
+// compactSpool drops the replayed bytes of the spool and returns cause.
+func (w *NetWriter) compactSpool(cause error) error {
+	sent := w.spoolSent
+	if sent == 0 {
+		return cause
+	}
+	rest := make([]byte, w.spoolSize-sent)
+	if _, err := w.spool.ReadAt(rest, sent); err != nil && err != io.EOF {
+		return cause
+	}
+	if err := w.spool.Truncate(0); err != nil {
+		return cause
+	}
+	n, _ := w.spool.Write(rest)
+	w.spoolSize = int64(n)
+	w.spoolSent = 0
+	return cause
+}
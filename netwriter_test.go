@@ -0,0 +1,153 @@
+package zerowrap
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// receive accepts one connection at a time on ln and sends the lines
+// read to the returned channel.
+func receive(t *testing.T, ln net.Listener) <-chan string {
+	t.Helper()
+	lines := make(chan string, 4096)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			sc := bufio.NewScanner(conn)
+			for sc.Scan() {
+				lines <- sc.Text()
+			}
+			_ = conn.Close()
+		}
+	}()
+	return lines
+}
+
+// entryPad makes entries large enough for a burst to fill the socket buffers.
+var entryPad = strings.Repeat("x", 4096)
+
+// expectLines checks the n lines received are entry 0 to n-1 in order.
+func expectLines(t *testing.T, lines <-chan string, n int) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for i := 0; i < n; i++ {
+		select {
+		case line := <-lines:
+			if want := fmt.Sprintf(`{"n":%d,`, i); !strings.HasPrefix(line, want) {
+				t.Fatalf("line %d = %.20s..., want %s...", i, line, want)
+			}
+		case <-timeout:
+			t.Fatalf("received %d of %d entries", i, n)
+		}
+	}
+}
+
+func TestNetWriterSpoolWhileConnected(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "agent.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	w, err := NewNetWriter(NetConfig{
+		Network:   "unix",
+		Addr:      sock,
+		QueueSize: 1,
+		SpoolPath: filepath.Join(dir, "logs.spool"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	// Nothing is read during the burst, so it fills the socket buffers
+	// and the queue and spills to the spool while connected; spooled
+	// entries are sent without waiting for a reconnection.
+	const n = 2000
+	for i := 0; i < n; i++ {
+		_, _ = fmt.Fprintf(w, `{"n":%d,"pad":%q}`, i, entryPad)
+	}
+	lines := receive(t, ln)
+	expectLines(t, lines, n)
+	if d := w.Dropped(); d != 0 {
+		t.Errorf("Dropped() = %d, want 0", d)
+	}
+}
+
+func TestNetWriterReplaysAfterReconnect(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "agent.sock")
+
+	w, err := NewNetWriter(NetConfig{
+		Network:    "unix",
+		Addr:       sock,
+		QueueSize:  4,
+		MaxBackoff: 50 * time.Millisecond,
+		SpoolPath:  filepath.Join(dir, "logs.spool"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+
+	const n = 100
+	for i := 0; i < n/2; i++ {
+		_, _ = fmt.Fprintf(w, `{"n":%d,"pad":%q}`, i, entryPad)
+	}
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := receive(t, ln)
+	for i := n / 2; i < n; i++ {
+		_, _ = fmt.Fprintf(w, `{"n":%d,"pad":%q}`, i, entryPad)
+	}
+	expectLines(t, lines, n)
+}
+
+func TestNetWriterSpoolsQueueOnClose(t *testing.T) {
+	dir := t.TempDir()
+	sock := filepath.Join(dir, "agent.sock")
+	cfg := NetConfig{
+		Network:   "unix",
+		Addr:      sock,
+		QueueSize: 4,
+		SpoolPath: filepath.Join(dir, "logs.spool"),
+	}
+
+	w, err := NewNetWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const n = 10
+	for i := 0; i < n; i++ {
+		_, _ = fmt.Fprintf(w, `{"n":%d,"pad":%q}`, i, entryPad)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	lines := receive(t, ln)
+	w, err = NewNetWriter(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	expectLines(t, lines, n)
+}
@@ -0,0 +1,151 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/log"
+)
+
+// ErrHookClosed is returned by Flush and Shutdown after the hook was shut down.
+var ErrHookClosed = errors.New("otel: hook is shut down")
+
+// defaultQueueSize is the async queue capacity when HookOptions.QueueSize is 0.
+const defaultQueueSize = 1024
+
+// queued is a record waiting in the async queue, or a flush marker.
+type queued struct {
+	ctx     context.Context
+	level   zerolog.Level
+	record  log.Record
+	flushed chan struct{}
+}
+
+// asyncQueue emits records from a background goroutine.
+type asyncQueue struct {
+	mu     sync.RWMutex
+	closed bool
+	ch     chan queued
+	done   chan struct{}
+	drops  atomic.Int64
+}
+
+// newAsyncQueue starts a background goroutine emitting queued records through emit.
+func newAsyncQueue(size int, emit func(ctx context.Context, level zerolog.Level, record log.Record)) *asyncQueue {
+	if size <= 0 {
+		size = defaultQueueSize
+	}
+	q := &asyncQueue{
+		ch:   make(chan queued, size),
+		done: make(chan struct{}),
+	}
+	go func() {
+		defer close(q.done)
+		for item := range q.ch {
+			if item.flushed != nil {
+				close(item.flushed)
+				continue
+			}
+			emit(item.ctx, item.level, item.record)
+		}
+	}()
+	return q
+}
+
+// enqueue adds a record without blocking. It reports false if the queue
+// is full or closed, in which case the record is dropped.
+func (q *asyncQueue) enqueue(ctx context.Context, level zerolog.Level, record log.Record) bool {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+	if q.closed {
+		q.drops.Add(1)
+		return false
+	}
+	select {
+	case q.ch <- queued{ctx: context.WithoutCancel(ctx), level: level, record: record}:
+		return true
+	default:
+		q.drops.Add(1)
+		return false
+	}
+}
+
+// flush blocks until every record queued before the call has been emitted.
+func (q *asyncQueue) flush(ctx context.Context) error {
+	q.mu.RLock()
+	if q.closed {
+		q.mu.RUnlock()
+		return ErrHookClosed
+	}
+	marker := queued{flushed: make(chan struct{})}
+	select {
+	case q.ch <- marker:
+		q.mu.RUnlock()
+	case <-ctx.Done():
+		q.mu.RUnlock()
+		return ctx.Err()
+	}
+
+	select {
+	case <-marker.flushed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// shutdown stops accepting records and waits for the queue to drain.
+func (q *asyncQueue) shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrHookClosed
+	}
+	q.closed = true
+	close(q.ch)
+	q.mu.Unlock()
+
+	select {
+	case <-q.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Flush blocks until all records queued so far have been emitted, or ctx
+// is done. It is a no-op for synchronous hooks.
+func (h *Hook) Flush(ctx context.Context) error {
+	if h.queue == nil {
+		return nil
+	}
+	return h.queue.flush(ctx)
+}
+
+// Shutdown stops the background emitter after draining queued records.
+// Records logged after Shutdown are dropped. It is a no-op for
+// synchronous hooks.
+//
+//	defer func() {
+//	    ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+//	    defer cancel()
+//	    _ = hook.Shutdown(ctx)
+//	}()
+func (h *Hook) Shutdown(ctx context.Context) error {
+	if h.queue == nil {
+		return nil
+	}
+	return h.queue.shutdown(ctx)
+}
+
+// Dropped returns the number of records dropped because the async queue
+// was full or the hook was shut down.
+func (h *Hook) Dropped() int64 {
+	if h.queue == nil {
+		return 0
+	}
+	return h.queue.drops.Load()
+}
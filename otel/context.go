@@ -0,0 +1,64 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// FromCtx returns the logger from ctx bound to ctx itself, so every event
+// carries the context and bridged records are correlated with its active
+// span. Use it instead of zerowrap.FromCtx where spans are started:
+//
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	defer span.End()
+//	log := otel.FromCtx(ctx)
+//	log.Info().Msg("charging card") // record carries the span's trace context
+func FromCtx(ctx context.Context) zerowrap.Logger {
+	return zerowrap.Logger{Logger: zerowrap.FromCtx(ctx).With().Ctx(ctx).Logger()}
+}
+
+// CtxWithSpan returns a context whose logger is bound to ctx (see FromCtx),
+// so downstream zerowrap.FromCtx calls also emit span-correlated records.
+//
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	ctx = otel.CtxWithSpan(ctx)
+func CtxWithSpan(ctx context.Context) context.Context {
+	// Bind the stored logger, not FromCtx's, which carries extractor
+	// fields that zerowrap.FromCtx adds again.
+	return zerowrap.WithCtxZerolog(ctx, zerolog.Ctx(ctx).With().Ctx(ctx).Logger())
+}
+
+// CtxWithBaggageFields returns a context whose logger carries the OTel
+// baggage members of ctx as fields, so metadata propagated across services
+// (tenant, user, feature flags) shows up in logs. Only the given keys are
+// copied; all members are copied if none are given.
+//
+//	// upstream: baggage "tenant_id=acme,plan=pro" propagated via headers
+//	ctx = otel.CtxWithBaggageFields(ctx, "tenant_id", "plan")
+//	zerowrap.FromCtx(ctx).Info().Msg("request") // includes tenant_id and plan
+func CtxWithBaggageFields(ctx context.Context, keys ...string) context.Context {
+	bag := baggage.FromContext(ctx)
+	if bag.Len() == 0 {
+		return ctx
+	}
+
+	fields := make(map[string]any)
+	if len(keys) == 0 {
+		for _, m := range bag.Members() {
+			fields[m.Key()] = m.Value()
+		}
+	} else {
+		for _, key := range keys {
+			if m := bag.Member(key); m.Key() != "" {
+				fields[key] = m.Value()
+			}
+		}
+	}
+	if len(fields) == 0 {
+		return ctx
+	}
+	return zerowrap.CtxWithFields(ctx, fields)
+}
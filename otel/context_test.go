@@ -0,0 +1,169 @@
+package otel_test
+
+import (
+	"context"
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/otel"
+	"go.opentelemetry.io/otel/baggage"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recorder is an SDK log processor keeping the records it receives.
+type recorder struct {
+	mu      sync.Mutex
+	records []sdklog.Record
+}
+
+func (r *recorder) OnEmit(_ context.Context, rec *sdklog.Record) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec.Clone())
+	return nil
+}
+
+func (r *recorder) Enabled(context.Context, sdklog.EnabledParameters) bool { return true }
+func (r *recorder) Shutdown(context.Context) error                         { return nil }
+func (r *recorder) ForceFlush(context.Context) error                       { return nil }
+
+// last returns the last record received.
+func (r *recorder) last(t *testing.T) sdklog.Record {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.records) == 0 {
+		t.Fatal("no record emitted")
+	}
+	return r.records[len(r.records)-1]
+}
+
+// attr returns the string value of the record attribute key.
+func attr(rec sdklog.Record, key string) (string, bool) {
+	var (
+		value string
+		found bool
+	)
+	rec.WalkAttributes(func(kv log.KeyValue) bool {
+		if kv.Key == key {
+			value, found = kv.Value.AsString(), true
+			return false
+		}
+		return true
+	})
+	return value, found
+}
+
+// setup returns a context holding a logger bridged to an SDK logger
+// provider with the returned recorder, and a started span.
+func setup(t *testing.T, opts otel.HookOptions) (context.Context, trace.Span, *recorder) {
+	t.Helper()
+	rec := &recorder{}
+	opts.Provider = sdklog.NewLoggerProvider(sdklog.WithProcessor(rec))
+	log := zerowrap.New(zerowrap.Config{Format: "json", Output: io.Discard}).
+		Hook(otel.NewHookWithOptions("test", opts))
+
+	tp := sdktrace.NewTracerProvider()
+	t.Cleanup(func() { _ = tp.Shutdown(context.Background()) })
+	ctx, span := tp.Tracer("test").Start(zerowrap.WithCtx(context.Background(), zerowrap.Logger{Logger: log}), "checkout")
+	t.Cleanup(func() { span.End() })
+	return ctx, span, rec
+}
+
+func TestFromCtxCorrelatesRecords(t *testing.T) {
+	ctx, span, rec := setup(t, otel.HookOptions{})
+
+	l := otel.FromCtx(ctx)
+	l.Info().Msg("charging card")
+
+	got := rec.last(t)
+	sc := span.SpanContext()
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() {
+		t.Errorf("record trace context = %s/%s, want %s/%s", got.TraceID(), got.SpanID(), sc.TraceID(), sc.SpanID())
+	}
+}
+
+func TestFromCtxWithoutSpan(t *testing.T) {
+	ctx, _, rec := setup(t, otel.HookOptions{})
+
+	l := zerowrap.FromCtx(ctx)
+	l.Info().Msg("unbound")
+
+	if got := rec.last(t); got.TraceID().IsValid() {
+		t.Errorf("record of a logger not bound to ctx has trace ID %s", got.TraceID())
+	}
+}
+
+func TestCtxWithSpanCorrelatesDownstreamLoggers(t *testing.T) {
+	ctx, span, rec := setup(t, otel.HookOptions{TraceFields: true})
+	ctx = otel.CtxWithSpan(ctx)
+
+	l := zerowrap.FromCtxWithField(ctx, zerowrap.FieldUserID, "u-1")
+	l.Warn().Msg("retrying")
+
+	got := rec.last(t)
+	sc := span.SpanContext()
+	if got.TraceID() != sc.TraceID() || got.SpanID() != sc.SpanID() {
+		t.Errorf("record trace context = %s/%s, want %s/%s", got.TraceID(), got.SpanID(), sc.TraceID(), sc.SpanID())
+	}
+	if id, _ := attr(got, zerowrap.FieldTraceID); id != sc.TraceID().String() {
+		t.Errorf("trace_id attribute = %q, want %q", id, sc.TraceID())
+	}
+	if id, _ := attr(got, zerowrap.FieldSpanID); id != sc.SpanID().String() {
+		t.Errorf("span_id attribute = %q, want %q", id, sc.SpanID())
+	}
+	if user, _ := attr(got, zerowrap.FieldUserID); user != "u-1" {
+		t.Errorf("user_id attribute = %q, want u-1", user)
+	}
+}
+
+func TestRecordErrorsSetsSpanStatus(t *testing.T) {
+	rec := &recorder{}
+	log := zerowrap.New(zerowrap.Config{Format: "json", Output: io.Discard}).
+		Hook(otel.NewHookWithOptions("test", otel.HookOptions{
+			Provider:     sdklog.NewLoggerProvider(sdklog.WithProcessor(rec)),
+			RecordErrors: true,
+		}))
+	tp := sdktrace.NewTracerProvider()
+	defer func() { _ = tp.Shutdown(context.Background()) }()
+	ctx, span := tp.Tracer("test").Start(zerowrap.WithCtx(context.Background(), zerowrap.Logger{Logger: log}), "charge")
+
+	l := otel.FromCtx(ctx)
+	l.Error().Str("card", "visa").Msg("payment declined")
+	span.End()
+
+	ro, ok := span.(sdktrace.ReadOnlySpan)
+	if !ok {
+		t.Fatal("span is not an SDK span")
+	}
+	if ro.Status().Description != "payment declined" {
+		t.Errorf("span status = %+v, want error with the message", ro.Status())
+	}
+	if len(ro.Events()) != 1 || ro.Events()[0].Name != "exception" {
+		t.Errorf("span events = %+v, want one exception", ro.Events())
+	}
+}
+
+func TestCtxWithBaggageFields(t *testing.T) {
+	ctx, _, rec := setup(t, otel.HookOptions{})
+	tenant, _ := baggage.NewMember("tenant_id", "acme")
+	plan, _ := baggage.NewMember("plan", "pro")
+	bag, _ := baggage.New(tenant, plan)
+	ctx = baggage.ContextWithBaggage(ctx, bag)
+
+	l := zerowrap.FromCtx(otel.CtxWithBaggageFields(ctx, "tenant_id"))
+	l.Info().Msg("request")
+
+	got := rec.last(t)
+	if v, _ := attr(got, "tenant_id"); v != "acme" {
+		t.Errorf("tenant_id attribute = %q, want acme", v)
+	}
+	if _, ok := attr(got, "plan"); ok {
+		t.Error("plan attribute copied without being requested")
+	}
+}
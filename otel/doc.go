@@ -22,6 +22,58 @@
 //	// Logs now flow to both zerolog output AND OpenTelemetry
 //	zerowrap.FromCtx(ctx).Info().Msg("hello world")
 //
+// # Attributes
+//
+// Event fields, including those inherited from the logger context, are
+// forwarded as record attributes. Strings, integers, floats and booleans
+// map to the matching attribute types; nested objects and arrays map to
+// map and slice values:
+//
+//	log := zerowrap.FromCtxWithField(ctx, "user_id", 42)
+//	log.Info().Str("plan", "pro").Msg("upgraded")
+//	// record attributes: user_id=42 (int64), plan="pro" (string)
+//
+// # Timestamps
+//
+// Each record's timestamp is the event time parsed from the zerolog time
+// field (using zerolog.TimeFieldFormat), and its observed timestamp is the
+// time the hook ran. Events without a parseable time field use the observed
+// time for both. Set zerolog.TimeFieldFormat to a sub-second format such as
+// time.RFC3339Nano for precise event times. The field name and clock are
+// configurable:
+//
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    TimestampField: "ts",
+//	    Clock:          clock.Now,
+//	})
+//
+// # Trace Correlation
+//
+// Records are emitted with the event's context, so the SDK attaches the
+// active span's trace context. zerolog only knows the context when it is
+// bound to the logger or event, so bind it where spans start:
+//
+//	ctx, span := tracer.Start(ctx, "checkout")
+//	defer span.End()
+//	ctx = otel.CtxWithSpan(ctx)           // downstream FromCtx loggers carry the span
+//	otel.FromCtx(ctx).Info().Msg("paid")  // or bind for a single logger
+//
+// Set HookOptions.TraceFields to also add trace_id and span_id attributes.
+//
+// Set HookOptions.RecordErrors to also mark spans as failed from the logs:
+// error, fatal and panic events record their error field on the active span
+// (span.RecordError) and set its status to Error with the log message.
+//
+// # Baggage Fields
+//
+// CtxWithBaggageFields copies OTel baggage members into the context
+// logger's fields, so metadata propagated across services appears in logs:
+//
+//	ctx = otel.CtxWithBaggageFields(ctx, "tenant_id", "feature_flags")
+//	zerowrap.FromCtx(ctx).Info().Msg("request") // includes tenant_id, feature_flags
+//
+// With no keys, every baggage member is copied.
+//
 // # Custom Provider
 //
 // To use a specific logger provider instead of the global one:
@@ -29,4 +81,88 @@
 //	provider := // your OTel logger provider
 //	hook := otel.NewHookWithProvider(provider, "my-service")
 //	log := zerowrap.New(cfg).Hook(hook)
+//
+// # Options
+//
+// NewHookWithOptions accepts a HookOptions struct for further configuration:
+//
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    Provider:      loggerProvider, // defaults to the global provider
+//	    MeterProvider: meterProvider,  // enables log volume metrics
+//	})
+//
+// # Scope and Default Attributes
+//
+// Set the instrumentation scope metadata and attributes added to every record:
+//
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    Version:         "1.4.2",
+//	    SchemaURL:       "https://opentelemetry.io/schemas/1.26.0",
+//	    ScopeAttributes: []attribute.KeyValue{attribute.String("team", "payments")},
+//	    Attributes: []log.KeyValue{
+//	        log.String("deployment.environment", "production"),
+//	        log.String("service.version", version),
+//	    },
+//	})
+//
+// # Levels and Severities
+//
+// Export only part of the stream, or remap severities:
+//
+//	minLevel := zerolog.WarnLevel // debug/info stay local, warn+ is exported
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    MinLevel: &minLevel,
+//	    SeverityMapper: func(l zerolog.Level) log.Severity {
+//	        if l == zerolog.PanicLevel {
+//	            return log.SeverityFatal2
+//	        }
+//	        return otel.DefaultSeverity(l)
+//	    },
+//	})
+//
+// # Async Emit
+//
+// With Async set, records are queued and emitted from a background
+// goroutine, so slow exporters never stall the logging hot path. When the
+// bounded queue is full, records are dropped and counted (see Dropped and
+// the log.dropped metric). Drain the queue before exit:
+//
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    Async:     true,
+//	    QueueSize: 4096,
+//	})
+//	defer hook.Shutdown(context.Background())
+//
+// # OTLP Writer
+//
+// For apps that want OTLP logs without the OpenTelemetry SDK or tracing,
+// NewOTLPWriter returns an io.Writer that parses zerolog JSON lines and
+// exports them over OTLP/HTTP or OTLP/gRPC using a private provider:
+//
+//	w, err := otel.NewOTLPWriter("collector:4317", otel.OTLPWriterOptions{
+//	    Protocol:    otel.ProtocolGRPC,
+//	    ServiceName: "my-service",
+//	    Headers:     map[string]string{"api-key": key},
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Shutdown(context.Background())
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: io.MultiWriter(os.Stderr, w),
+//	})
+//
+// The level, message and time fields map to the record severity, body and
+// timestamp; other fields become attributes. Records are batched, so call
+// Flush or Shutdown before exit.
+//
+// # Metrics
+//
+// When MeterProvider is set, the hook records:
+//
+//	log.records  counter of bridged records, by severity
+//	log.dropped  counter of records the bridge failed to emit or dropped
+//	             from a full async queue, by severity
 package otel
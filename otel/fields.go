@@ -0,0 +1,154 @@
+package otel
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/bnema/zerowrap/internal/eventbuf"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/log"
+)
+
+// errNotObject is returned by decodeJSON when the input is not a JSON object.
+var errNotObject = errors.New("otel: not a JSON object")
+
+// eventFields converts the fields buffered in e to OTel attributes and
+// returns the event timestamp read from timeField (zero if absent).
+// The level, message and timestamp fields are skipped since they map to
+// dedicated record fields. Returns nil if the buffer cannot be parsed
+// (e.g. when zerolog is built with binary encoding).
+func eventFields(e *zerolog.Event, timeField string) ([]log.KeyValue, time.Time) {
+	data := eventbuf.JSON(e)
+	if data == nil {
+		return nil, time.Time{}
+	}
+
+	kvs, err := decodeJSON(data)
+	if err != nil {
+		return nil, time.Time{}
+	}
+
+	var ts time.Time
+	out := kvs[:0]
+	for _, kv := range kvs {
+		switch kv.Key {
+		case timeField:
+			ts = parseTimestamp(kv.Value)
+			continue
+		case zerolog.LevelFieldName, zerolog.MessageFieldName:
+			continue
+		}
+		out = append(out, kv)
+	}
+	return out, ts
+}
+
+// parseTimestamp parses a timestamp written with zerolog.TimeFieldFormat.
+// Returns the zero time if v cannot be parsed.
+func parseTimestamp(v log.Value) time.Time {
+	switch v.Kind() {
+	case log.KindString:
+		t, err := time.Parse(zerolog.TimeFieldFormat, v.AsString())
+		if err != nil {
+			return time.Time{}
+		}
+		return t
+	case log.KindInt64:
+		n := v.AsInt64()
+		switch zerolog.TimeFieldFormat {
+		case zerolog.TimeFormatUnixMs:
+			return time.UnixMilli(n)
+		case zerolog.TimeFormatUnixMicro:
+			return time.UnixMicro(n)
+		case zerolog.TimeFormatUnixNano:
+			return time.Unix(0, n)
+		default:
+			return time.Unix(n, 0)
+		}
+	case log.KindFloat64:
+		f := v.AsFloat64()
+		return time.Unix(0, int64(f*float64(time.Second)))
+	default:
+		return time.Time{}
+	}
+}
+
+// decodeJSON decodes a JSON object into OTel key-values, preserving field order.
+func decodeJSON(data []byte) ([]log.KeyValue, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	if tok != json.Delim('{') {
+		return nil, errNotObject
+	}
+	return decodeObject(dec)
+}
+
+// decodeObject decodes object members up to and including the closing brace.
+func decodeObject(dec *json.Decoder) ([]log.KeyValue, error) {
+	var kvs []log.KeyValue
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key, _ := tok.(string)
+		val, err := decodeValue(dec)
+		if err != nil {
+			return nil, err
+		}
+		kvs = append(kvs, log.KeyValue{Key: key, Value: val})
+	}
+	if _, err := dec.Token(); err != nil {
+		return nil, err
+	}
+	return kvs, nil
+}
+
+// decodeValue decodes the next JSON value into an OTel log.Value.
+// Integral numbers map to Int64Value, other numbers to Float64Value.
+func decodeValue(dec *json.Decoder) (log.Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return log.Value{}, err
+	}
+	switch v := tok.(type) {
+	case json.Delim:
+		if v == '{' {
+			kvs, err := decodeObject(dec)
+			if err != nil {
+				return log.Value{}, err
+			}
+			return log.MapValue(kvs...), nil
+		}
+		var vals []log.Value
+		for dec.More() {
+			val, err := decodeValue(dec)
+			if err != nil {
+				return log.Value{}, err
+			}
+			vals = append(vals, val)
+		}
+		if _, err := dec.Token(); err != nil {
+			return log.Value{}, err
+		}
+		return log.SliceValue(vals...), nil
+	case string:
+		return log.StringValue(v), nil
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return log.Int64Value(i), nil
+		}
+		f, _ := v.Float64()
+		return log.Float64Value(f), nil
+	case bool:
+		return log.BoolValue(v), nil
+	default:
+		return log.Value{}, nil
+	}
+}
@@ -0,0 +1,74 @@
+package otel
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// hookMetrics records log volume counters for a Hook.
+// A nil *hookMetrics records nothing.
+type hookMetrics struct {
+	records  metric.Int64Counter
+	drops    metric.Int64Counter
+	severity func(zerolog.Level) log.Severity
+	attrs    map[zerolog.Level]metric.AddOption
+}
+
+// newHookMetrics creates the log.records and log.dropped counters,
+// labeled with the severity produced by the hook's mapper.
+// Instruments that fail to register fall back to no-ops.
+func newHookMetrics(meter metric.Meter, severity func(zerolog.Level) log.Severity) *hookMetrics {
+	records, err := meter.Int64Counter("log.records",
+		metric.WithDescription("Number of log records bridged to OpenTelemetry."),
+		metric.WithUnit("{record}"),
+	)
+	if err != nil {
+		return nil
+	}
+	drops, err := meter.Int64Counter("log.dropped",
+		metric.WithDescription("Number of log records the bridge failed to emit or dropped."),
+		metric.WithUnit("{record}"),
+	)
+	if err != nil {
+		return nil
+	}
+
+	attrs := make(map[zerolog.Level]metric.AddOption)
+	for l := zerolog.TraceLevel; l <= zerolog.PanicLevel; l++ {
+		attrs[l] = metric.WithAttributeSet(severityAttrs(severity(l)))
+	}
+	return &hookMetrics{records: records, drops: drops, severity: severity, attrs: attrs}
+}
+
+// recorded counts one bridged record at level.
+func (m *hookMetrics) recorded(ctx context.Context, level zerolog.Level) {
+	if m == nil {
+		return
+	}
+	m.records.Add(ctx, 1, m.attrsFor(level))
+}
+
+// dropped counts one record at level that could not be emitted.
+func (m *hookMetrics) dropped(ctx context.Context, level zerolog.Level) {
+	if m == nil {
+		return
+	}
+	m.drops.Add(ctx, 1, m.attrsFor(level))
+}
+
+// attrsFor returns the precomputed attribute option for level.
+func (m *hookMetrics) attrsFor(level zerolog.Level) metric.AddOption {
+	if opt, ok := m.attrs[level]; ok {
+		return opt
+	}
+	return metric.WithAttributeSet(severityAttrs(m.severity(level)))
+}
+
+// severityAttrs returns the metric attributes for a severity.
+func severityAttrs(severity log.Severity) attribute.Set {
+	return attribute.NewSet(attribute.String("severity", severity.String()))
+}
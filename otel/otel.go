@@ -2,36 +2,185 @@ package otel
 
 import (
 	"context"
+	"time"
 
+	"github.com/bnema/zerowrap"
 	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/log"
 	"go.opentelemetry.io/otel/log/global"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// HookOptions configures a Hook created with NewHookWithOptions.
+type HookOptions struct {
+	// Provider is the OpenTelemetry logger provider.
+	// Defaults to the global logger provider if nil.
+	Provider log.LoggerProvider
+
+	// MeterProvider enables log volume metrics when set:
+	// log.records counts bridged records by severity, and
+	// log.dropped counts records the bridge failed to emit.
+	MeterProvider metric.MeterProvider
+
+	// Version is the instrumentation scope version.
+	Version string
+
+	// SchemaURL is the schema URL of the emitted telemetry.
+	SchemaURL string
+
+	// ScopeAttributes are the instrumentation scope attributes.
+	ScopeAttributes []attribute.KeyValue
+
+	// Attributes are added to every record, e.g. deployment.environment
+	// or service.version when the provider's resource does not carry them.
+	Attributes []log.KeyValue
+
+	// MinLevel is the minimum level bridged to OpenTelemetry; events below
+	// it are still written by zerolog but not exported.
+	// Defaults to zerolog.TraceLevel, bridging everything, if nil.
+	MinLevel *zerolog.Level
+
+	// SeverityMapper converts zerolog levels to OpenTelemetry severities.
+	// Defaults to DefaultSeverity if nil.
+	SeverityMapper func(zerolog.Level) log.Severity
+
+	// TimestampField is the event field holding the event time.
+	// Defaults to zerolog.TimestampFieldName if empty.
+	TimestampField string
+
+	// Clock returns the current time, used for the observed timestamp and
+	// as the record timestamp when the event has no parseable time field.
+	// Defaults to time.Now if nil.
+	Clock func() time.Time
+
+	// Async emits records from a background goroutine through a bounded
+	// queue, so slow exporters never block logging. Records are dropped
+	// (and counted) when the queue is full. Call Flush or Shutdown before
+	// exit to drain the queue.
+	Async bool
+
+	// QueueSize is the async queue capacity.
+	// Defaults to 1024 if 0.
+	QueueSize int
+
+	// TraceFields adds trace_id and span_id attributes from the active span,
+	// for backends that do not correlate on the record's trace context.
+	TraceFields bool
+
+	// RecordErrors records error, fatal and panic events on the active span
+	// of the event context: the error is added with span.RecordError and
+	// the span status is set to Error. Applies even when MinLevel or the
+	// provider filters the event out of the log pipeline.
+	RecordErrors bool
+}
+
 // Hook is a zerolog.Hook that bridges logs to OpenTelemetry.
 type Hook struct {
-	logger log.Logger
+	logger       log.Logger
+	attrs        []log.KeyValue
+	metrics      *hookMetrics
+	minLevel     zerolog.Level
+	severity     func(zerolog.Level) log.Severity
+	timeField    string
+	clock        func() time.Time
+	traceFields  bool
+	recordErrors bool
+	queue        *asyncQueue
 }
 
 // NewHook creates a hook that forwards zerolog events to OpenTelemetry.
 // Uses the global logger provider.
 func NewHook(serviceName string) *Hook {
-	return &Hook{
-		logger: global.GetLoggerProvider().Logger(serviceName),
-	}
+	return NewHookWithOptions(serviceName, HookOptions{})
 }
 
 // NewHookWithProvider creates a hook with a specific logger provider.
 func NewHookWithProvider(provider log.LoggerProvider, serviceName string) *Hook {
-	return &Hook{
-		logger: provider.Logger(serviceName),
+	return NewHookWithOptions(serviceName, HookOptions{Provider: provider})
+}
+
+// NewHookWithOptions creates a hook with the given options.
+//
+//	minLevel := zerolog.WarnLevel // only export warn and above
+//	hook := otel.NewHookWithOptions("my-service", otel.HookOptions{
+//	    Provider:      loggerProvider,
+//	    MeterProvider: meterProvider,
+//	    MinLevel:      &minLevel,
+//	    Version:       "1.4.2",
+//	    SchemaURL:     semconv.SchemaURL,
+//	    Attributes: []log.KeyValue{
+//	        log.String("deployment.environment", "production"),
+//	    },
+//	})
+func NewHookWithOptions(serviceName string, opts HookOptions) *Hook {
+	provider := opts.Provider
+	if provider == nil {
+		provider = global.GetLoggerProvider()
+	}
+
+	minLevel := zerolog.TraceLevel
+	if opts.MinLevel != nil {
+		minLevel = *opts.MinLevel
+	}
+
+	severity := opts.SeverityMapper
+	if severity == nil {
+		severity = DefaultSeverity
+	}
+
+	timeField := opts.TimestampField
+	if timeField == "" {
+		timeField = zerolog.TimestampFieldName
+	}
+	clock := opts.Clock
+	if clock == nil {
+		clock = time.Now
 	}
+
+	var loggerOpts []log.LoggerOption
+	if opts.Version != "" {
+		loggerOpts = append(loggerOpts, log.WithInstrumentationVersion(opts.Version))
+	}
+	if opts.SchemaURL != "" {
+		loggerOpts = append(loggerOpts, log.WithSchemaURL(opts.SchemaURL))
+	}
+	if len(opts.ScopeAttributes) > 0 {
+		loggerOpts = append(loggerOpts, log.WithInstrumentationAttributes(opts.ScopeAttributes...))
+	}
+
+	h := &Hook{
+		logger:       provider.Logger(serviceName, loggerOpts...),
+		attrs:        opts.Attributes,
+		minLevel:     minLevel,
+		severity:     severity,
+		timeField:    timeField,
+		clock:        clock,
+		traceFields:  opts.TraceFields,
+		recordErrors: opts.RecordErrors,
+	}
+	if opts.MeterProvider != nil {
+		h.metrics = newHookMetrics(opts.MeterProvider.Meter(serviceName), severity)
+	}
+	if opts.Async {
+		h.queue = newAsyncQueue(opts.QueueSize, h.emit)
+	}
+	return h
 }
 
 // Run implements zerolog.Hook interface.
-// It forwards log events to the OpenTelemetry logger.
+// It forwards log events to the OpenTelemetry logger, with the event's
+// fields (including those inherited from the logger context) as attributes.
+//
+// Records are emitted with the event's context (see FromCtx), so the SDK
+// attaches the active span's trace context to them.
 func (h *Hook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
-	if h.logger == nil {
+	if h.logger == nil || level == zerolog.Disabled {
+		return
+	}
+	recordErr := h.recordErrors && level >= zerolog.ErrorLevel
+	if level < h.minLevel && !recordErr {
 		return
 	}
 
@@ -40,16 +189,74 @@ func (h *Hook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
 		ctx = context.Background()
 	}
 
+	severity := h.severity(level)
+	export := level >= h.minLevel &&
+		h.logger.Enabled(ctx, log.EnabledParameters{Severity: severity})
+	if !export && !recordErr {
+		return
+	}
+
+	attrs, ts := eventFields(e, h.timeField)
+	if recordErr {
+		recordSpanError(ctx, msg, attrs)
+	}
+	if !export {
+		return
+	}
+	now := h.clock()
+	if ts.IsZero() {
+		ts = now
+	}
+
 	var record log.Record
+	record.SetTimestamp(ts)
+	record.SetObservedTimestamp(now)
 	record.SetBody(log.StringValue(msg))
-	record.SetSeverity(levelToOTel(level))
+	record.SetSeverity(severity)
 	record.SetSeverityText(level.String())
+	record.AddAttributes(h.attrs...)
+	record.AddAttributes(attrs...)
+	if h.traceFields {
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			record.AddAttributes(
+				log.String(zerowrap.FieldTraceID, sc.TraceID().String()),
+				log.String(zerowrap.FieldSpanID, sc.SpanID().String()),
+			)
+		}
+	}
+
+	if h.queue != nil {
+		if !h.queue.enqueue(ctx, level, record) {
+			h.metrics.dropped(ctx, level)
+		}
+		return
+	}
+	h.emit(ctx, level, record)
+}
+
+// emit sends the record and records metrics. A panicking exporter is
+// counted as a dropped record instead of crashing the caller.
+func (h *Hook) emit(ctx context.Context, level zerolog.Level, record log.Record) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.metrics.dropped(ctx, level)
+		}
+	}()
 
 	h.logger.Emit(ctx, record)
+	h.metrics.recorded(ctx, level)
 }
 
-// levelToOTel converts zerolog.Level to OpenTelemetry log.Severity.
-func levelToOTel(level zerolog.Level) log.Severity {
+// DefaultSeverity converts zerolog.Level to OpenTelemetry log.Severity.
+// Custom mappers can delegate to it for levels they do not remap:
+//
+//	SeverityMapper: func(l zerolog.Level) log.Severity {
+//	    if l == zerolog.PanicLevel {
+//	        return log.SeverityFatal2
+//	    }
+//	    return otel.DefaultSeverity(l)
+//	}
+func DefaultSeverity(level zerolog.Level) log.Severity {
 	switch level {
 	case zerolog.TraceLevel:
 		return log.SeverityTrace
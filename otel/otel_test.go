@@ -0,0 +1,42 @@
+package otel_test
+
+import (
+	"io"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/otel"
+	"github.com/rs/zerolog"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+)
+
+func TestHookMinLevel(t *testing.T) {
+	warn := zerolog.WarnLevel
+	tests := []struct {
+		name     string
+		minLevel *zerolog.Level
+		want     int
+	}{
+		{"default", nil, 4},
+		{"warn", &warn, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			log := zerowrap.New(zerowrap.Config{Format: "json", Level: "trace", Output: io.Discard}).
+				Hook(otel.NewHookWithOptions("test", otel.HookOptions{
+					Provider: sdklog.NewLoggerProvider(sdklog.WithProcessor(rec)),
+					MinLevel: tt.minLevel,
+				}))
+
+			log.Trace().Msg("trace")
+			log.Debug().Msg("debug")
+			log.Info().Msg("info")
+			log.Warn().Msg("warn")
+
+			if len(rec.records) != tt.want {
+				t.Errorf("bridged %d records, want %d", len(rec.records), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package otel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploggrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlplog/otlploghttp"
+	"go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+)
+
+// OTLP protocols supported by NewOTLPWriter.
+const (
+	ProtocolHTTP = "http"
+	ProtocolGRPC = "grpc"
+)
+
+// OTLPWriterOptions configures an OTLPWriter.
+type OTLPWriterOptions struct {
+	// Protocol is the OTLP transport, ProtocolHTTP or ProtocolGRPC.
+	// Defaults to ProtocolHTTP if empty.
+	Protocol string
+
+	// Insecure disables TLS for the exporter connection.
+	Insecure bool
+
+	// Headers are sent with every export request, e.g. API keys.
+	Headers map[string]string
+
+	// ServiceName sets the service.name resource attribute.
+	ServiceName string
+
+	// ResourceAttributes are added to the exported resource.
+	ResourceAttributes []attribute.KeyValue
+
+	// Attributes are added to every record.
+	Attributes []log.KeyValue
+
+	// SeverityMapper converts zerolog levels to OpenTelemetry severities.
+	// Defaults to DefaultSeverity if nil.
+	SeverityMapper func(zerolog.Level) log.Severity
+
+	// TimestampField is the JSON field holding the event time.
+	// Defaults to zerolog.TimestampFieldName if empty.
+	TimestampField string
+}
+
+// OTLPWriter is an io.Writer that parses zerolog JSON lines and exports
+// them as OTLP log records. It owns a private logger provider, so no
+// global OpenTelemetry SDK setup is required.
+type OTLPWriter struct {
+	provider  *sdklog.LoggerProvider
+	logger    log.Logger
+	attrs     []log.KeyValue
+	severity  func(zerolog.Level) log.Severity
+	timeField string
+}
+
+// NewOTLPWriter creates a writer exporting logs to an OTLP collector.
+// The endpoint is either a URL ("https://collector:4318/v1/logs") or a
+// host and port ("collector:4317").
+//
+//	w, err := otel.NewOTLPWriter("localhost:4318", otel.OTLPWriterOptions{
+//	    ServiceName: "my-service",
+//	    Insecure:    true,
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer w.Shutdown(context.Background())
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: io.MultiWriter(os.Stderr, w),
+//	})
+func NewOTLPWriter(endpoint string, opts OTLPWriterOptions) (*OTLPWriter, error) {
+	exporter, err := newOTLPExporter(endpoint, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := opts.ResourceAttributes
+	if opts.ServiceName != "" {
+		attrs = append([]attribute.KeyValue{attribute.String("service.name", opts.ServiceName)}, attrs...)
+	}
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(attrs...))
+	if err != nil {
+		return nil, fmt.Errorf("otel: resource: %w", err)
+	}
+
+	severity := opts.SeverityMapper
+	if severity == nil {
+		severity = DefaultSeverity
+	}
+	timeField := opts.TimestampField
+	if timeField == "" {
+		timeField = zerolog.TimestampFieldName
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithResource(res),
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	return &OTLPWriter{
+		provider:  provider,
+		logger:    provider.Logger("github.com/bnema/zerowrap/otel"),
+		attrs:     opts.Attributes,
+		severity:  severity,
+		timeField: timeField,
+	}, nil
+}
+
+// newOTLPExporter creates the exporter for the configured protocol.
+func newOTLPExporter(endpoint string, opts OTLPWriterOptions) (sdklog.Exporter, error) {
+	isURL := strings.Contains(endpoint, "://")
+	ctx := context.Background()
+
+	switch opts.Protocol {
+	case "", ProtocolHTTP:
+		var o []otlploghttp.Option
+		if isURL {
+			o = append(o, otlploghttp.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			o = append(o, otlploghttp.WithEndpoint(endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlploghttp.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			o = append(o, otlploghttp.WithHeaders(opts.Headers))
+		}
+		return otlploghttp.New(ctx, o...)
+	case ProtocolGRPC:
+		var o []otlploggrpc.Option
+		if isURL {
+			o = append(o, otlploggrpc.WithEndpointURL(endpoint))
+		} else if endpoint != "" {
+			o = append(o, otlploggrpc.WithEndpoint(endpoint))
+		}
+		if opts.Insecure {
+			o = append(o, otlploggrpc.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			o = append(o, otlploggrpc.WithHeaders(opts.Headers))
+		}
+		return otlploggrpc.New(ctx, o...)
+	default:
+		return nil, fmt.Errorf("otel: unsupported OTLP protocol %q", opts.Protocol)
+	}
+}
+
+// Write implements io.Writer. Each line of p is parsed as a zerolog JSON
+// event and queued for export; lines that are not JSON objects are
+// exported with the raw line as body. Write never fails, so it is safe
+// to combine with other writers in io.MultiWriter.
+func (w *OTLPWriter) Write(p []byte) (int, error) {
+	now := time.Now()
+	for line := range bytes.SplitSeq(p, []byte{'\n'}) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		w.logger.Emit(context.Background(), w.record(line, now))
+	}
+	return len(p), nil
+}
+
+// record converts a JSON line to an OTel log record.
+func (w *OTLPWriter) record(line []byte, now time.Time) log.Record {
+	var record log.Record
+	record.SetObservedTimestamp(now)
+	record.AddAttributes(w.attrs...)
+
+	kvs, err := decodeJSON(line)
+	if err != nil {
+		record.SetTimestamp(now)
+		record.SetBody(log.StringValue(string(line)))
+		return record
+	}
+
+	ts := now
+	for _, kv := range kvs {
+		switch kv.Key {
+		case zerolog.LevelFieldName:
+			if level, err := zerolog.ParseLevel(kv.Value.AsString()); err == nil {
+				record.SetSeverity(w.severity(level))
+				record.SetSeverityText(level.String())
+			}
+		case zerolog.MessageFieldName:
+			record.SetBody(kv.Value)
+		case w.timeField:
+			if t := parseTimestamp(kv.Value); !t.IsZero() {
+				ts = t
+			}
+		default:
+			record.AddAttributes(kv)
+		}
+	}
+	record.SetTimestamp(ts)
+	return record
+}
+
+// Flush exports all queued records.
+func (w *OTLPWriter) Flush(ctx context.Context) error {
+	return w.provider.ForceFlush(ctx)
+}
+
+// Shutdown flushes queued records and closes the exporter.
+// Records written after Shutdown are discarded.
+func (w *OTLPWriter) Shutdown(ctx context.Context) error {
+	return w.provider.Shutdown(ctx)
+}
+
+// Close implements io.Closer by calling Shutdown with a 5 second timeout.
+func (w *OTLPWriter) Close() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return w.Shutdown(ctx)
+}
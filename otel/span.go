@@ -0,0 +1,38 @@
+package otel
+
+import (
+	"context"
+	"errors"
+
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/log"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpanError records an error event on the active span in ctx.
+// The recorded error is the event's error field, or msg if it has none.
+func recordSpanError(ctx context.Context, msg string, attrs []log.KeyValue) {
+	span := trace.SpanFromContext(ctx)
+	if !span.IsRecording() {
+		return
+	}
+
+	errMsg := msg
+	for _, kv := range attrs {
+		if kv.Key == zerolog.ErrorFieldName && kv.Value.Kind() == log.KindString {
+			errMsg = kv.Value.AsString()
+			break
+		}
+	}
+	if errMsg == "" {
+		errMsg = "error"
+	}
+
+	desc := msg
+	if desc == "" {
+		desc = errMsg
+	}
+	span.RecordError(errors.New(errMsg))
+	span.SetStatus(codes.Error, desc)
+}
@@ -0,0 +1,31 @@
+// Package pgxlog provides pgx statement logging for zerowrap.
+//
+// This is an optional sub-package that adds the pgx dependency. Its
+// tracer logs the statements of native pgx connections and pools with
+// the same fields and level rules as sqllog, which covers pgx used
+// through database/sql: normalized query, query_hash, duration and
+// error, with the logger of the statement context.
+//
+// # Usage
+//
+//	import (
+//	    "github.com/bnema/zerowrap/pgxlog"
+//	    "github.com/bnema/zerowrap/sqllog"
+//	    "github.com/jackc/pgx/v5/pgxpool"
+//	)
+//
+//	cfg, err := pgxpool.ParseConfig(dsn)
+//	cfg.ConnConfig.Tracer = pgxlog.NewTracer(sqllog.Options{
+//	    Database:      "orders",
+//	    SlowThreshold: 200 * time.Millisecond,
+//	})
+//	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+//
+//	pool.QueryRow(ctx, "SELECT name FROM users WHERE id = $1", 42).Scan(&name)
+//	// {"level":"debug","database":"orders","query":"SELECT name FROM users WHERE id = ?",
+//	//  "query_hash":"87154d352aba922e","duration_ms":0.41,"message":"query"}
+//
+// Statements are counted in the query counter of their context, so
+// MaxQueries and MaxRepeats warn about N+1 queries once the request
+// context is set up with sqllog.WithQueryCounter.
+package pgxlog
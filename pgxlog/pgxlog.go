@@ -0,0 +1,80 @@
+package pgxlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/bnema/zerowrap/sqllog"
+	"github.com/jackc/pgx/v5"
+)
+
+// Tracer is a pgx tracer logging every statement through
+// sqllog.Options.Record: normalized, with its query_hash, duration and
+// error, with the logger of the statement context. It implements
+// pgx.QueryTracer and pgx.BatchTracer.
+type Tracer struct {
+	opts sqllog.Options
+}
+
+// NewTracer returns a tracer logging statements with opts. Set it as the
+// Tracer of a connection or pool configuration:
+//
+//	cfg, err := pgxpool.ParseConfig(dsn)
+//	cfg.ConnConfig.Tracer = pgxlog.NewTracer(sqllog.Options{Database: "orders"})
+//	pool, err := pgxpool.NewWithConfig(ctx, cfg)
+func NewTracer(opts sqllog.Options) *Tracer {
+	return &Tracer{opts: opts}
+}
+
+// statementKey is the context key of a running statement.
+type statementKey struct{}
+
+// statement is a statement started by TraceQueryStart.
+type statement struct {
+	sql   string
+	start time.Time
+}
+
+// TraceQueryStart implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, statementKey{}, statement{sql: data.SQL, start: time.Now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer.
+func (t *Tracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	s, ok := ctx.Value(statementKey{}).(statement)
+	if !ok {
+		return
+	}
+	t.opts.Record(ctx, s.sql, time.Since(s.start), data.Err)
+}
+
+// batchKey is the context key of a running batch.
+type batchKey struct{}
+
+// batch holds the end time of the last query of a batch, from which the
+// duration of the next one is measured.
+type batch struct {
+	last time.Time
+}
+
+// TraceBatchStart implements pgx.BatchTracer.
+func (t *Tracer) TraceBatchStart(ctx context.Context, _ *pgx.Conn, _ pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, batchKey{}, &batch{last: time.Now()})
+}
+
+// TraceBatchQuery implements pgx.BatchTracer. The queries of a batch are
+// sent together, so each is logged with the time since the previous
+// result was read.
+func (t *Tracer) TraceBatchQuery(ctx context.Context, _ *pgx.Conn, data pgx.TraceBatchQueryData) {
+	b, ok := ctx.Value(batchKey{}).(*batch)
+	if !ok {
+		return
+	}
+	now := time.Now()
+	t.opts.Record(ctx, data.SQL, now.Sub(b.last), data.Err)
+	b.last = now
+}
+
+// TraceBatchEnd implements pgx.BatchTracer.
+func (t *Tracer) TraceBatchEnd(context.Context, *pgx.Conn, pgx.TraceBatchEndData) {}
@@ -0,0 +1,64 @@
+package pgxlog_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/pgxlog"
+	"github.com/bnema/zerowrap/sqllog"
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+)
+
+func TestTracerLogsNormalizedStatements(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerowrap.WithCtxZerolog(context.Background(), zerolog.New(&buf))
+	tr := pgxlog.NewTracer(sqllog.Options{Database: "orders"})
+
+	qctx := tr.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT name FROM users WHERE id = $1"})
+	tr.TraceQueryEnd(qctx, nil, pgx.TraceQueryEndData{Err: errors.New("boom")})
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("entry %q: %v", buf.String(), err)
+	}
+	want := map[string]any{
+		"level":                  "error",
+		zerowrap.FieldDatabase:   "orders",
+		zerowrap.FieldQuery:      "SELECT name FROM users WHERE id = ?",
+		zerowrap.FieldQueryHash:  zerowrap.QueryHash("SELECT name FROM users WHERE id = $1"),
+		zerolog.ErrorFieldName:   "boom",
+		zerolog.MessageFieldName: "query",
+	}
+	for k, v := range want {
+		if entry[k] != v {
+			t.Errorf("%s = %v, want %v", k, entry[k], v)
+		}
+	}
+	if _, ok := entry[zerowrap.FieldDuration]; !ok {
+		t.Errorf("no %s in %s", zerowrap.FieldDuration, buf.String())
+	}
+}
+
+func TestTracerCountsBatchQueries(t *testing.T) {
+	var buf bytes.Buffer
+	ctx := zerowrap.WithCtxZerolog(sqllog.WithQueryCounter(context.Background()), zerolog.New(&buf))
+	tr := pgxlog.NewTracer(sqllog.Options{MaxRepeats: 1})
+
+	bctx := tr.TraceBatchStart(ctx, nil, pgx.TraceBatchStartData{})
+	for _, id := range []string{"1", "2"} {
+		tr.TraceBatchQuery(bctx, nil, pgx.TraceBatchQueryData{SQL: "SELECT * FROM items WHERE id = " + id})
+	}
+	tr.TraceBatchEnd(bctx, nil, pgx.TraceBatchEndData{})
+
+	if n := sqllog.QueryCount(ctx); n != 2 {
+		t.Errorf("QueryCount = %d, want 2", n)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("possible N+1")) {
+		t.Errorf("no repeated query warning in %s", buf.String())
+	}
+}
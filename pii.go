@@ -0,0 +1,327 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// PIIPattern detects one kind of personal data in string values.
+type PIIPattern struct {
+	// Name identifies the kind of data, e.g. "email", used in the mask
+	// and in PIIMasker.Counts.
+	Name string
+
+	// Regexp matches candidate values.
+	Regexp *regexp.Regexp
+
+	// Valid, if set, filters matches, e.g. with a checksum, so numbers
+	// that merely look like an identifier are kept.
+	Valid func(match string) bool
+}
+
+// Patterns detecting personal data regardless of the country.
+var (
+	PIIEmail = PIIPattern{
+		Name:   "email",
+		Regexp: regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9\-]+(?:\.[A-Za-z0-9\-]+)*\.[A-Za-z]{2,}`),
+	}
+
+	// PIIPhone matches international numbers starting with +; national
+	// formats are in the country packs.
+	PIIPhone = PIIPattern{
+		Name:   "phone",
+		Regexp: regexp.MustCompile(`\+[1-9]\d{0,2}(?:[ .\-]?\(?\d{1,4}\)?){2,5}\b`),
+		Valid:  func(m string) bool { return countDigits(m) >= 8 },
+	}
+
+	PIIIBAN = PIIPattern{
+		Name:   "iban",
+		Regexp: regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,3})?\b`),
+		Valid:  validIBAN,
+	}
+)
+
+// DefaultPIIPatterns lists the patterns used by PIIMasker when
+// PIIConfig.Patterns is nil.
+var DefaultPIIPatterns = []PIIPattern{PIIEmail, PIIIBAN, PIIPhone}
+
+// PIICountries holds the country packs selected with
+// PIIConfig.Countries, keyed by ISO 3166 alpha-2 code in lower case:
+// national identifiers and national phone number formats. Add packs
+// before creating maskers.
+var PIICountries = map[string][]PIIPattern{
+	"us": {
+		{Name: "ssn", Regexp: regexp.MustCompile(`\b\d{3}-\d{2}-\d{4}\b`)},
+		{Name: "phone", Regexp: regexp.MustCompile(`\(?\b\d{3}\)?[ .\-]\d{3}[ .\-]\d{4}\b`)},
+	},
+	"uk": {
+		{Name: "nino", Regexp: regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z]{2} ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`)},
+		{Name: "phone", Regexp: regexp.MustCompile(`\b0[1-9]\d{2,3} ?\d{3} ?\d{3,4}\b`)},
+	},
+	"fr": {
+		{Name: "nir", Regexp: regexp.MustCompile(`\b[12] ?\d{2} ?(?:0[1-9]|1[0-2]) ?(?:\d{2}|2[AB]) ?\d{3} ?\d{3} ?\d{2}\b`)},
+		{Name: "phone", Regexp: regexp.MustCompile(`\b0[1-9](?:[ .\-]?\d{2}){4}\b`)},
+	},
+	"es": {
+		{Name: "dni", Regexp: regexp.MustCompile(`\b[XYZ0-9]\d{7}[A-Z]\b`), Valid: validDNI},
+		{Name: "phone", Regexp: regexp.MustCompile(`\b[6789]\d{2} ?\d{3} ?\d{3}\b`)},
+	},
+	"it": {
+		{Name: "codice_fiscale", Regexp: regexp.MustCompile(`\b[A-Z]{6}\d{2}[A-EHLMPR-T]\d{2}[A-Z]\d{3}[A-Z]\b`)},
+		{Name: "phone", Regexp: regexp.MustCompile(`\b3\d{2} ?\d{3} ?\d{3,4}\b`)},
+	},
+}
+
+// PIIConfig holds configuration for a PII masker.
+type PIIConfig struct {
+	// Patterns lists the kinds of data detected.
+	// Defaults to DefaultPIIPatterns if nil.
+	Patterns []PIIPattern
+
+	// Countries adds the PIICountries packs of these countries, e.g.
+	// []string{"fr", "es"}.
+	Countries []string
+
+	// ExemptFields lists top-level fields never masked, e.g. a field
+	// holding an already hashed user ID, or the email of a support
+	// mailbox logged on purpose.
+	ExemptFields []string
+
+	// Mask returns the replacement of a detected value.
+	// Defaults to "[REDACTED:<name>]".
+	Mask func(name, value string) string
+}
+
+// PIIMasker is a zerolog.LevelWriter that masks personal data (emails,
+// phone numbers, IBANs, national identifiers) found in the string values
+// of JSON entries, including the message and nested values, before
+// passing them on. Masked values are counted by kind (see Counts) and in
+// DiagnosticStats redactions. Entries that are not JSON objects are
+// masked as plain text.
+//
+// Detection is pattern based: it catches personal data logged by
+// mistake, it does not replace deliberate redaction of known fields.
+type PIIMasker struct {
+	out      io.Writer
+	patterns []PIIPattern
+	exempt   map[string]bool
+	mask     func(name, value string) string
+
+	masked atomic.Int64
+	mu     sync.Mutex
+	counts map[string]int64
+}
+
+// NewPIIMasker wraps out to mask personal data.
+//
+//	masker := zerowrap.NewPIIMasker(os.Stdout, zerowrap.PIIConfig{
+//	    Countries:    []string{"fr"},
+//	    ExemptFields: []string{"support_email"},
+//	})
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: masker})
+//	log.Info().Str("to", "jane@example.com").Msg("invoice sent")
+//	// {"level":"info","to":"[REDACTED:email]","message":"invoice sent",...}
+func NewPIIMasker(out io.Writer, cfg PIIConfig) *PIIMasker {
+	patterns := cfg.Patterns
+	if patterns == nil {
+		patterns = DefaultPIIPatterns
+	}
+	patterns = append([]PIIPattern(nil), patterns...)
+	for _, c := range cfg.Countries {
+		patterns = append(patterns, PIICountries[strings.ToLower(c)]...)
+	}
+	mask := cfg.Mask
+	if mask == nil {
+		mask = func(name, _ string) string { return "[REDACTED:" + name + "]" }
+	}
+	exempt := make(map[string]bool, len(cfg.ExemptFields))
+	for _, f := range cfg.ExemptFields {
+		exempt[f] = true
+	}
+	return &PIIMasker{
+		out:      out,
+		patterns: patterns,
+		exempt:   exempt,
+		mask:     mask,
+		counts:   map[string]int64{},
+	}
+}
+
+// Write implements io.Writer.
+func (m *PIIMasker) Write(p []byte) (int, error) {
+	return m.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (m *PIIMasker) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if !m.anyMatch(p) {
+		return writeLevel(m.out, level, p)
+	}
+	out, n := m.maskEntry(p)
+	if n == 0 {
+		return writeLevel(m.out, level, p)
+	}
+	ReportRedacted(n)
+	if _, err := writeLevel(m.out, level, out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Masked returns the number of values masked.
+func (m *PIIMasker) Masked() int64 {
+	return m.masked.Load()
+}
+
+// Counts returns the number of values masked by pattern name.
+func (m *PIIMasker) Counts() map[string]int64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return maps.Clone(m.counts)
+}
+
+// anyMatch reports whether a pattern matches anywhere in p, so entries
+// without personal data are passed on untouched.
+func (m *PIIMasker) anyMatch(p []byte) bool {
+	for _, pat := range m.patterns {
+		if pat.Regexp.Match(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// maskEntry returns p with personal data masked and the number of
+// values masked.
+func (m *PIIMasker) maskEntry(p []byte) ([]byte, int) {
+	var total int
+	buf := bytes.NewBuffer(make([]byte, 0, len(p)))
+	buf.WriteByte('{')
+	err := eachField(p, func(key string, raw json.RawMessage) {
+		if !m.exempt[key] {
+			var n int
+			raw, n = m.maskJSON(raw)
+			total += n
+		}
+		appendField(buf, key, raw)
+	})
+	if err != nil {
+		s, n := m.maskString(string(p))
+		return []byte(s), n
+	}
+	return closeEntry(buf, p), total
+}
+
+// maskJSON masks the string values of the JSON value raw, leaving object
+// keys untouched.
+func (m *PIIMasker) maskJSON(raw []byte) ([]byte, int) {
+	var out []byte
+	var total, last int
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '"' {
+			continue
+		}
+		end := stringEnd(raw, i)
+		if !isKey(raw, end) {
+			var s string
+			if err := json.Unmarshal(raw[i:end], &s); err == nil {
+				if masked, n := m.maskString(s); n > 0 {
+					out = append(out, raw[last:i]...)
+					out = appendJSONString(out, masked)
+					last = end
+					total += n
+				}
+			}
+		}
+		i = end - 1
+	}
+	if total == 0 {
+		return raw, 0
+	}
+	return append(out, raw[last:]...), total
+}
+
+// maskString returns s with the matches of all patterns masked, and the
+// number of matches masked.
+func (m *PIIMasker) maskString(s string) (string, int) {
+	var total int
+	for _, pat := range m.patterns {
+		n := 0
+		s = pat.Regexp.ReplaceAllStringFunc(s, func(match string) string {
+			if pat.Valid != nil && !pat.Valid(match) {
+				return match
+			}
+			n++
+			return m.mask(pat.Name, match)
+		})
+		if n > 0 {
+			total += n
+			m.mu.Lock()
+			m.counts[pat.Name] += int64(n)
+			m.mu.Unlock()
+		}
+	}
+	m.masked.Add(int64(total))
+	return s, total
+}
+
+// appendJSONString appends s to dst as a JSON string, without escaping
+// HTML characters, like zerolog.
+func appendJSONString(dst []byte, s string) []byte {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return append(dst, bytes.TrimSuffix(buf.Bytes(), []byte("\n"))...)
+}
+
+// countDigits returns the number of ASCII digits in s.
+func countDigits(s string) int {
+	n := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] >= '0' && s[i] <= '9' {
+			n++
+		}
+	}
+	return n
+}
+
+// validIBAN checks the ISO 13616 mod-97 checksum of an IBAN.
+func validIBAN(s string) bool {
+	s = strings.ReplaceAll(s, " ", "")
+	if len(s) < 15 || len(s) > 34 {
+		return false
+	}
+	rem := 0
+	for _, c := range s[4:] + s[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			rem = (rem*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			rem = (rem*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return rem == 1
+}
+
+// validDNI checks the control letter of a Spanish DNI or NIE.
+func validDNI(s string) bool {
+	digits := strings.NewReplacer("X", "0", "Y", "1", "Z", "2").Replace(s[:1]) + s[1:8]
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return false
+	}
+	return "TRWAGMYFPDXBNJZSQVHLCKE"[n%23] == s[8]
+}
@@ -0,0 +1,163 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ANSI colors used by the pretty format.
+const (
+	colorRed      = 31
+	colorCyan     = 36
+	colorDarkGray = 90
+	colorBold     = 1
+)
+
+// prettyWriter renders JSON entries for local development: the message on
+// one line, followed by one indented line per field.
+//
+//	15:04:05 INF user signed up
+//	    user_id  42
+//	    plan     pro
+type prettyWriter struct {
+	out        io.Writer
+	timeFormat string
+	noColor    bool
+}
+
+// newPrettyWriter returns a writer rendering the "pretty" format.
+func newPrettyWriter(out io.Writer, timeFormat string, cfg ConsoleConfig) io.Writer {
+	if cfg.TimeOnly {
+		timeFormat = time.TimeOnly
+	}
+	return prettyWriter{
+		out:        out,
+		timeFormat: timeFormat,
+		noColor:    cfg.NoColor || os.Getenv("NO_COLOR") != "",
+	}
+}
+
+// Write implements io.Writer. Entries that are not JSON objects are
+// written unchanged.
+func (w prettyWriter) Write(p []byte) (int, error) {
+	fields, err := decodeOrdered(p)
+	if err != nil {
+		return w.out.Write(p)
+	}
+
+	var ts, level, msg, caller, errMsg string
+	rest := fields[:0]
+	for _, f := range fields {
+		switch f.Key {
+		case zerolog.TimestampFieldName:
+			ts = rawString(f.Value)
+		case zerolog.LevelFieldName:
+			level = rawString(f.Value)
+		case zerolog.MessageFieldName:
+			msg = rawString(f.Value)
+		case zerolog.CallerFieldName:
+			caller = rawString(f.Value)
+		case zerolog.ErrorFieldName:
+			errMsg = rawString(f.Value)
+		default:
+			rest = append(rest, f)
+		}
+	}
+
+	var buf bytes.Buffer
+	if ts != "" {
+		if t, err := time.Parse(zerolog.TimeFieldFormat, ts); err == nil {
+			ts = t.Format(w.timeFormat)
+		}
+		buf.WriteString(w.color(ts, colorDarkGray))
+		buf.WriteByte(' ')
+	}
+	buf.WriteString(w.level(level))
+	if msg != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(w.color(msg, colorBold))
+	}
+	if caller != "" {
+		buf.WriteByte(' ')
+		buf.WriteString(w.color(caller, colorDarkGray))
+	}
+	buf.WriteByte('\n')
+
+	width := 0
+	for _, f := range rest {
+		width = max(width, len(f.Key))
+	}
+	if errMsg != "" {
+		width = max(width, len(zerolog.ErrorFieldName))
+		fmt.Fprintf(&buf, "    %s  %s\n",
+			w.color(pad(zerolog.ErrorFieldName, width), colorRed),
+			w.color(errMsg, colorRed))
+	}
+	for _, f := range rest {
+		fmt.Fprintf(&buf, "    %s  %s\n",
+			w.color(pad(f.Key, width), colorCyan),
+			prettyValue(f.Value))
+	}
+
+	if _, err := w.out.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// level returns the colored three-letter level label.
+func (w prettyWriter) level(level string) string {
+	l, err := zerolog.ParseLevel(level)
+	label, ok := zerolog.FormattedLevels[l]
+	if err != nil || !ok {
+		return "???"
+	}
+	return w.color(label, zerolog.LevelColors[l])
+}
+
+// color wraps s in an ANSI color sequence unless colors are disabled.
+func (w prettyWriter) color(s string, c int) string {
+	if w.noColor || c == 0 {
+		return s
+	}
+	return fmt.Sprintf("\x1b[%dm%s\x1b[0m", c, s)
+}
+
+// rawString returns a JSON string's content, or the raw JSON otherwise.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+	return string(raw)
+}
+
+// prettyValue renders a field value: strings unquoted, objects and arrays
+// as indented JSON continuing the field's indentation.
+func prettyValue(raw json.RawMessage) string {
+	switch raw[0] {
+	case '"':
+		return rawString(raw)
+	case '{', '[':
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, raw, "    ", "  "); err == nil {
+			return buf.String()
+		}
+	}
+	return string(raw)
+}
+
+// pad right-pads s with spaces to width.
+func pad(s string, width int) string {
+	if len(s) >= width {
+		return s
+	}
+	return s + strings.Repeat(" ", width-len(s))
+}
@@ -0,0 +1,103 @@
+package zerowrap
+
+import (
+	"context"
+	"net/http"
+)
+
+// Headers carrying IDs between services.
+const (
+	HeaderRequestID     = "X-Request-ID"
+	HeaderCorrelationID = "X-Correlation-ID"
+)
+
+// requestIDKey and correlationIDKey are the context keys of propagated IDs.
+type (
+	requestIDKey     struct{}
+	correlationIDKey struct{}
+)
+
+// CtxWithRequestID returns a new context carrying the request ID, whose
+// logger includes the request_id field.
+func CtxWithRequestID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, requestIDKey{}, id)
+	return CtxWithField(ctx, FieldRequestID, id)
+}
+
+// CtxWithCorrelationID returns a new context carrying the correlation ID,
+// whose logger includes the correlation_id field.
+func CtxWithCorrelationID(ctx context.Context, id string) context.Context {
+	ctx = context.WithValue(ctx, correlationIDKey{}, id)
+	return CtxWithField(ctx, FieldCorrelationID, id)
+}
+
+// RequestIDFromCtx returns the request ID set with CtxWithRequestID, or an
+// empty string if there is none.
+func RequestIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// CorrelationIDFromCtx returns the correlation ID set with
+// CtxWithCorrelationID, or an empty string if there is none.
+func CorrelationIDFromCtx(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey{}).(string)
+	return id
+}
+
+// Extract returns ctx with the request and correlation IDs read through
+// get, for transports other than HTTP. A new request ID is generated if
+// absent; the correlation ID is only set if present.
+//
+//	// gRPC server interceptor
+//	md, _ := metadata.FromIncomingContext(ctx)
+//	ctx = zerowrap.Extract(ctx, func(k string) string {
+//	    if v := md.Get(k); len(v) > 0 {
+//	        return v[0]
+//	    }
+//	    return ""
+//	})
+func Extract(ctx context.Context, get func(key string) string) context.Context {
+	requestID := get(HeaderRequestID)
+	if requestID == "" {
+		requestID = NewID()
+	}
+	ctx = CtxWithRequestID(ctx, requestID)
+	if id := get(HeaderCorrelationID); id != "" {
+		ctx = CtxWithCorrelationID(ctx, id)
+	}
+	return ctx
+}
+
+// Inject writes the request and correlation IDs of ctx through set, for
+// transports other than HTTP. IDs absent from ctx are skipped.
+//
+//	// gRPC client interceptor
+//	zerowrap.Inject(ctx, func(k, v string) {
+//	    ctx = metadata.AppendToOutgoingContext(ctx, k, v)
+//	})
+func Inject(ctx context.Context, set func(key, value string)) {
+	if id := RequestIDFromCtx(ctx); id != "" {
+		set(HeaderRequestID, id)
+	}
+	if id := CorrelationIDFromCtx(ctx); id != "" {
+		set(HeaderCorrelationID, id)
+	}
+}
+
+// ExtractHTTP returns the request context with the request and
+// correlation IDs of the incoming request's headers (see Extract).
+// httplog.Middleware does this for every request.
+func ExtractHTTP(r *http.Request) context.Context {
+	return Extract(r.Context(), r.Header.Get)
+}
+
+// InjectHTTP sets the X-Request-ID and X-Correlation-ID headers of an
+// outbound request from ctx, so downstream services log the same IDs.
+// LoggingTransport does this when TransportConfig.PropagateIDs is set.
+//
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	zerowrap.InjectHTTP(req, ctx)
+func InjectHTTP(req *http.Request, ctx context.Context) {
+	Inject(ctx, req.Header.Set)
+}
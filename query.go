@@ -0,0 +1,190 @@
+package zerowrap
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// paramList matches a parenthesized list of placeholders, as left by
+// NormalizeQuery in IN lists and VALUES rows.
+var paramList = regexp.MustCompile(`\(\?(?:, ?\?)*\)`)
+
+// paramRows matches the repeated (?+) rows of a multi-row VALUES.
+var paramRows = regexp.MustCompile(`\(\?\+\)(?:, ?\(\?\+\))+`)
+
+// NormalizeQuery returns the fingerprint of a SQL statement: string and
+// numeric literals and bind parameters ($1, ?, :name, @name) replaced by
+// ?, lists and VALUES rows of them collapsed to (?+), comments removed
+// and whitespace collapsed. Statements differing only in their parameters normalize to
+// the same string, so slow-query analysis can group them:
+//
+//	zerowrap.NormalizeQuery("SELECT * FROM users\n  WHERE id = 42 AND name IN ('a', 'b')")
+//	// SELECT * FROM users WHERE id = ? AND name IN (?+)
+//
+// Quoted identifiers and the case of keywords are kept.
+func NormalizeQuery(query string) string {
+	var b strings.Builder
+	b.Grow(len(query))
+	space := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		var next byte
+		if i+1 < len(query) {
+			next = query[i+1]
+		}
+		switch {
+		case c == '-' && next == '-':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+			space = true
+			continue
+		case c == '/' && next == '*':
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += end + 3
+			}
+			space = true
+			continue
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			space = true
+			continue
+		}
+
+		if space && b.Len() > 0 {
+			b.WriteByte(' ')
+		}
+		space = false
+		switch {
+		case c == '\'':
+			i = quotedEnd(query, i, '\'')
+			b.WriteByte('?')
+		case c == '"' || c == '`':
+			end := quotedEnd(query, i, c)
+			b.WriteString(query[i:min(end+1, len(query))])
+			i = end
+		case c == '$' && isDigit(next):
+			i = skipWord(query, i+1)
+			b.WriteByte('?')
+		case c == '$' && (next == '$' || isWordByte(next)):
+			// dollar-quoted string: $$...$$ or $tag$...$tag$
+			tagEnd := strings.IndexByte(query[i+1:], '$')
+			if tagEnd < 0 || skipWord(query, i+1) != i+tagEnd {
+				b.WriteByte(c)
+				continue
+			}
+			tag := query[i : i+tagEnd+2]
+			end := strings.Index(query[i+len(tag):], tag)
+			if end < 0 {
+				i = len(query)
+			} else {
+				i += len(tag) + end + len(tag) - 1
+			}
+			b.WriteByte('?')
+		case c == ':' && next == ':':
+			b.WriteString("::")
+			i++
+		case (c == ':' || c == '@') && isWordByte(next) && !isDigit(next):
+			i = skipWord(query, i+1)
+			b.WriteByte('?')
+		case isDigit(c) && !endsWithWord(&b):
+			i = numberEnd(query, i)
+			b.WriteByte('?')
+		case c == '.' && isDigit(next) && !endsWithWord(&b):
+			i = numberEnd(query, i)
+			b.WriteByte('?')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	normalized := paramList.ReplaceAllString(b.String(), "(?+)")
+	return paramRows.ReplaceAllString(normalized, "(?+)")
+}
+
+// QueryHash returns a short hash (16 hex characters) of the normalized
+// query, to group statements by the query_hash field.
+func QueryHash(query string) string {
+	return hashQuery(NormalizeQuery(query))
+}
+
+// AddQuery adds the normalized query and its query_hash to e. The raw
+// statement is not logged, so literals it holds do not reach the logs.
+//
+//	zerowrap.AddQuery(log.Debug(), query).Msg("query")
+func AddQuery(e *zerolog.Event, query string) *zerolog.Event {
+	normalized := NormalizeQuery(query)
+	return e.Str(FieldQuery, normalized).Str(FieldQueryHash, hashQuery(normalized))
+}
+
+// hashQuery returns the query_hash of a normalized query.
+func hashQuery(normalized string) string {
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:8])
+}
+
+// quotedEnd returns the index of the quote closing the string opened at
+// i, where a doubled quote is an escaped one. It returns the last index
+// of s if the string is not closed.
+func quotedEnd(s string, i int, quote byte) int {
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if quote == '\'' {
+				j++
+			}
+		case quote:
+			if j+1 < len(s) && s[j+1] == quote {
+				j++
+				continue
+			}
+			return j
+		}
+	}
+	return len(s) - 1
+}
+
+// numberEnd returns the index of the last byte of the numeric literal
+// starting at i, including decimals, exponents and hex digits.
+func numberEnd(s string, i int) int {
+	j := i
+	for j+1 < len(s) {
+		c := s[j+1]
+		if isDigit(c) || c == '.' || c == 'x' || c == 'X' ||
+			c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F' ||
+			(c == '+' || c == '-') && (s[j] == 'e' || s[j] == 'E') {
+			j++
+			continue
+		}
+		break
+	}
+	return j
+}
+
+// skipWord returns the index of the last word byte from i.
+func skipWord(s string, i int) int {
+	for i < len(s) && isWordByte(s[i]) {
+		i++
+	}
+	return i - 1
+}
+
+// endsWithWord reports whether b ends with a word byte, so digits are
+// part of an identifier such as t1.
+func endsWithWord(b *strings.Builder) bool {
+	s := b.String()
+	return len(s) > 0 && isWordByte(s[len(s)-1])
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || isDigit(c) || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
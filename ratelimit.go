@@ -0,0 +1,79 @@
+package zerowrap
+
+import (
+	"sync"
+	"time"
+)
+
+// maxErrRates caps the fingerprints tracked by ErrRateLimited. When full,
+// expired fingerprints are evicted; errors with new fingerprints are
+// logged without limit until room is made.
+const maxErrRates = 1024
+
+// errRate is the state of one ErrRateLimited fingerprint.
+type errRate struct {
+	last       time.Time
+	suppressed int
+}
+
+var (
+	errRatesMu sync.Mutex
+	errRates   = make(map[string]*errRate)
+)
+
+// ErrRateLimited logs err at error level with msg the first time, then at
+// most once per interval for the same message and error text. Each entry
+// after suppressed ones carries their count (suppressed field), so retry
+// loops and health checks do not flood the logs.
+//
+//	for {
+//	    if err := ping(ctx); err != nil {
+//	        log.ErrRateLimited(err, "health check failed", time.Minute)
+//	    }
+//	    time.Sleep(time.Second)
+//	}
+func (l Logger) ErrRateLimited(err error, msg string, interval time.Duration) {
+	if err == nil {
+		return
+	}
+	suppressed, ok := allowErr(msg+"\x00"+err.Error(), interval, time.Now())
+	if !ok {
+		return
+	}
+	e := l.Error().Err(err)
+	if suppressed > 0 {
+		e = e.Int(FieldSuppressed, suppressed)
+	}
+	e.Msg(msg)
+}
+
+// allowErr reports whether an error with fingerprint key may be logged at
+// now, and how many were suppressed since the last one logged.
+func allowErr(key string, interval time.Duration, now time.Time) (suppressed int, ok bool) {
+	errRatesMu.Lock()
+	defer errRatesMu.Unlock()
+
+	r, found := errRates[key]
+	if !found {
+		if len(errRates) >= maxErrRates {
+			for k, s := range errRates {
+				if now.Sub(s.last) >= interval && s.suppressed == 0 {
+					delete(errRates, k)
+				}
+			}
+		}
+		if len(errRates) < maxErrRates {
+			errRates[key] = &errRate{last: now}
+		}
+		return 0, true
+	}
+
+	if now.Sub(r.last) < interval {
+		r.suppressed++
+		ReportSuppressed(1)
+		return 0, false
+	}
+	suppressed = r.suppressed
+	r.last, r.suppressed = now, 0
+	return suppressed, true
+}
@@ -0,0 +1,58 @@
+package zerowrap
+
+import (
+	"net/url"
+	"strings"
+)
+
+// Redacted is the placeholder written in place of sensitive values.
+const Redacted = "[REDACTED]"
+
+// DefaultRedactQueryKeys lists URL query parameters redacted by default.
+var DefaultRedactQueryKeys = []string{
+	"access_token",
+	"api_key",
+	"apikey",
+	"password",
+	"secret",
+	"token",
+}
+
+// RedactQuery returns rawQuery with the values of the given keys replaced by Redacted.
+// Key matching is case-insensitive and parameter order is preserved.
+// If keys is nil, DefaultRedactQueryKeys is used.
+func RedactQuery(rawQuery string, keys []string) string {
+	if rawQuery == "" {
+		return ""
+	}
+	if keys == nil {
+		keys = DefaultRedactQueryKeys
+	}
+
+	parts := strings.Split(rawQuery, "&")
+	redacted := 0
+	for i, part := range parts {
+		key, _, _ := strings.Cut(part, "=")
+		if k, err := url.QueryUnescape(key); err == nil {
+			key = k
+		}
+		if containsFold(keys, key) {
+			parts[i] = key + "=" + Redacted
+			redacted++
+		}
+	}
+	if redacted > 0 {
+		ReportRedacted(redacted)
+	}
+	return strings.Join(parts, "&")
+}
+
+// containsFold reports whether list contains s, ignoring case.
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package zerowrap
+
+import (
+	"context"
+	"time"
+)
+
+// Retries logs the attempts of one retried operation; see LogRetries.
+// It is not safe for concurrent use.
+type Retries struct {
+	log      Logger
+	op       string
+	start    time.Time
+	attempts int
+}
+
+// LogRetries starts logging the attempts of op with the logger from ctx,
+// for use in existing retry loops. Call Failed for each failed attempt
+// that will be retried and Done with the final outcome:
+//
+//	r := zerowrap.LogRetries(ctx, "fetch-invoice")
+//	for attempt := 1; ; attempt++ {
+//	    err = fetch(ctx)
+//	    if err == nil || attempt == maxAttempts {
+//	        break
+//	    }
+//	    delay := backoff(attempt)
+//	    r.Failed(err, delay)
+//	    time.Sleep(delay)
+//	}
+//	r.Done(err)
+func LogRetries(ctx context.Context, op string) *Retries {
+	return &Retries{log: FromCtx(ctx), op: op, start: time.Now()}
+}
+
+// Failed logs a failed attempt at debug level with its number, the
+// backoff delay before the next one and err.
+func (r *Retries) Failed(err error, backoff time.Duration) {
+	r.attempts++
+	e := r.log.Debug().
+		Str(FieldOperation, r.op).
+		Int(FieldAttempt, r.attempts).
+		Err(err)
+	durToEvent(e, FieldBackoff, backoff)
+	e.Msg("attempt failed, retrying")
+}
+
+// Done logs the final outcome with the number of retries and the total
+// duration: at info level if err is nil (only when the operation was
+// retried), at error level otherwise.
+func (r *Retries) Done(err error) {
+	r.done(err, r.attempts+1)
+}
+
+// done logs the final outcome after the given number of attempts.
+func (r *Retries) done(err error, attempts int) {
+	if err == nil && attempts == 1 {
+		return
+	}
+	e := r.log.Info()
+	msg := "operation succeeded after retries"
+	if err != nil {
+		e, msg = r.log.Error().Err(err), "operation failed after retries"
+	}
+	e = e.Str(FieldOperation, r.op).
+		Int(FieldAttempt, attempts).
+		Int(FieldRetries, attempts-1)
+	AddDuration(e, time.Since(r.start)).Msg(msg)
+}
+
+// Retry calls fn until it succeeds, up to maxAttempts times, waiting
+// backoff(attempt) after each failed attempt (attempt starts at 1), and
+// logs the attempts like LogRetries. It stops early when ctx is done and
+// returns the last error.
+//
+//	err := zerowrap.Retry(ctx, "publish-event", 5,
+//	    func(attempt int) time.Duration { return time.Duration(attempt) * 100 * time.Millisecond },
+//	    func(ctx context.Context) error { return publish(ctx, evt) },
+//	)
+func Retry(ctx context.Context, op string, maxAttempts int, backoff func(attempt int) time.Duration, fn func(ctx context.Context) error) error {
+	r := LogRetries(ctx, op)
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = fn(ctx); err == nil || attempt >= maxAttempts {
+			break
+		}
+		delay := backoff(attempt)
+		r.Failed(err, delay)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			r.done(err, attempt)
+			return err
+		case <-timer.C:
+		}
+	}
+	r.Done(err)
+	return err
+}
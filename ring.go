@@ -0,0 +1,234 @@
+package zerowrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// DefaultRingSize is the number of entries kept by a RingSink.
+const DefaultRingSize = 1000
+
+// ringStreamBuffer is the number of entries queued per subscriber.
+const ringStreamBuffer = 256
+
+// ringEntry is an entry kept by a RingSink.
+type ringEntry struct {
+	level zerolog.Level
+	data  []byte
+}
+
+// ringSub is a subscriber streaming entries from a RingSink.
+type ringSub struct {
+	minLevel zerolog.Level
+	ch       chan []byte
+}
+
+// RingSink is a zerolog.LevelWriter keeping the last entries in memory,
+// so applications can show recent logs on a debug page without shell
+// access to the host. Entries are kept as written, so pair it with the
+// "json" format to serve them with Handler.
+type RingSink struct {
+	mu      sync.Mutex
+	entries []ringEntry
+	next    int
+	full    bool
+	subs    map[*ringSub]struct{}
+}
+
+// NewRingSink creates a sink keeping the last n entries.
+// n defaults to DefaultRingSize if 0 or less.
+//
+//	ring := zerowrap.NewRingSink(500)
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: zerolog.MultiLevelWriter(os.Stdout, ring),
+//	})
+//	mux.Handle("GET /debug/logs", ring.Handler())
+func NewRingSink(n int) *RingSink {
+	if n <= 0 {
+		n = DefaultRingSize
+	}
+	return &RingSink{
+		entries: make([]ringEntry, n),
+		subs:    map[*ringSub]struct{}{},
+	}
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (s *RingSink) Write(p []byte) (int, error) {
+	return s.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. The entry replaces the
+// oldest one once the sink is full, and is sent to the subscribers
+// whose minimum level it meets. Subscribers that do not keep up miss
+// entries rather than blocking the logger.
+func (s *RingSink) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	data := bytes.TrimRight(bytes.Clone(p), "\n")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[s.next] = ringEntry{level: level, data: data}
+	s.next = (s.next + 1) % len(s.entries)
+	if s.next == 0 {
+		s.full = true
+	}
+	for sub := range s.subs {
+		if level < sub.minLevel {
+			continue
+		}
+		select {
+		case sub.ch <- data:
+		default:
+		}
+	}
+	return len(p), nil
+}
+
+// Snapshot returns the kept entries at minLevel or above, oldest first,
+// without their trailing newline. Pass zerolog.TraceLevel for all.
+func (s *RingSink) Snapshot(minLevel zerolog.Level) [][]byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.snapshot(minLevel)
+}
+
+// snapshot is Snapshot with s.mu held.
+func (s *RingSink) snapshot(minLevel zerolog.Level) [][]byte {
+	var out [][]byte
+	add := func(entries []ringEntry) {
+		for _, e := range entries {
+			if e.data != nil && e.level >= minLevel {
+				out = append(out, e.data)
+			}
+		}
+	}
+	if s.full {
+		add(s.entries[s.next:])
+	}
+	add(s.entries[:s.next])
+	return out
+}
+
+// Subscribe returns the kept entries at minLevel or above, like Snapshot,
+// and a channel receiving the entries written afterwards, until ctx is
+// done and the channel is closed. No entry is missed or repeated between
+// the snapshot and the stream, unless the subscriber falls behind.
+func (s *RingSink) Subscribe(ctx context.Context, minLevel zerolog.Level) ([][]byte, <-chan []byte) {
+	sub := &ringSub{minLevel: minLevel, ch: make(chan []byte, ringStreamBuffer)}
+
+	s.mu.Lock()
+	snapshot := s.snapshot(minLevel)
+	s.subs[sub] = struct{}{}
+	s.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		s.mu.Lock()
+		delete(s.subs, sub)
+		s.mu.Unlock()
+		close(sub.ch)
+	}()
+	return snapshot, sub.ch
+}
+
+// Handler returns an http.Handler serving the kept entries. It responds
+// with a JSON array of the entries, or, when the request accepts
+// text/event-stream, streams them as server-sent events (one entry per
+// "data:" line) followed by new entries as they are written, until the
+// client disconnects.
+//
+// Query parameters:
+//
+//	level  minimum level, e.g. ?level=warn (default: all)
+//	limit  maximum number of entries returned, keeping the newest
+//
+//	mux.Handle("GET /debug/logs", ring.Handler())
+//
+// The entries are not redacted beyond what the logger did; do not expose
+// the handler publicly.
+func (s *RingSink) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		minLevel := zerolog.TraceLevel
+		if l := q.Get("level"); l != "" {
+			minLevel = parseLevel(l)
+		}
+		limit, _ := strconv.Atoi(q.Get("limit"))
+
+		if strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+			s.serveEvents(w, r, minLevel, limit)
+			return
+		}
+
+		entries := lastEntries(s.Snapshot(minLevel), limit)
+		w.Header().Set("Content-Type", "application/json")
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			writeJSONEntry(&buf, e)
+		}
+		buf.WriteString("]\n")
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+// serveEvents streams entries as server-sent events.
+func (s *RingSink) serveEvents(w http.ResponseWriter, r *http.Request, minLevel zerolog.Level, limit int) {
+	rc := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+
+	snapshot, stream := s.Subscribe(r.Context(), minLevel)
+	var buf bytes.Buffer
+	send := func(e []byte) error {
+		buf.Reset()
+		buf.WriteString("data: ")
+		writeJSONEntry(&buf, e)
+		buf.WriteString("\n\n")
+		_, err := w.Write(buf.Bytes())
+		return err
+	}
+	for _, e := range lastEntries(snapshot, limit) {
+		if send(e) != nil {
+			return
+		}
+	}
+	if rc.Flush() != nil {
+		return
+	}
+	for e := range stream {
+		if send(e) != nil || rc.Flush() != nil {
+			return
+		}
+	}
+}
+
+// lastEntries returns the last limit entries, or all if limit is 0 or less.
+func lastEntries(entries [][]byte, limit int) [][]byte {
+	if limit > 0 && len(entries) > limit {
+		return entries[len(entries)-limit:]
+	}
+	return entries
+}
+
+// writeJSONEntry writes e to buf, as a JSON string if it is not JSON, so
+// entries written in other formats do not break the response.
+func writeJSONEntry(buf *bytes.Buffer, e []byte) {
+	if json.Valid(e) {
+		buf.Write(e)
+		return
+	}
+	b, _ := json.Marshal(string(e))
+	buf.Write(b)
+}
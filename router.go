@@ -0,0 +1,186 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+
+	"github.com/rs/zerolog"
+)
+
+// Matcher selects the entries delivered to a route. All set conditions
+// must match; an empty Matcher matches every entry.
+type Matcher struct {
+	// MinLevel is the minimum entry level, e.g. "error".
+	MinLevel string
+
+	// Component matches the component field.
+	Component string
+
+	// Field matches entries that have this field. Unless Value is set,
+	// a field whose value is false or null does not match.
+	Field string
+
+	// Value is the value Field must have, compared to its string form.
+	Value string
+
+	// Func is a custom condition on the entry level and decoded fields.
+	Func func(level zerolog.Level, fields map[string]any) bool
+}
+
+// route is a matcher with its destination.
+type route struct {
+	Matcher
+	minLevel zerolog.Level
+	out      io.Writer
+}
+
+// Router is a zerolog.LevelWriter that delivers each entry to every route
+// whose matcher matches it, and to the default writer when none does, so
+// a single logger can feed different destinations with different slices
+// of events.
+type Router struct {
+	routes []route
+	def    io.Writer
+}
+
+// NewRouter creates an empty router. Entries that match no route are
+// discarded until Default is set.
+//
+//	router := zerowrap.NewRouter().
+//	    Route(zerowrap.Matcher{MinLevel: "error"}, alerts).
+//	    Route(zerowrap.Matcher{Field: "audit"}, auditFile).
+//	    Default(os.Stdout)
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: router})
+func NewRouter() *Router {
+	return &Router{}
+}
+
+// Route adds a route delivering the entries matched by m to out.
+func (r *Router) Route(m Matcher, out io.Writer) *Router {
+	minLevel := zerolog.TraceLevel
+	if m.MinLevel != "" {
+		minLevel = parseLevel(m.MinLevel)
+	}
+	r.routes = append(r.routes, route{Matcher: m, minLevel: minLevel, out: out})
+	return r
+}
+
+// Default sets the writer receiving entries that match no route.
+func (r *Router) Default(out io.Writer) *Router {
+	r.def = out
+	return r
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (r *Router) Write(p []byte) (int, error) {
+	return r.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Errors from the destinations
+// are joined.
+func (r *Router) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var (
+		fields  map[string]any
+		decoded bool
+		matched bool
+		errs    []error
+	)
+	for _, rt := range r.routes {
+		if rt.needsFields() && !decoded {
+			fields = decodeFields(p)
+			decoded = true
+		}
+		if !rt.match(level, fields) {
+			continue
+		}
+		matched = true
+		if _, err := writeLevel(rt.out, level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if !matched && r.def != nil {
+		if _, err := writeLevel(r.def, level, p); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// Close closes the destinations implementing io.Closer, except os.Stdout
+// and os.Stderr.
+func (r *Router) Close() error {
+	var errs []error
+	seen := map[io.Writer]bool{}
+	outs := make([]io.Writer, 0, len(r.routes)+1)
+	for _, rt := range r.routes {
+		outs = append(outs, rt.out)
+	}
+	outs = append(outs, r.def)
+	for _, out := range outs {
+		if out == nil || out == os.Stdout || out == os.Stderr {
+			continue
+		}
+		if reflect.TypeOf(out).Comparable() {
+			if seen[out] {
+				continue
+			}
+			seen[out] = true
+		}
+		if c, ok := out.(io.Closer); ok {
+			errs = append(errs, c.Close())
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// needsFields reports whether matching requires the decoded entry.
+func (rt route) needsFields() bool {
+	return rt.Component != "" || rt.Field != "" || rt.Func != nil
+}
+
+// match reports whether the route accepts the entry.
+func (rt route) match(level zerolog.Level, fields map[string]any) bool {
+	if rt.MinLevel != "" && (level < rt.minLevel || level == zerolog.NoLevel) {
+		return false
+	}
+	if rt.Component != "" && fields[FieldComponent] != rt.Component {
+		return false
+	}
+	if rt.Field != "" {
+		v, ok := fields[rt.Field]
+		if !ok {
+			return false
+		}
+		if rt.Value != "" {
+			if fmt.Sprint(v) != rt.Value {
+				return false
+			}
+		} else if v == nil || v == false {
+			return false
+		}
+	}
+	if rt.Func != nil && !rt.Func(level, fields) {
+		return false
+	}
+	return true
+}
+
+// decodeFields decodes a JSON entry, returning nil if it is not an object.
+func decodeFields(p []byte) map[string]any {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	var fields map[string]any
+	if err := dec.Decode(&fields); err != nil {
+		return nil
+	}
+	return fields
+}
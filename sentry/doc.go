@@ -0,0 +1,54 @@
+// Package sentry provides a zerolog hook that forwards error events to
+// Sentry.
+//
+// This is an optional sub-package that adds the sentry-go dependency.
+// Only import it if you report errors to Sentry.
+//
+// # Usage
+//
+//	import (
+//	    sentrygo "github.com/getsentry/sentry-go"
+//
+//	    "github.com/bnema/zerowrap"
+//	    "github.com/bnema/zerowrap/sentry"
+//	)
+//
+//	sentrygo.Init(sentrygo.ClientOptions{Dsn: dsn})
+//	defer sentrygo.Flush(2 * time.Second)
+//
+//	log := zerowrap.New(cfg).Hook(sentry.NewHook(sentry.Options{}))
+//	ctx := zerowrap.WithCtx(context.Background(), log)
+//
+//	// Captured in Sentry with the error, stack trace and fields
+//	zerowrap.FromCtx(ctx).WrapErr(err, "charge failed")
+//
+// # Event Mapping
+//
+// Error, fatal and panic events are forwarded by default (see
+// Options.Levels). Each Sentry event carries:
+//
+//	message        the log message
+//	exception      the error field, with the stack trace at the log call
+//	user           user_id and client_ip
+//	request        method and path
+//	tags           request_id, trace_id, span_id, correlation_id, route,
+//	               component, service, env, version
+//	extra          all other fields
+//
+// # Hubs
+//
+// Events are captured on the hub bound to the event context when there is
+// one (e.g. by the sentryhttp middleware), so per-request scope data is
+// kept. Bind the context to the logger with With().Ctx(ctx) or log via
+// zerolog's Ctx(ctx) on the event. Otherwise Options.Hub or the current
+// hub is used.
+//
+// # Sampling and Grouping
+//
+//	hook := sentry.NewHook(sentry.Options{
+//	    SampleRate: 0.25, // forward a quarter of error events
+//	    Fingerprint: func(_ zerolog.Level, msg string, fields map[string]any) []string {
+//	        return []string{msg, fmt.Sprint(fields["route"])}
+//	    },
+//	})
+package sentry
@@ -0,0 +1,202 @@
+package sentry
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/bnema/zerowrap/internal/eventbuf"
+	sentrygo "github.com/getsentry/sentry-go"
+	"github.com/rs/zerolog"
+)
+
+// DefaultLevels are the levels forwarded to Sentry when Options.Levels is nil.
+var DefaultLevels = []zerolog.Level{
+	zerolog.ErrorLevel,
+	zerolog.FatalLevel,
+	zerolog.PanicLevel,
+}
+
+// Options configures a Hook.
+type Options struct {
+	// Hub is the Sentry hub events are captured on when the event context
+	// carries none (see sentrygo.SetHubOnContext).
+	// Defaults to sentrygo.CurrentHub() if nil.
+	Hub *sentrygo.Hub
+
+	// Levels lists the zerolog levels forwarded to Sentry.
+	// Defaults to DefaultLevels if nil.
+	Levels []zerolog.Level
+
+	// SampleRate is the fraction of events forwarded, between 0 and 1.
+	// Defaults to 1 (all events) if 0.
+	SampleRate float64
+
+	// Fingerprint returns the Sentry fingerprint used to group an event.
+	// Defaults to Sentry's own grouping if nil.
+	Fingerprint func(level zerolog.Level, msg string, fields map[string]any) []string
+
+	// FlushTimeout bounds how long fatal and panic events wait to be sent
+	// before the process exits.
+	// Defaults to 2 seconds if 0.
+	FlushTimeout time.Duration
+}
+
+// Hook is a zerolog.Hook that captures events on a Sentry hub.
+type Hook struct {
+	hub          *sentrygo.Hub
+	levels       map[zerolog.Level]bool
+	sampleRate   float64
+	fingerprint  func(level zerolog.Level, msg string, fields map[string]any) []string
+	flushTimeout time.Duration
+}
+
+// NewHook creates a hook that forwards events to Sentry.
+// The Sentry client must be initialized with sentrygo.Init or bound to
+// opts.Hub beforehand.
+//
+//	hook := sentry.NewHook(sentry.Options{
+//	    SampleRate: 0.5,
+//	    Fingerprint: func(_ zerolog.Level, msg string, _ map[string]any) []string {
+//	        return []string{msg}
+//	    },
+//	})
+//	log := zerowrap.New(cfg).Hook(hook)
+func NewHook(opts Options) *Hook {
+	levels := opts.Levels
+	if levels == nil {
+		levels = DefaultLevels
+	}
+	set := make(map[zerolog.Level]bool, len(levels))
+	for _, l := range levels {
+		set[l] = true
+	}
+
+	sampleRate := opts.SampleRate
+	if sampleRate <= 0 {
+		sampleRate = 1
+	}
+	flushTimeout := opts.FlushTimeout
+	if flushTimeout == 0 {
+		flushTimeout = 2 * time.Second
+	}
+
+	return &Hook{
+		hub:          opts.Hub,
+		levels:       set,
+		sampleRate:   sampleRate,
+		fingerprint:  opts.Fingerprint,
+		flushTimeout: flushTimeout,
+	}
+}
+
+// Run implements zerolog.Hook interface.
+// It captures the event on the hub from the event context, falling back
+// to Options.Hub and then the current hub. Fatal and panic events are
+// flushed before returning, since the process is about to exit.
+func (h *Hook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if !h.levels[level] {
+		return
+	}
+	if h.sampleRate < 1 && rand.Float64() >= h.sampleRate {
+		return
+	}
+
+	hub := h.hubFor(e.GetCtx())
+	if hub == nil || hub.Client() == nil {
+		return
+	}
+
+	fields := eventbuf.Fields(e)
+	event := newEvent(level, msg, fields)
+	if h.fingerprint != nil {
+		event.Fingerprint = h.fingerprint(level, msg, fields)
+	}
+	hub.CaptureEvent(event)
+
+	if level == zerolog.FatalLevel || level == zerolog.PanicLevel {
+		hub.Flush(h.flushTimeout)
+	}
+}
+
+// hubFor returns the hub bound to ctx, or the configured fallback.
+func (h *Hook) hubFor(ctx context.Context) *sentrygo.Hub {
+	if ctx != nil {
+		if hub := sentrygo.GetHubFromContext(ctx); hub != nil {
+			return hub
+		}
+	}
+	if h.hub != nil {
+		return h.hub
+	}
+	return sentrygo.CurrentHub()
+}
+
+// newEvent builds a Sentry event from a zerolog event. User and request
+// fields map to the event's user and request, identifiers to tags, the
+// error field to an exception with the current stack trace, and all other
+// fields to extras.
+func newEvent(level zerolog.Level, msg string, fields map[string]any) *sentrygo.Event {
+	event := sentrygo.NewEvent()
+	event.Level = sentryLevel(level)
+	event.Message = msg
+	event.Logger = "zerowrap"
+	event.Timestamp = time.Now()
+
+	var errMsg string
+	var method, path string
+	for key, val := range fields {
+		switch key {
+		case zerolog.LevelFieldName, zerolog.TimestampFieldName:
+		case zerolog.ErrorFieldName:
+			errMsg = fmt.Sprint(val)
+		case zerowrap.FieldUserID:
+			event.User.ID = fmt.Sprint(val)
+		case zerowrap.FieldClientIP:
+			event.User.IPAddress = fmt.Sprint(val)
+		case zerowrap.FieldMethod:
+			method = fmt.Sprint(val)
+		case zerowrap.FieldPath:
+			path = fmt.Sprint(val)
+		case zerowrap.FieldRequestID, zerowrap.FieldTraceID, zerowrap.FieldSpanID,
+			zerowrap.FieldCorrelationID, zerowrap.FieldRoute, zerowrap.FieldComponent,
+			zerowrap.FieldService, zerowrap.FieldEnv, zerowrap.FieldVersion:
+			event.Tags[key] = fmt.Sprint(val)
+		default:
+			event.Extra[key] = val
+		}
+	}
+	if method != "" || path != "" {
+		event.Request = &sentrygo.Request{Method: method, URL: path}
+	}
+	if errMsg != "" {
+		// The message is used as the exception type, so Sentry titles
+		// the issue "<message>: <error>".
+		event.Exception = []sentrygo.Exception{{
+			Type:       msg,
+			Value:      errMsg,
+			Stacktrace: sentrygo.NewStacktrace(),
+		}}
+	}
+	return event
+}
+
+// sentryLevel converts a zerolog level to a Sentry level.
+func sentryLevel(level zerolog.Level) sentrygo.Level {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return sentrygo.LevelDebug
+	case zerolog.InfoLevel:
+		return sentrygo.LevelInfo
+	case zerolog.WarnLevel:
+		return sentrygo.LevelWarning
+	case zerolog.ErrorLevel:
+		return sentrygo.LevelError
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return sentrygo.LevelFatal
+	default:
+		return sentrygo.LevelError
+	}
+}
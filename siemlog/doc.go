@@ -0,0 +1,41 @@
+// Package siemlog provides Common Event Format (CEF) and Log Event
+// Extended Format (LEEF) encoders for zerowrap, to ship security-relevant
+// logs such as authentication failures and audit events directly to
+// SIEMs like ArcSight, QRadar or Sentinel.
+//
+// Both implement zerowrap.Encoder: register them as formats, or use them
+// on a single sink with zerowrap.NewEncoderWriter while other outputs
+// keep their format.
+//
+// # Usage
+//
+//	func init() {
+//	    zerowrap.RegisterFormat("cef", siemlog.CEF{Vendor: "Acme", Product: "Shop", Version: "1.4"})
+//	}
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "cef", Output: syslogConn})
+//
+//	log.Warn().
+//	    Str(zerowrap.FieldEvent, "login_failed").
+//	    Str(zerowrap.FieldClientIP, ip).
+//	    Str(zerowrap.FieldUserID, user).
+//	    Msg("invalid password")
+//	// CEF:0|Acme|Shop|1.4|login_failed|invalid password|6|rt=... event=login_failed src=10.0.0.7 suser=42
+//
+// Only audit events to the SIEM, the rest to stdout as JSON:
+//
+//	siem := zerowrap.NewEncoderWriter(syslogConn, siemlog.LEEF{Vendor: "Acme", Product: "Shop"})
+//	router := zerowrap.NewRouter().
+//	    Route(zerowrap.Matcher{Field: "audit"}, siem).
+//	    Default(os.Stdout)
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: router})
+//
+// # Mapping
+//
+// The entry level sets the 0-10 severity (debug 1, info 3, warn 6,
+// error 8, fatal and panic 10), the message is the CEF name or LEEF msg
+// attribute, and the event field (or action) is the signature or event
+// ID. zerowrap fields are renamed to standard keys (DefaultCEFKeys,
+// DefaultLEEFKeys), e.g. client_ip to src and user_id to suser or
+// usrName; other fields keep their names.
+package siemlog
@@ -0,0 +1,101 @@
+package siemlog
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/bnema/zerowrap"
+)
+
+// DefaultLEEFKeys maps zerowrap fields to LEEF attribute keys.
+var DefaultLEEFKeys = map[string]string{
+	zerowrap.FieldClientIP:  "src",
+	zerowrap.FieldUserID:    "usrName",
+	zerowrap.FieldComponent: "cat",
+	zerowrap.FieldPath:      "url",
+}
+
+// leefTimeFormat is the devTime layout, declared in devTimeFormat with
+// its Java equivalent.
+const (
+	leefTimeFormat     = "2006-01-02T15:04:05.000-0700"
+	leefJavaTimeFormat = "yyyy-MM-dd'T'HH:mm:ss.SSSZ"
+)
+
+// LEEF encodes entries in IBM QRadar Log Event Extended Format (LEEF:1.0),
+// with tab-separated attributes:
+//
+//	LEEF:1.0|Acme|Shop|1.4|login_failed|devTime=...	sev=6	msg=invalid password	src=10.0.0.7
+//
+// The level is the sev attribute (see Severity) and the message the msg
+// attribute. Fields in Keys are renamed to their LEEF attribute keys;
+// other fields are kept with their own names.
+type LEEF struct {
+	// Vendor, Product and Version identify the device in the header.
+	// Vendor and Product default to "zerowrap" if empty.
+	Vendor  string
+	Product string
+	Version string
+
+	// EventIDFields lists the fields whose first set value is the Event
+	// ID. The message is used if none is set.
+	// Defaults to zerowrap.FieldEvent and zerowrap.FieldAction if nil.
+	EventIDFields []string
+
+	// Keys maps field names to LEEF attribute keys.
+	// Defaults to DefaultLEEFKeys if nil.
+	Keys map[string]string
+}
+
+// leefValueEscaper removes the attribute delimiter and line breaks from
+// attribute values.
+var leefValueEscaper = strings.NewReplacer("\t", " ", "\n", `\n`, "\r", `\r`)
+
+// Encode implements zerowrap.Encoder.
+func (l LEEF) Encode(dst []byte, e *zerowrap.Entry) ([]byte, error) {
+	eventIDFields := l.EventIDFields
+	if eventIDFields == nil {
+		eventIDFields = []string{zerowrap.FieldEvent, zerowrap.FieldAction}
+	}
+	keys := l.Keys
+	if keys == nil {
+		keys = DefaultLEEFKeys
+	}
+
+	dst = append(dst, "LEEF:1.0|"...)
+	dst = append(dst, headerEscaper.Replace(orDefault(l.Vendor, "zerowrap"))...)
+	dst = append(dst, '|')
+	dst = append(dst, headerEscaper.Replace(orDefault(l.Product, "zerowrap"))...)
+	dst = append(dst, '|')
+	dst = append(dst, headerEscaper.Replace(l.Version)...)
+	dst = append(dst, '|')
+	dst = append(dst, headerEscaper.Replace(eventID(e, eventIDFields))...)
+	dst = append(dst, '|')
+
+	sep := false
+	add := func(key, value string) {
+		if sep {
+			dst = append(dst, '\t')
+		}
+		sep = true
+		dst = append(dst, key...)
+		dst = append(dst, '=')
+		dst = append(dst, leefValueEscaper.Replace(value)...)
+	}
+	if !e.Time.IsZero() {
+		add("devTime", e.Time.Format(leefTimeFormat))
+		add("devTimeFormat", leefJavaTimeFormat)
+	}
+	add("sev", strconv.Itoa(Severity(e.Level)))
+	if e.Message != "" {
+		add("msg", e.Message)
+	}
+	for _, f := range e.Fields {
+		key, ok := keys[f.Key]
+		if !ok {
+			key = attrKey(f.Key)
+		}
+		add(key, f.String())
+	}
+	return append(dst, '\n'), nil
+}
@@ -0,0 +1,54 @@
+package siemlog
+
+import (
+	"strings"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Severity returns the 0-10 CEF and LEEF severity of a level.
+func Severity(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 1
+	case zerolog.InfoLevel:
+		return 3
+	case zerolog.WarnLevel:
+		return 6
+	case zerolog.ErrorLevel:
+		return 8
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return 10
+	}
+	return 0
+}
+
+// eventID returns the value of the first of fields set in e, or the
+// message if none is.
+func eventID(e *zerowrap.Entry, fields []string) string {
+	for _, key := range fields {
+		if f, ok := e.Field(key); ok {
+			if s := f.String(); s != "" {
+				return s
+			}
+		}
+	}
+	return e.Message
+}
+
+// headerEscaper escapes header values, where pipes separate the fields.
+var headerEscaper = strings.NewReplacer(`\`, `\\`, `|`, `\|`, "\t", " ", "\n", " ", "\r", " ")
+
+// attrKey returns key usable as an attribute key: characters other than
+// letters, digits, '_', '.' and '-' are replaced by '_'.
+func attrKey(key string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9',
+			r == '_', r == '.', r == '-':
+			return r
+		}
+		return '_'
+	}, key)
+}
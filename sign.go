@@ -0,0 +1,273 @@
+package zerowrap
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// SignConfig holds configuration for signed (tamper-evident) output.
+type SignConfig struct {
+	// Key signs each entry with HMAC-SHA256. Without a key, entries are
+	// hash-chained with SHA-256, which detects edits only when the last
+	// hash is kept out of reach of whoever can edit the file.
+	Key []byte
+
+	// Chain includes the signature of the previous entry in each
+	// signature, so deleted, inserted or reordered lines are detected
+	// too. Always on without a Key.
+	Chain bool
+
+	// Previous is the signature the chain continues from, e.g. the last
+	// one of the file being appended to (see LastSignature). Empty for a
+	// new chain.
+	Previous string
+}
+
+// SignedWriter is a zerolog.LevelWriter that appends a signature field
+// (FieldSignature) to each JSON entry, so tampering with lines of an
+// audit log is detectable with VerifySigned. The signature covers the
+// entry as written without the signature field.
+//
+// Entries must be JSON objects ("json" format); other entries are
+// refused with ErrWrite.
+type SignedWriter struct {
+	out   io.Writer
+	cfg   SignConfig
+	mu    sync.Mutex
+	prev  string // chained into the next signature
+	last  string
+	state hash.Hash
+}
+
+// NewSignedWriter wraps out to sign each entry.
+//
+//	audit, _ := os.OpenFile("/var/log/app/audit.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+//	prev, _ := zerowrap.LastSignature("/var/log/app/audit.log")
+//	signed := zerowrap.NewSignedWriter(audit, zerowrap.SignConfig{
+//	    Key:      auditKey,
+//	    Chain:    true,
+//	    Previous: prev,
+//	})
+//
+//	auditLog := zerowrap.New(zerowrap.Config{Format: "json", Output: signed})
+func NewSignedWriter(out io.Writer, cfg SignConfig) *SignedWriter {
+	if len(cfg.Key) == 0 {
+		cfg.Chain = true
+	}
+	return &SignedWriter{out: out, cfg: cfg, prev: cfg.Previous, last: cfg.Previous, state: cfg.hash()}
+}
+
+// Write implements io.Writer.
+func (w *SignedWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter. Entries are signed and
+// written in order under a lock, so the chain matches the file.
+func (w *SignedWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	entry := bytes.TrimRight(p, "\r\n")
+	if len(entry) < 2 || entry[0] != '{' || entry[len(entry)-1] != '}' {
+		return 0, fmt.Errorf("%w: signed writer: entry is not a JSON object", ErrWrite)
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	sig := w.cfg.sign(w.state, w.prev, entry)
+	out := make([]byte, 0, len(entry)+len(FieldSignature)+len(sig)+8)
+	out = append(out, entry[:len(entry)-1]...)
+	if len(bytes.TrimSpace(entry[1:len(entry)-1])) > 0 {
+		out = append(out, ',')
+	}
+	out = append(out, '"')
+	out = append(out, FieldSignature...)
+	out = append(out, `":"`...)
+	out = append(out, sig...)
+	out = append(out, "\"}\n"...)
+	if _, err := writeLevel(w.out, level, out); err != nil {
+		return 0, err
+	}
+	w.last = sig
+	if w.cfg.Chain {
+		w.prev = sig
+	}
+	return len(p), nil
+}
+
+// Last returns the signature of the last entry written, to be kept
+// elsewhere (e.g. in a database or an external log) so truncation of the
+// file can be detected too.
+func (w *SignedWriter) Last() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.last
+}
+
+// Close closes the underlying writer if it implements io.Closer, except
+// os.Stdout and os.Stderr.
+func (w *SignedWriter) Close() error {
+	if w.out == os.Stdout || w.out == os.Stderr {
+		return nil
+	}
+	if c, ok := w.out.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// TamperError is returned by VerifySigned for the first line whose
+// signature does not match.
+type TamperError struct {
+	// Line is the 1-based line number.
+	Line int
+
+	// Reason tells what is wrong with the line.
+	Reason string
+}
+
+// Error implements error.
+func (e *TamperError) Error() string {
+	return fmt.Sprintf("zerowrap: signed log tampered at line %d: %s", e.Line, e.Reason)
+}
+
+// VerifySigned checks the signatures of the entries written by a
+// SignedWriter with cfg, and returns the last signature. The first line
+// that does not match is returned as a *TamperError; with Chain, lines
+// deleted, inserted or reordered make the following line fail. Empty
+// lines are skipped.
+//
+// To check that the file was not truncated, compare the returned
+// signature with one kept elsewhere (see SignedWriter.Last).
+//
+//	f, _ := os.Open("/var/log/app/audit.log")
+//	last, err := zerowrap.VerifySigned(f, zerowrap.SignConfig{Key: auditKey, Chain: true})
+//	var tampered *zerowrap.TamperError
+//	if errors.As(err, &tampered) {
+//	    alert(tampered.Line)
+//	}
+func VerifySigned(r io.Reader, cfg SignConfig) (string, error) {
+	if len(cfg.Key) == 0 {
+		cfg.Chain = true
+	}
+	state := cfg.hash()
+	prev, last := cfg.Previous, cfg.Previous
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), 64<<20)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := bytes.TrimRight(sc.Bytes(), "\r")
+		if len(bytes.TrimSpace(raw)) == 0 {
+			continue
+		}
+		entry, sig, ok := splitSignature(raw)
+		if !ok {
+			return last, &TamperError{Line: line, Reason: "missing signature"}
+		}
+		want := cfg.sign(state, prev, entry)
+		if !hmac.Equal([]byte(sig), []byte(want)) {
+			return last, &TamperError{Line: line, Reason: "signature mismatch"}
+		}
+		last = sig
+		if cfg.Chain {
+			prev = sig
+		}
+	}
+	return last, sc.Err()
+}
+
+// LastSignature returns the signature of the last entry of the file at
+// path, to continue its chain with SignConfig.Previous. It returns an
+// empty string if the file does not exist or is empty.
+func LastSignature(path string) (string, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := info.Size()
+	for chunk := int64(64 << 10); ; chunk *= 2 {
+		off := max(size-chunk, 0)
+		buf := make([]byte, size-off)
+		if _, err := f.ReadAt(buf, off); err != nil && err != io.EOF {
+			return "", err
+		}
+		buf = bytes.TrimRight(buf, "\r\n")
+		i := bytes.LastIndexByte(buf, '\n')
+		if i < 0 && off > 0 {
+			continue // the last line starts before the chunk
+		}
+		if len(buf) == 0 {
+			return "", nil
+		}
+		_, sig, ok := splitSignature(buf[i+1:])
+		if !ok {
+			return "", errors.New("zerowrap: last entry has no signature")
+		}
+		return sig, nil
+	}
+}
+
+// hash returns the hash used to sign entries with cfg.
+func (cfg SignConfig) hash() hash.Hash {
+	if len(cfg.Key) > 0 {
+		return hmac.New(sha256.New, cfg.Key)
+	}
+	return sha256.New()
+}
+
+// sign returns the hex signature of entry, chained to prev.
+func (cfg SignConfig) sign(h hash.Hash, prev string, entry []byte) string {
+	h.Reset()
+	if cfg.Chain {
+		h.Write([]byte(prev))
+		h.Write([]byte{'\n'})
+	}
+	h.Write(entry)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// splitSignature splits a signed line into the entry as it was signed
+// and its signature.
+func splitSignature(line []byte) (entry []byte, sig string, ok bool) {
+	key := []byte(`"` + FieldSignature + `":"`)
+	i := bytes.LastIndex(line, key)
+	if i < 1 || !bytes.HasSuffix(line, []byte(`"}`)) {
+		return nil, "", false
+	}
+	sig = string(line[i+len(key) : len(line)-2])
+	if len(sig) != 2*sha256.Size {
+		return nil, "", false
+	}
+	head := line[:i]
+	switch {
+	case head[len(head)-1] == ',':
+		head = head[:len(head)-1]
+	case bytes.Equal(bytes.TrimSpace(head), []byte("{")):
+	default:
+		return nil, "", false
+	}
+	entry = make([]byte, 0, len(head)+1)
+	entry = append(entry, head...)
+	entry = append(entry, '}')
+	return entry, sig, true
+}
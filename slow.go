@@ -0,0 +1,59 @@
+package zerowrap
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WarnIfSlow starts timing op and returns a stop function to call when it
+// completes. If op is still running after threshold, or once 80% of the
+// time left until the ctx deadline has passed, a warn entry is logged
+// right away with the elapsed time (and the time left before the
+// deadline), so hung calls show up before they return. Stop then logs a
+// second warn entry with the total duration. Fast operations log nothing.
+// A threshold <= 0 disables the warnings, e.g. for an unset setting.
+//
+//	defer zerowrap.WarnIfSlow(ctx, 500*time.Millisecond, "load-profile")()
+func WarnIfSlow(ctx context.Context, threshold time.Duration, op string) (stop func()) {
+	if threshold <= 0 {
+		return func() {}
+	}
+	log := FromCtx(ctx)
+	start := time.Now()
+
+	wait := threshold
+	deadline, hasDeadline := ctx.Deadline()
+	if hasDeadline {
+		wait = min(wait, time.Until(deadline)*8/10)
+	}
+
+	var mu sync.Mutex
+	fired, stopped := false, false
+	timer := time.AfterFunc(wait, func() {
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		fired = true
+		e := log.Warn().Str(FieldOperation, op)
+		if hasDeadline {
+			durToEvent(e, FieldDeadlineIn, time.Until(deadline))
+		}
+		AddDuration(e, time.Since(start)).Msg("slow operation still running")
+	})
+
+	return func() {
+		timer.Stop()
+		mu.Lock()
+		defer mu.Unlock()
+		if stopped {
+			return
+		}
+		stopped = true
+		if fired {
+			AddDuration(log.Warn().Str(FieldOperation, op), time.Since(start)).Msg("slow operation finished")
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package zerowrap
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestWarnIfSlow(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		run       time.Duration
+		want      []string
+	}{
+		{"fast", time.Second, 0, nil},
+		{"slow", 10 * time.Millisecond, 50 * time.Millisecond, []string{"slow operation still running", "slow operation finished"}},
+		{"zero threshold", 0, 20 * time.Millisecond, nil},
+		{"negative threshold", -time.Second, 20 * time.Millisecond, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			ctx := WithCtxZerolog(context.Background(), zerolog.New(&buf))
+
+			stop := WarnIfSlow(ctx, tt.threshold, "load")
+			time.Sleep(tt.run)
+			stop()
+
+			var got []string
+			for _, line := range strings.Split(buf.String(), "\n") {
+				for _, msg := range []string{"slow operation still running", "slow operation finished"} {
+					if strings.Contains(line, msg) {
+						got = append(got, msg)
+					}
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("entries %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package sqllog
+
+import (
+	"context"
+	"sync"
+
+	"github.com/bnema/zerowrap"
+)
+
+// counterKey is the context key of the query counter.
+type counterKey struct{}
+
+// queryCounter counts the statements of a context, in total and per
+// normalized statement.
+type queryCounter struct {
+	mu      sync.Mutex
+	total   int
+	repeats map[string]int
+}
+
+// WithQueryCounter returns ctx with a query counter, so statements run
+// with it (or a context derived from it) are counted for
+// Options.MaxQueries and Options.MaxRepeats. Set it up once per request,
+// e.g. in a middleware after httplog.Middleware, so the warnings carry
+// the request fields:
+//
+//	func countQueries(next http.Handler) http.Handler {
+//	    return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	        next.ServeHTTP(w, r.WithContext(sqllog.WithQueryCounter(r.Context())))
+//	    })
+//	}
+func WithQueryCounter(ctx context.Context) context.Context {
+	return context.WithValue(ctx, counterKey{}, &queryCounter{repeats: map[string]int{}})
+}
+
+// QueryCount returns the number of statements run with ctx since
+// WithQueryCounter, e.g. to log it with the request completion. It
+// returns 0 if ctx has no counter.
+func QueryCount(ctx context.Context) int {
+	c, _ := ctx.Value(counterKey{}).(*queryCounter)
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.total
+}
+
+// count counts query in the counter of ctx, logging a warning the first
+// time MaxQueries or MaxRepeats is exceeded.
+func (o Options) count(ctx context.Context, log zerowrap.Logger, query string) {
+	if o.MaxQueries <= 0 && o.MaxRepeats <= 0 {
+		return
+	}
+	c, _ := ctx.Value(counterKey{}).(*queryCounter)
+	if c == nil {
+		return
+	}
+	normalized := zerowrap.NormalizeQuery(query)
+	c.mu.Lock()
+	c.total++
+	c.repeats[normalized]++
+	total, repeats := c.total, c.repeats[normalized]
+	c.mu.Unlock()
+
+	if o.MaxQueries > 0 && total == o.MaxQueries+1 {
+		e := log.Warn()
+		if o.Database != "" {
+			e = e.Str(zerowrap.FieldDatabase, o.Database)
+		}
+		e.Int(zerowrap.FieldCount, total).Msg("too many queries")
+	}
+	if o.MaxRepeats > 0 && repeats == o.MaxRepeats+1 {
+		e := log.Warn()
+		if o.Database != "" {
+			e = e.Str(zerowrap.FieldDatabase, o.Database)
+		}
+		zerowrap.AddQuery(e, normalized).Int(zerowrap.FieldCount, repeats).Msg("repeated query, possible N+1")
+	}
+}
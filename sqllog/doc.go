@@ -0,0 +1,90 @@
+// Package sqllog provides database/sql statement logging for zerowrap.
+//
+// It does not depend on a database driver: Open, Wrap and WrapConnector
+// wrap whichever driver is used (pgx through its stdlib package,
+// lib/pq, go-sql-driver/mysql, sqlite...), and every statement is
+// logged with the logger of its context. ORMs built on database/sql,
+// such as gorm, log through it when given the wrapped *sql.DB. Native
+// pgx connections and gorm's own logger are covered by the pgxlog and
+// gormlog sub-packages, which log with the same Options through
+// Options.Record.
+//
+// # Usage
+//
+//	import (
+//	    _ "github.com/jackc/pgx/v5/stdlib"
+//	    "github.com/bnema/zerowrap/sqllog"
+//	)
+//
+//	db, err := sqllog.Open("pgx", dsn, sqllog.Options{Database: "orders"})
+//
+//	db.QueryRowContext(ctx, "SELECT name FROM users WHERE id = $1", 42).Scan(&name)
+//	// {"level":"debug","database":"orders","query":"SELECT name FROM users WHERE id = ?",
+//	//  "query_hash":"87154d352aba922e","duration_ms":0.41,"message":"query"}
+//
+// With gorm, open the dialector on the wrapped database:
+//
+//	gdb, err := gorm.Open(postgres.New(postgres.Config{Conn: db}), &gorm.Config{})
+//
+// # Query Normalization
+//
+// Statements are logged normalized with zerowrap.NormalizeQuery: literals
+// and bind parameters replaced by ?, lists collapsed and whitespace
+// collapsed. Arguments and the raw statement are never logged, and
+// statements differing only in their parameters share one query_hash, so
+// slow-query analysis can group them.
+//
+// Successful statements log at Options.Level (debug by default), failed
+// ones at error and canceled ones at warn.
+//
+// # Slow Queries and N+1
+//
+// SlowThreshold logs slower statements at warn level. MaxQueries and
+// MaxRepeats count the statements of a request, once its context is set
+// up with WithQueryCounter, and log a warning when it issues too many or
+// repeats one normalized statement too often, as loading a list then
+// each of its items one by one does:
+//
+//	db, err := sqllog.Open("pgx", dsn, sqllog.Options{
+//	    SlowThreshold: 200 * time.Millisecond,
+//	    MaxQueries:    50,
+//	    MaxRepeats:    10,
+//	})
+//
+//	ctx := sqllog.WithQueryCounter(r.Context())
+//	// {"level":"warn","request_id":"...","query":"SELECT * FROM items WHERE order_id = ?",
+//	//  "query_hash":"...","count":11,"message":"repeated query, possible N+1"}
+//
+// Each warning is logged once per context, when the limit is first
+// exceeded. QueryCount returns the total, e.g. for the completion log.
+//
+// # Transactions
+//
+// Options.BeginTx begins a transaction and returns a context whose
+// logger carries a tx_id. Statements run with it are logged with the
+// tx_id, and Commit and Rollback log the outcome (tx_outcome) and the
+// duration of the transaction, so it can be reconstructed from logs:
+//
+//	ctx, tx, err := opts.BeginTx(ctx, db, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer tx.Rollback() // logs nothing after Commit
+//
+//	if _, err := tx.ExecContext(ctx, "UPDATE stock SET qty = qty - 1 WHERE sku = $1", sku); err != nil {
+//	    return err
+//	}
+//	return tx.Commit()
+//
+// Commits log at Options.Level, rollbacks at warn and failures at error.
+//
+// # Raw Connections
+//
+// sql.Conn.Raw passes the wrapper to its callback; the driver connection
+// is reached through its Unwrap method:
+//
+//	conn.Raw(func(dc any) error {
+//	    pc := dc.(interface{ Unwrap() driver.Conn }).Unwrap().(*stdlib.Conn)
+//	    ...
+//	})
+package sqllog
@@ -0,0 +1,343 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Field names logged with transactions (see Options.BeginTx).
+const (
+	FieldTxOutcome   = "tx_outcome"
+	FieldTxIsolation = "tx_isolation"
+	FieldTxReadOnly  = "tx_read_only"
+)
+
+// Options configures statement logging.
+type Options struct {
+	// Database is logged as database on every entry, e.g. "orders".
+	// Not logged if empty.
+	Database string
+
+	// Level is the level of successful statements. Failed statements log
+	// at error level, canceled ones at warn.
+	// Defaults to debug if 0.
+	Level zerolog.Level
+
+	// SlowThreshold logs statements taking longer than this at warn
+	// level. Disabled if 0.
+	SlowThreshold time.Duration
+
+	// MaxQueries logs a warning when a context set up with
+	// WithQueryCounter (typically a request) issues more than MaxQueries
+	// statements. Disabled if 0.
+	MaxQueries int
+
+	// MaxRepeats logs a warning when a context set up with
+	// WithQueryCounter repeats the same normalized statement more than
+	// MaxRepeats times, the signature of N+1 queries. Disabled if 0.
+	MaxRepeats int
+}
+
+// Statement describes an executed statement, as passed to Options.Log.
+type Statement struct {
+	Query    string
+	Err      error
+	Duration time.Duration
+}
+
+// Open opens a database like sql.Open, with every statement logged
+// through the logger of its context. The driver must be registered, e.g.
+// by importing github.com/jackc/pgx/v5/stdlib for "pgx".
+//
+//	db, err := sqllog.Open("pgx", dsn, sqllog.Options{Database: "orders"})
+func Open(driverName, dsn string, opts Options) (*sql.DB, error) {
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	d := db.Driver()
+	_ = db.Close()
+
+	if dc, ok := d.(driver.DriverContext); ok {
+		c, err := dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+		return sql.OpenDB(WrapConnector(c, opts)), nil
+	}
+	return sql.OpenDB(dsnConnector{dsn: dsn, driver: Wrap(d, opts)}), nil
+}
+
+// Wrap returns d with every statement of its connections logged.
+func Wrap(d driver.Driver, opts Options) driver.Driver {
+	return &wrappedDriver{Driver: d, opts: opts}
+}
+
+// WrapConnector returns c with every statement of its connections
+// logged, for drivers configured through a connector:
+//
+//	db := sql.OpenDB(sqllog.WrapConnector(stdlib.GetConnector(*cfg), opts))
+func WrapConnector(c driver.Connector, opts Options) driver.Connector {
+	return &connector{Connector: c, opts: opts}
+}
+
+// Log writes the entry of statement s. Statements skipped by the driver
+// (driver.ErrSkip), which database/sql retries another way, are not
+// logged.
+func (o Options) Log(log zerowrap.Logger, s Statement) {
+	if errors.Is(s.Err, driver.ErrSkip) {
+		return
+	}
+	e := log.WithLevel(o.level(s.Err, s.Duration))
+	if o.Database != "" {
+		e = e.Str(zerowrap.FieldDatabase, o.Database)
+	}
+	e = zerowrap.AddQuery(e, s.Query)
+	if s.Err != nil {
+		e = e.Err(s.Err)
+	}
+	zerowrap.AddDuration(e, s.Duration).Msg("query")
+}
+
+// level returns the log level of a statement ending with err after dur.
+func (o Options) level(err error, dur time.Duration) zerolog.Level {
+	switch {
+	case err == nil:
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return zerolog.WarnLevel
+	default:
+		return zerolog.ErrorLevel
+	}
+	if o.SlowThreshold > 0 && dur >= o.SlowThreshold {
+		return zerolog.WarnLevel
+	}
+	return o.Level
+}
+
+// Record logs a statement run with ctx for dur, with the logger of ctx,
+// and counts it in the query counter of ctx. Clients not built on
+// database/sql log through it, as the pgxlog and gormlog sub-packages do.
+func (o Options) Record(ctx context.Context, query string, dur time.Duration, err error) {
+	log := zerowrap.FromCtx(ctx)
+	o.Log(log, Statement{Query: query, Err: err, Duration: dur})
+	if !errors.Is(err, driver.ErrSkip) {
+		o.count(ctx, log, query)
+	}
+}
+
+// log records a statement started at start.
+func (o Options) log(ctx context.Context, query string, start time.Time, err error) {
+	o.Record(ctx, query, time.Since(start), err)
+}
+
+// wrappedDriver wraps the connections of a driver.
+type wrappedDriver struct {
+	driver.Driver
+	opts Options
+}
+
+func (d *wrappedDriver) Open(name string) (driver.Conn, error) {
+	c, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: c, opts: d.opts}, nil
+}
+
+// connector wraps the connections of a connector.
+type connector struct {
+	driver.Connector
+	opts Options
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &conn{Conn: cn, opts: c.opts}, nil
+}
+
+func (c *connector) Driver() driver.Driver {
+	return Wrap(c.Connector.Driver(), c.opts)
+}
+
+// Close closes the wrapped connector if it holds resources, as sql.DB.Close
+// does for unwrapped ones.
+func (c *connector) Close() error {
+	if cl, ok := c.Connector.(io.Closer); ok {
+		return cl.Close()
+	}
+	return nil
+}
+
+// dsnConnector connects drivers without DriverContext.
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+// conn logs the statements of a connection. Optional interfaces the
+// wrapped connection does not implement return driver.ErrSkip, so
+// database/sql falls back as it would without the wrapper.
+type conn struct {
+	driver.Conn
+	opts Options
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	ex, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	res, err := ex.ExecContext(ctx, query, args)
+	c.opts.log(ctx, query, start, err)
+	return res, err
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	q, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+	start := time.Now()
+	rows, err := q.QueryContext(ctx, query, args)
+	c.opts.log(ctx, query, start, err)
+	return rows, err
+}
+
+func (c *conn) PrepareContext(ctx context.Context, query string) (driver.Stmt, error) {
+	var (
+		st  driver.Stmt
+		err error
+	)
+	if p, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		st, err = p.PrepareContext(ctx, query)
+	} else {
+		st, err = c.Conn.Prepare(query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &stmt{Stmt: st, query: query, opts: c.opts}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if b, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return b.BeginTx(ctx, opts)
+	}
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if p, ok := c.Conn.(driver.Pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if r, ok := c.Conn.(driver.SessionResetter); ok {
+		return r.ResetSession(ctx)
+	}
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if v, ok := c.Conn.(driver.Validator); ok {
+		return v.IsValid()
+	}
+	return true
+}
+
+func (c *conn) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// Unwrap returns the driver connection. sql.Conn.Raw callers receive the
+// wrapper and get the driver's own connection type through Unwrap.
+func (c *conn) Unwrap() driver.Conn {
+	return c.Conn
+}
+
+// stmt logs the executions of a prepared statement.
+type stmt struct {
+	driver.Stmt
+	query string
+	opts  Options
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	start := time.Now()
+	var (
+		res driver.Result
+		err error
+	)
+	if ex, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err = ex.ExecContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			res, err = s.Stmt.Exec(values)
+		}
+	}
+	s.opts.log(ctx, s.query, start, err)
+	return res, err
+}
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	start := time.Now()
+	var (
+		rows driver.Rows
+		err  error
+	)
+	if q, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		rows, err = q.QueryContext(ctx, args)
+	} else {
+		var values []driver.Value
+		if values, err = namedToValues(args); err == nil {
+			rows, err = s.Stmt.Query(values)
+		}
+	}
+	s.opts.log(ctx, s.query, start, err)
+	return rows, err
+}
+
+func (s *stmt) CheckNamedValue(nv *driver.NamedValue) error {
+	if ch, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return ch.CheckNamedValue(nv)
+	}
+	return driver.ErrSkip
+}
+
+// namedToValues converts positional arguments for drivers without
+// context support, which cannot take named ones.
+func namedToValues(args []driver.NamedValue) ([]driver.Value, error) {
+	values := make([]driver.Value, len(args))
+	for i, a := range args {
+		if a.Name != "" {
+			return nil, errors.New("sqllog: driver does not support named arguments")
+		}
+		values[i] = a.Value
+	}
+	return values, nil
+}
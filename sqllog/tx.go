@@ -0,0 +1,112 @@
+package sqllog
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Transaction outcomes logged in the tx_outcome field.
+const (
+	TxCommitted  = "committed"
+	TxRolledBack = "rolled_back"
+)
+
+// Beginner begins transactions; *sql.DB and *sql.Conn implement it.
+type Beginner interface {
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// Tx is a transaction begun with Options.BeginTx. Commit and Rollback
+// log the outcome and duration of the transaction.
+type Tx struct {
+	*sql.Tx
+	log   zerowrap.Logger
+	opts  Options
+	start time.Time
+}
+
+// BeginTx begins a transaction on db and returns ctx with a tx_id field
+// added to its logger. Run the statements of the transaction with the
+// returned context, so their entries carry the tx_id of the begin,
+// commit or rollback entries:
+//
+//	ctx, tx, err := opts.BeginTx(ctx, db, nil)
+//	if err != nil {
+//	    return err
+//	}
+//	defer tx.Rollback()
+//
+//	if _, err := tx.ExecContext(ctx, "UPDATE accounts SET balance = balance - $1 WHERE id = $2", amount, from); err != nil {
+//	    return err
+//	}
+//	return tx.Commit()
+func (o Options) BeginTx(ctx context.Context, db Beginner, txOpts *sql.TxOptions) (context.Context, *Tx, error) {
+	ctx = zerowrap.CtxWithField(ctx, zerowrap.FieldTxID, zerowrap.NewID())
+	log := zerowrap.FromCtx(ctx)
+	tx, err := db.BeginTx(ctx, txOpts)
+	if err != nil {
+		o.txEvent(log.Error()).Err(err).Msg("transaction begin failed")
+		return ctx, nil, err
+	}
+	e := o.txEvent(log.WithLevel(o.Level))
+	if txOpts != nil {
+		if txOpts.Isolation != sql.LevelDefault {
+			e = e.Str(FieldTxIsolation, txOpts.Isolation.String())
+		}
+		if txOpts.ReadOnly {
+			e = e.Bool(FieldTxReadOnly, true)
+		}
+	}
+	e.Msg("transaction started")
+	return ctx, &Tx{Tx: tx, log: log, opts: o, start: time.Now()}, nil
+}
+
+// Commit commits the transaction and logs the outcome with the
+// duration since BeginTx. Failed commits log at error level.
+func (tx *Tx) Commit() error {
+	err := tx.Tx.Commit()
+	if errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	if err != nil {
+		tx.end(tx.log.Error().Err(err)).Msg("transaction commit failed")
+		return err
+	}
+	tx.end(tx.log.WithLevel(tx.opts.Level).Str(FieldTxOutcome, TxCommitted)).Msg("transaction committed")
+	return nil
+}
+
+// Rollback aborts the transaction and logs the outcome with the
+// duration since BeginTx, at warn level. Calling Rollback after Commit,
+// as a deferred Rollback does, logs nothing.
+func (tx *Tx) Rollback() error {
+	err := tx.Tx.Rollback()
+	if errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	if err != nil {
+		tx.end(tx.log.Error().Err(err)).Msg("transaction rollback failed")
+		return err
+	}
+	tx.end(tx.log.Warn().Str(FieldTxOutcome, TxRolledBack)).Msg("transaction rolled back")
+	return nil
+}
+
+// txEvent adds the database field to a transaction entry.
+func (o Options) txEvent(e *zerolog.Event) *zerolog.Event {
+	if o.Database != "" {
+		e = e.Str(zerowrap.FieldDatabase, o.Database)
+	}
+	return e
+}
+
+// end adds the database and duration fields to a commit or rollback
+// entry.
+func (tx *Tx) end(e *zerolog.Event) *zerolog.Event {
+	return zerowrap.AddDuration(tx.opts.txEvent(e), time.Since(tx.start))
+}
@@ -0,0 +1,185 @@
+package zerowrap
+
+import (
+	"cmp"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+// StructLimits bounds what WithStruct, FromCtxWithStruct and
+// CtxWithStruct extract, so a large or cyclic model cannot explode log
+// entries or hang the logger.
+type StructLimits struct {
+	// MaxDepth is the nesting of objects and arrays logged below the
+	// struct's fields. Deeper values are logged as their type name,
+	// e.g. "<models.Order>".
+	// Defaults to 3 if 0.
+	MaxDepth int
+
+	// MaxFields caps the fields logged per object. Extra fields are
+	// dropped and the object is marked with _truncated: true.
+	// Defaults to 50 if 0.
+	MaxFields int
+
+	// MaxSliceLen caps the elements logged per slice, array or map. A
+	// cut slice or array ends with a "... N more" element; a cut map
+	// keeps its first keys in sorted order and is marked with
+	// _truncated: true.
+	// Defaults to 20 if 0.
+	MaxSliceLen int
+}
+
+var structLimits atomic.Pointer[StructLimits]
+
+// SetStructLimits sets the limits applied to struct extraction.
+//
+//	zerowrap.SetStructLimits(zerowrap.StructLimits{MaxDepth: 2, MaxSliceLen: 5})
+func SetStructLimits(l StructLimits) {
+	structLimits.Store(&l)
+}
+
+// currentStructLimits returns the limits with defaults applied.
+func currentStructLimits() StructLimits {
+	var l StructLimits
+	if p := structLimits.Load(); p != nil {
+		l = *p
+	}
+	if l.MaxDepth == 0 {
+		l.MaxDepth = 3
+	}
+	if l.MaxFields == 0 {
+		l.MaxFields = 50
+	}
+	if l.MaxSliceLen == 0 {
+		l.MaxSliceLen = 20
+	}
+	return l
+}
+
+// Types logged as they are rather than walked field by field.
+var (
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	errorType           = reflect.TypeFor[error]()
+	objectMarshalerType = reflect.TypeFor[zerolog.LogObjectMarshaler]()
+	arrayMarshalerType  = reflect.TypeFor[zerolog.LogArrayMarshaler]()
+	urlType             = reflect.TypeFor[url.URL]()
+	fieldsProviderType  = reflect.TypeFor[FieldsProvider]()
+)
+
+// structExtractor walks structs within limits, tracking the pointers on
+// the current path to detect cycles.
+type structExtractor struct {
+	limits StructLimits
+	path   []uintptr
+}
+
+// value returns v converted for logging: structs become maps and slices
+// become []any, bounded by the limits.
+func (e *structExtractor) value(v reflect.Value, depth int) any {
+	if !v.IsValid() {
+		return nil
+	}
+	if v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	if keepValue(v.Type()) {
+		return v.Interface()
+	}
+	if v.Type().Implements(fieldsProviderType) && (v.Kind() != reflect.Ptr || !v.IsNil()) {
+		return v.Interface().(FieldsProvider).LogFields()
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return nil
+		}
+		p := v.Pointer()
+		if slices.Contains(e.path, p) {
+			return "<cycle>"
+		}
+		e.path = append(e.path, p)
+		defer func() { e.path = e.path[:len(e.path)-1] }()
+		return e.value(v.Elem(), depth)
+
+	case reflect.Struct:
+		if depth > e.limits.MaxDepth {
+			return "<" + v.Type().String() + ">"
+		}
+		fields := make(map[string]any)
+		e.structFields(fields, v, "", depth)
+		return fields
+
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return nil
+		}
+		if depth > e.limits.MaxDepth {
+			return "<" + v.Type().String() + ">"
+		}
+		n := v.Len()
+		out := make([]any, 0, min(n, e.limits.MaxSliceLen)+1)
+		for i := range min(n, e.limits.MaxSliceLen) {
+			out = append(out, e.value(v.Index(i), depth+1))
+		}
+		if n > e.limits.MaxSliceLen {
+			out = append(out, fmt.Sprintf("... %d more", n-e.limits.MaxSliceLen))
+		}
+		return out
+
+	case reflect.Map:
+		if v.IsNil() {
+			return nil
+		}
+		if depth > e.limits.MaxDepth {
+			return "<" + v.Type().String() + ">"
+		}
+		keys := v.MapKeys()
+		slices.SortFunc(keys, func(a, b reflect.Value) int {
+			return cmp.Compare(fmt.Sprint(a.Interface()), fmt.Sprint(b.Interface()))
+		})
+		out := make(map[string]any, min(len(keys), e.limits.MaxSliceLen)+1)
+		for _, k := range keys[:min(len(keys), e.limits.MaxSliceLen)] {
+			out[fmt.Sprint(k.Interface())] = e.value(v.MapIndex(k), depth+1)
+		}
+		if len(keys) > e.limits.MaxSliceLen {
+			out[FieldTruncated] = true
+		}
+		return out
+
+	default:
+		return v.Interface()
+	}
+}
+
+// keepValue reports whether values of type t are logged as they are:
+// basic kinds, byte slices, URLs and types with their own marshaling.
+func keepValue(t reflect.Type) bool {
+	if t == urlType || t == reflect.PointerTo(urlType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Struct, reflect.Map, reflect.Interface:
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() != reflect.Uint8 {
+			break
+		}
+		return true
+	default:
+		return true
+	}
+	return t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) ||
+		t.Implements(errorType) || t.Implements(objectMarshalerType) ||
+		t.Implements(arrayMarshalerType)
+}
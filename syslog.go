@@ -0,0 +1,260 @@
+package zerowrap
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// syslogFacilities maps facility names to RFC 5424 facility codes.
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3,
+	"auth": 4, "syslog": 5, "lpr": 6, "news": 7,
+	"uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// syslogSockets are the local syslog sockets tried when Network is empty.
+var syslogSockets = []string{"/dev/log", "/var/run/syslog", "/var/run/log"}
+
+// SyslogConfig holds configuration for syslog output.
+type SyslogConfig struct {
+	// Network is "udp", "tcp", "unix" or "unixgram".
+	// Defaults to the local syslog socket if empty.
+	Network string
+
+	// Addr is the daemon address, e.g. "logs.internal:514".
+	// Ignored if Network is empty.
+	Addr string
+
+	// Facility is the syslog facility name, e.g. "daemon" or "local0".
+	// Defaults to "user" if empty.
+	Facility string
+
+	// Tag is the APP-NAME of each message.
+	// Defaults to the executable name if empty.
+	Tag string
+
+	// Hostname is the HOSTNAME of each message.
+	// Defaults to os.Hostname if empty.
+	Hostname string
+}
+
+// SyslogWriter is a zerolog.LevelWriter that sends entries to a syslog
+// daemon using RFC 5424 framing, with the syslog severity derived from
+// the entry level. The message is the JSON entry.
+type SyslogWriter struct {
+	network  string
+	addr     string
+	facility int
+	header   string // " HOSTNAME APP-NAME PROCID - - "
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+// NewSyslogWriter connects to a syslog daemon.
+//
+//	syslog, err := zerowrap.NewSyslogWriter(zerowrap.SyslogConfig{
+//	    Network:  "udp",
+//	    Addr:     "logs.internal:514",
+//	    Facility: "local0",
+//	    Tag:      "billing",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer syslog.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: syslog})
+func NewSyslogWriter(cfg SyslogConfig) (*SyslogWriter, error) {
+	facility := 1
+	if cfg.Facility != "" {
+		f, ok := syslogFacilities[strings.ToLower(cfg.Facility)]
+		if !ok {
+			return nil, fmt.Errorf("zerowrap: unknown syslog facility %q", cfg.Facility)
+		}
+		facility = f
+	}
+
+	tag := cfg.Tag
+	if tag == "" {
+		tag = filepath.Base(os.Args[0])
+	}
+	hostname := cfg.Hostname
+	if hostname == "" {
+		hostname, _ = os.Hostname()
+	}
+
+	w := &SyslogWriter{
+		network:  cfg.Network,
+		addr:     cfg.Addr,
+		facility: facility,
+		header: " " + syslogField(hostname, 255) +
+			" " + syslogField(tag, 48) +
+			" " + strconv.Itoa(os.Getpid()) + " - - ",
+	}
+	if err := w.connect(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// connect dials the daemon, trying the local sockets if no network is set.
+func (w *SyslogWriter) connect() error {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+
+	if w.network != "" {
+		conn, err := net.Dial(w.network, w.addr)
+		if err != nil {
+			return fmt.Errorf("zerowrap: syslog: %w", err)
+		}
+		w.conn = conn
+		return nil
+	}
+
+	for _, path := range syslogSockets {
+		for _, network := range []string{"unixgram", "unix"} {
+			if conn, err := net.Dial(network, path); err == nil {
+				w.conn = conn
+				return nil
+			}
+		}
+	}
+	return errors.New("zerowrap: syslog: no local syslog socket found")
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (w *SyslogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(entryLevel(p), p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (w *SyslogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	msg := bytes.TrimRight(p, "\n")
+
+	var buf bytes.Buffer
+	buf.WriteByte('<')
+	buf.WriteString(strconv.Itoa(w.facility*8 + syslogSeverity(level)))
+	buf.WriteString(">1 ")
+	buf.WriteString(time.Now().Format(time.RFC3339Nano))
+	buf.WriteString(w.header)
+	buf.Write(msg)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if err := w.send(buf.Bytes()); err != nil {
+		// Reconnect once, e.g. after a daemon restart.
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+		if err := w.send(buf.Bytes()); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// send writes one message, using octet-counting framing (RFC 6587) on
+// stream connections.
+func (w *SyslogWriter) send(msg []byte) error {
+	if w.conn == nil {
+		return net.ErrClosed
+	}
+	switch w.conn.(type) {
+	case *net.TCPConn:
+		_, err := fmt.Fprintf(w.conn, "%d %s", len(msg), msg)
+		return err
+	case *net.UnixConn:
+		if w.conn.LocalAddr().Network() == "unix" {
+			_, err := w.conn.Write(append(msg, '\n'))
+			return err
+		}
+	}
+	_, err := w.conn.Write(msg)
+	return err
+}
+
+// Close closes the connection to the daemon.
+func (w *SyslogWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// syslogSeverity maps a zerolog level to an RFC 5424 severity.
+func syslogSeverity(level zerolog.Level) int {
+	switch level {
+	case zerolog.TraceLevel, zerolog.DebugLevel:
+		return 7 // debug
+	case zerolog.InfoLevel:
+		return 6 // informational
+	case zerolog.WarnLevel:
+		return 4 // warning
+	case zerolog.ErrorLevel:
+		return 3 // error
+	case zerolog.FatalLevel:
+		return 2 // critical
+	case zerolog.PanicLevel:
+		return 1 // alert
+	default:
+		return 5 // notice
+	}
+}
+
+// syslogField returns s as an RFC 5424 header field: printable ASCII
+// without spaces, at most max bytes, or "-" if empty.
+func syslogField(s string, max int) string {
+	s = strings.Map(func(r rune) rune {
+		if r < 33 || r > 126 {
+			return -1
+		}
+		return r
+	}, s)
+	if len(s) > max {
+		s = s[:max]
+	}
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// entryLevel reads the level of a JSON entry without decoding it fully.
+// Returns zerolog.NoLevel if the level cannot be found.
+func entryLevel(p []byte) zerolog.Level {
+	key := []byte(`"` + zerolog.LevelFieldName + `":"`)
+	i := bytes.Index(p, key)
+	if i < 0 {
+		return zerolog.NoLevel
+	}
+	rest := p[i+len(key):]
+	end := bytes.IndexByte(rest, '"')
+	if end < 0 {
+		return zerolog.NoLevel
+	}
+	level, err := zerolog.ParseLevel(string(rest[:end]))
+	if err != nil {
+		return zerolog.NoLevel
+	}
+	return level
+}
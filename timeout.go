@@ -0,0 +1,156 @@
+package zerowrap
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TimeoutWriter is a zerolog.LevelWriter that bounds how long a write to
+// a blocking sink may take, e.g. a file on an NFS mount or a pipe whose
+// reader hangs. A write not done within the timeout returns an error
+// wrapping ErrWriteTimeout, so the entry goes through the fallback or
+// drop policy of the surrounding writer (FailoverWriter, MultiWriter)
+// instead of stalling the application.
+//
+// Writes are run by a single worker goroutine, which reuses one entry
+// buffer and one timer. The timed-out write keeps running in the
+// worker; until it returns, further writes fail right away with
+// ErrWriteTimeout rather than piling up behind the hung sink.
+type TimeoutWriter struct {
+	out     io.Writer
+	timeout time.Duration
+	count   atomic.Int64
+
+	jobs    chan timeoutJob
+	results chan writeResult
+
+	mu     sync.Mutex
+	entry  []byte
+	timer  *time.Timer
+	busy   bool // a timed-out write is still running
+	closed bool
+}
+
+// timeoutJob is a write handed to the worker of a TimeoutWriter.
+type timeoutJob struct {
+	level zerolog.Level
+	p     []byte
+}
+
+// writeResult is the outcome of a write run by a TimeoutWriter.
+type writeResult struct {
+	n   int
+	err error
+}
+
+// NewTimeoutWriter wraps out so that each write gives up after timeout.
+// Defaults to 5 seconds if timeout is 0. Close stops its worker.
+//
+//	nfs, _ := os.OpenFile("/mnt/logs/app.log", os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+//	out, _ := zerowrap.NewFailoverWriter(zerowrap.FailoverConfig{
+//	    Primary: zerowrap.NewTimeoutWriter(nfs, 2*time.Second),
+//	})
+//
+//	log := zerowrap.New(zerowrap.Config{Format: "json", Output: out})
+func NewTimeoutWriter(out io.Writer, timeout time.Duration) *TimeoutWriter {
+	if timeout == 0 {
+		timeout = 5 * time.Second
+	}
+	timer := time.NewTimer(timeout)
+	timer.Stop()
+	w := &TimeoutWriter{
+		out:     out,
+		timeout: timeout,
+		jobs:    make(chan timeoutJob),
+		results: make(chan writeResult, 1),
+		timer:   timer,
+	}
+	go w.run()
+	return w
+}
+
+// Write implements io.Writer.
+func (w *TimeoutWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter, passing level on to
+// level-aware sinks. Writes after Close fail with os.ErrClosed.
+func (w *TimeoutWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return 0, os.ErrClosed
+	}
+
+	if w.busy {
+		select {
+		case <-w.results:
+			w.busy = false
+		default:
+			return 0, w.timedOut()
+		}
+	}
+
+	// The sink may still hold the entry after a timeout, while the caller
+	// reuses p. The worker is idle here, so the buffer is free.
+	w.entry = append(w.entry[:0], p...)
+	w.jobs <- timeoutJob{level: level, p: w.entry}
+
+	w.timer.Reset(w.timeout)
+	select {
+	case r := <-w.results:
+		w.timer.Stop()
+		return r.n, r.err
+	case <-w.timer.C:
+		w.busy = true
+		return 0, w.timedOut()
+	}
+}
+
+// run writes the entries handed over by WriteLevel until Close.
+func (w *TimeoutWriter) run() {
+	for job := range w.jobs {
+		n, err := writeLevel(w.out, job.level, job.p)
+		w.results <- writeResult{n, err}
+	}
+}
+
+// TimedOut returns the number of writes that timed out, including those
+// refused while a timed-out write was still running.
+func (w *TimeoutWriter) TimedOut() int64 {
+	return w.count.Load()
+}
+
+// Close stops the worker and closes the sink if it implements io.Closer,
+// except os.Stdout and os.Stderr. It does not wait for a timed-out write.
+func (w *TimeoutWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.jobs)
+	w.mu.Unlock()
+
+	if w.out == os.Stdout || w.out == os.Stderr {
+		return nil
+	}
+	if c, ok := w.out.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// timedOut counts a timed-out write and returns its error.
+func (w *TimeoutWriter) timedOut() error {
+	w.count.Add(1)
+	return fmt.Errorf("%w after %s", ErrWriteTimeout, w.timeout)
+}
@@ -0,0 +1,80 @@
+package zerowrap
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// hungWriter blocks writes until release is closed.
+type hungWriter struct {
+	writeRecorder
+	release chan struct{}
+}
+
+func (w *hungWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.writeRecorder.Write(p)
+}
+
+func BenchmarkTimeoutWriter(b *testing.B) {
+	w := NewTimeoutWriter(io.Discard, time.Second)
+	defer w.Close()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_, _ = w.Write(benchEntry)
+	}
+}
+
+func TestTimeoutWriterAllocs(t *testing.T) {
+	w := NewTimeoutWriter(io.Discard, time.Second)
+	defer w.Close()
+	if n := testing.AllocsPerRun(100, func() { _, _ = w.Write(benchEntry) }); n != 0 {
+		t.Errorf("Write allocates %v times, want 0", n)
+	}
+}
+
+func TestTimeoutWriterHungSink(t *testing.T) {
+	sink := &hungWriter{release: make(chan struct{})}
+	w := NewTimeoutWriter(sink, 20*time.Millisecond)
+	defer w.Close()
+
+	entry := []byte(`{"n":1}` + "\n")
+	if _, err := w.Write(entry); !errors.Is(err, ErrWriteTimeout) {
+		t.Fatalf("hung write = %v, want ErrWriteTimeout", err)
+	}
+	copy(entry, `{"n":9}`)
+
+	start := time.Now()
+	if _, err := w.Write([]byte(`{"n":2}` + "\n")); !errors.Is(err, ErrWriteTimeout) {
+		t.Fatalf("write behind hung write = %v, want ErrWriteTimeout", err)
+	}
+	if d := time.Since(start); d >= 20*time.Millisecond {
+		t.Errorf("write behind hung write waited %s", d)
+	}
+
+	close(sink.release)
+	time.Sleep(10 * time.Millisecond)
+	if _, err := w.Write([]byte(`{"n":3}` + "\n")); err != nil {
+		t.Fatalf("write after the sink recovered: %v", err)
+	}
+	want := []string{`{"n":1}` + "\n", `{"n":3}` + "\n"}
+	if len(sink.writes) != 2 || sink.writes[0] != want[0] || sink.writes[1] != want[1] {
+		t.Errorf("sink got %q, want %q", sink.writes, want)
+	}
+	if n := w.TimedOut(); n != 2 {
+		t.Errorf("TimedOut = %d, want 2", n)
+	}
+}
+
+func TestTimeoutWriterWriteAfterClose(t *testing.T) {
+	w := NewTimeoutWriter(io.Discard, time.Second)
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write(benchEntry); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Write after Close = %v, want os.ErrClosed", err)
+	}
+}
@@ -0,0 +1,100 @@
+package zerowrap
+
+import (
+	"context"
+	"strings"
+)
+
+// W3C Trace Context headers.
+const (
+	HeaderTraceParent = "traceparent"
+	HeaderTraceState  = "tracestate"
+)
+
+// traceParentKey is the context key of the parsed trace context.
+type traceParentKey struct{}
+
+// TraceParent is a parsed W3C traceparent header.
+type TraceParent struct {
+	TraceID string // 32 lowercase hex characters
+	SpanID  string // 16 lowercase hex characters, the caller's span
+	Flags   byte
+	State   string // raw tracestate header, if any
+}
+
+// Sampled reports whether the caller recorded the trace.
+func (tp TraceParent) Sampled() bool {
+	return tp.Flags&0x01 != 0
+}
+
+// ParseTraceParent parses a traceparent header value
+// ("00-<trace-id>-<span-id>-<flags>"). Returns false if it is malformed,
+// has an all-zero ID or the invalid version ff. Higher versions are
+// parsed as version 00, as the specification requires.
+func ParseTraceParent(s string) (TraceParent, bool) {
+	s = strings.TrimSpace(s)
+	if len(s) < 55 || (len(s) > 55 && s[55] != '-') {
+		return TraceParent{}, false
+	}
+	version, traceID, spanID, flags := s[0:2], s[3:35], s[36:52], s[53:55]
+	if s[2] != '-' || s[35] != '-' || s[52] != '-' {
+		return TraceParent{}, false
+	}
+	if !isLowerHex(version) || version == "ff" || (version == "00" && len(s) != 55) {
+		return TraceParent{}, false
+	}
+	if !isLowerHex(traceID) || !isLowerHex(spanID) || !isLowerHex(flags) {
+		return TraceParent{}, false
+	}
+	if strings.Trim(traceID, "0") == "" || strings.Trim(spanID, "0") == "" {
+		return TraceParent{}, false
+	}
+	f := hexNibble(flags[0])<<4 | hexNibble(flags[1])
+	return TraceParent{TraceID: traceID, SpanID: spanID, Flags: f}, true
+}
+
+// CtxWithTraceParent returns a new context carrying the trace context
+// read through get (traceparent and tracestate headers), whose logger
+// includes the trace_id and span_id fields, for log/trace correlation in
+// services without a tracing SDK. ctx is returned unchanged if the
+// traceparent header is absent or malformed.
+//
+//	ctx := zerowrap.CtxWithTraceParent(r.Context(), r.Header.Get)
+func CtxWithTraceParent(ctx context.Context, get func(key string) string) context.Context {
+	tp, ok := ParseTraceParent(get(HeaderTraceParent))
+	if !ok {
+		return ctx
+	}
+	tp.State = get(HeaderTraceState)
+	ctx = context.WithValue(ctx, traceParentKey{}, tp)
+	return CtxWithFields(ctx, map[string]any{
+		FieldTraceID: tp.TraceID,
+		FieldSpanID:  tp.SpanID,
+	})
+}
+
+// TraceParentFromCtx returns the trace context set with
+// CtxWithTraceParent, if any.
+func TraceParentFromCtx(ctx context.Context) (TraceParent, bool) {
+	tp, ok := ctx.Value(traceParentKey{}).(TraceParent)
+	return tp, ok
+}
+
+// isLowerHex reports whether s only holds lowercase hex characters.
+func isLowerHex(s string) bool {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// hexNibble returns the value of a lowercase hex character.
+func hexNibble(c byte) byte {
+	if c >= 'a' {
+		return c - 'a' + 10
+	}
+	return c - '0'
+}
@@ -0,0 +1,219 @@
+package zerowrap
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TransportConfig holds configuration for outbound HTTP logging.
+type TransportConfig struct {
+	// Level is the log level for successful calls.
+	// Defaults to "debug". 4xx/5xx responses log at warn, transport errors at error.
+	Level string
+
+	// RedactQuery lists query parameters whose values are replaced by Redacted.
+	// Defaults to DefaultRedactQueryKeys if nil.
+	RedactQuery []string
+
+	// CaptureRequestBody logs the request body (up to MaxBodyBytes).
+	CaptureRequestBody bool
+
+	// CaptureResponseBody logs the response body (up to MaxBodyBytes).
+	// The body is captured as the caller reads it, so the entry is
+	// written once the caller reads the body to the end or closes it.
+	CaptureResponseBody bool
+
+	// MaxBodyBytes caps captured bodies.
+	// Defaults to 4096 if 0.
+	MaxBodyBytes int
+
+	// PropagateIDs sets the X-Request-ID and X-Correlation-ID headers of
+	// outbound requests from the request context (see InjectHTTP), so
+	// downstream services log the same IDs.
+	PropagateIDs bool
+}
+
+// LoggingTransport is an http.RoundTripper that logs outbound calls
+// using the logger from the request context.
+type LoggingTransport struct {
+	next  http.RoundTripper
+	cfg   TransportConfig
+	level zerolog.Level
+}
+
+// NewLoggingTransport wraps rt with outbound call logging.
+// If rt is nil, http.DefaultTransport is used.
+//
+//	client := &http.Client{
+//	    Transport: zerowrap.NewLoggingTransport(nil, zerowrap.TransportConfig{}),
+//	}
+//	req, _ := http.NewRequestWithContext(ctx, "GET", url, nil)
+//	resp, err := client.Do(req) // logged with ctx's logger
+func NewLoggingTransport(rt http.RoundTripper, cfg TransportConfig) *LoggingTransport {
+	if rt == nil {
+		rt = http.DefaultTransport
+	}
+	if cfg.MaxBodyBytes == 0 {
+		cfg.MaxBodyBytes = 4096
+	}
+	level := zerolog.DebugLevel
+	if cfg.Level != "" {
+		level = parseLevel(cfg.Level)
+	}
+	return &LoggingTransport{next: rt, cfg: cfg, level: level}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *LoggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	log := FromCtx(req.Context())
+
+	if t.cfg.PropagateIDs {
+		// RoundTrippers must not modify the caller's request.
+		req = req.Clone(req.Context())
+		InjectHTTP(req, req.Context())
+	}
+
+	var reqBody []byte
+	if t.cfg.CaptureRequestBody && req.Body != nil && req.Body != http.NoBody {
+		reqBody, req = t.captureRequestBody(req)
+	}
+
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	dur := time.Since(start)
+
+	level := t.level
+	switch {
+	case err != nil:
+		level = zerolog.ErrorLevel
+	case resp.StatusCode >= 400:
+		level = zerolog.WarnLevel
+	}
+
+	e := log.WithLevel(level)
+	if e == nil {
+		return resp, err
+	}
+
+	e = e.Str(FieldMethod, req.Method).
+		Str(FieldHost, req.URL.Host).
+		Str(FieldPath, req.URL.Path)
+	if q := RedactQuery(req.URL.RawQuery, t.cfg.RedactQuery); q != "" {
+		e = e.Str(FieldQueryStr, q)
+	}
+	if n := retryAttempt(req.Context()); n > 1 {
+		e = e.Int(FieldRetries, n-1)
+	}
+	if reqBody != nil {
+		e = e.Bytes(FieldRequestBody, reqBody)
+	}
+	if err != nil {
+		AddDuration(e.Err(err), dur).Msg("outbound request failed")
+		return resp, err
+	}
+
+	e = AddDuration(e.Int(FieldStatus, resp.StatusCode), dur)
+	if t.cfg.CaptureResponseBody && resp.Body != nil && resp.Body != http.NoBody {
+		resp.Body = &bodyCapture{ReadCloser: resp.Body, max: t.cfg.MaxBodyBytes, e: e}
+		return resp, nil
+	}
+	e.Msg("outbound request completed")
+	return resp, nil
+}
+
+// captureRequestBody reads up to MaxBodyBytes of the request body. It
+// returns the request to send: without GetBody, a clone whose body
+// replays the bytes read, as RoundTrippers must not modify the caller's
+// request.
+func (t *LoggingTransport) captureRequestBody(req *http.Request) ([]byte, *http.Request) {
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, req
+		}
+		defer body.Close()
+		buf, _ := io.ReadAll(io.LimitReader(body, int64(t.cfg.MaxBodyBytes)))
+		return buf, req
+	}
+	buf, _ := io.ReadAll(io.LimitReader(req.Body, int64(t.cfg.MaxBodyBytes)))
+	clone := req.Clone(req.Context())
+	clone.Body = readCloser{io.MultiReader(bytes.NewReader(buf), req.Body), req.Body}
+	return buf, clone
+}
+
+// bodyCapture records up to max bytes of a response body as the caller
+// reads it, and writes the entry e with them once the body is read to
+// the end or closed. Streamed responses (SSE, long polling) are passed
+// on as they arrive.
+type bodyCapture struct {
+	io.ReadCloser
+	max int
+
+	mu  sync.Mutex
+	buf []byte
+	e   *zerolog.Event // nil once written
+}
+
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.max - len(b.buf); room > 0 && b.e != nil {
+		b.buf = append(b.buf, p[:min(n, room)]...)
+	}
+	if err != nil {
+		b.write()
+	}
+	return n, err
+}
+
+func (b *bodyCapture) Close() error {
+	err := b.ReadCloser.Close()
+	b.mu.Lock()
+	b.write()
+	b.mu.Unlock()
+	return err
+}
+
+// write writes the entry once. The caller holds mu.
+func (b *bodyCapture) write() {
+	if b.e == nil {
+		return
+	}
+	b.e.Bytes(FieldResponseBody, b.buf).Msg("outbound request completed")
+	b.e = nil
+}
+
+// readCloser combines a reader with the closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+type retryAttemptKey struct{}
+
+// CtxWithRetryAttempt returns a context recording the current attempt number
+// (starting at 1) of a retried operation. LoggingTransport logs attempts
+// after the first as the retries field.
+//
+//	for attempt := 1; attempt <= 3; attempt++ {
+//	    req, _ := http.NewRequestWithContext(zerowrap.CtxWithRetryAttempt(ctx, attempt), "GET", url, nil)
+//	    if resp, err = client.Do(req); err == nil {
+//	        break
+//	    }
+//	}
+func CtxWithRetryAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, retryAttemptKey{}, attempt)
+}
+
+// retryAttempt returns the attempt number stored in ctx, or 0 if none.
+func retryAttempt(ctx context.Context) int {
+	n, _ := ctx.Value(retryAttemptKey{}).(int)
+	return n
+}
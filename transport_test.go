@@ -0,0 +1,151 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// transportRequest returns a request whose context holds a logger
+// writing to buf.
+func transportRequest(t *testing.T, buf *bytes.Buffer, method, url string, body io.Reader) *http.Request {
+	t.Helper()
+	log := Logger{zerolog.New(buf)}
+	req, err := http.NewRequestWithContext(WithCtx(t.Context(), log), method, url, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+// entry decodes the single entry in buf.
+func entry(t *testing.T, buf *bytes.Buffer) map[string]any {
+	t.Helper()
+	var m map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatalf("decode entry %q: %v", buf, err)
+	}
+	return m
+}
+
+func TestLoggingTransportRequestBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(b)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	// A reader without GetBody, so the transport has to replay it.
+	body := io.NopCloser(strings.NewReader(`{"name":"ada"}`))
+	req := transportRequest(t, &buf, http.MethodPost, srv.URL+"/users", body)
+	req.GetBody = nil
+
+	rt := NewLoggingTransport(nil, TransportConfig{CaptureRequestBody: true})
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	echoed, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if req.Body != body {
+		t.Error("RoundTrip replaced the body of the caller's request")
+	}
+	if string(echoed) != `{"name":"ada"}` {
+		t.Errorf("server received %q", echoed)
+	}
+	if got := entry(t, &buf)[FieldRequestBody]; got != `{"name":"ada"}` {
+		t.Errorf("%s = %v", FieldRequestBody, got)
+	}
+}
+
+func TestLoggingTransportStreamedResponse(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, "data: first\n\n")
+		w.(http.Flusher).Flush()
+		<-release
+		_, _ = io.WriteString(w, "data: second\n\n")
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var buf bytes.Buffer
+	req := transportRequest(t, &buf, http.MethodGet, srv.URL+"/events", nil)
+	rt := NewLoggingTransport(nil, TransportConfig{CaptureResponseBody: true})
+
+	done := make(chan *http.Response)
+	go func() {
+		resp, err := rt.RoundTrip(req)
+		if err != nil {
+			t.Error(err)
+		}
+		done <- resp
+	}()
+	var resp *http.Response
+	select {
+	case resp = <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("RoundTrip waited for the end of a streamed response")
+	}
+	if resp == nil {
+		return
+	}
+
+	first := make([]byte, len("data: first\n\n"))
+	if _, err := io.ReadFull(resp.Body, first); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("entry written before the body was read: %s", buf.Bytes())
+	}
+	release <- struct{}{}
+	rest, _ := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if got := string(first) + string(rest); got != "data: first\n\ndata: second\n\n" {
+		t.Errorf("caller read %q", got)
+	}
+	e := entry(t, &buf)
+	if got := e[FieldResponseBody]; got != "data: first\n\ndata: second\n\n" {
+		t.Errorf("%s = %q", FieldResponseBody, got)
+	}
+	if e[FieldStatus] != float64(200) {
+		t.Errorf("%s = %v", FieldStatus, e[FieldStatus])
+	}
+}
+
+func TestLoggingTransportResponseBodyCap(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("x", 100))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	req := transportRequest(t, &buf, http.MethodGet, srv.URL, nil)
+	rt := NewLoggingTransport(nil, TransportConfig{CaptureResponseBody: true, MaxBodyBytes: 10})
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Closing without reading writes the entry with what was read.
+	got, _ := io.ReadAll(io.LimitReader(resp.Body, 50))
+	_ = resp.Body.Close()
+	_ = resp.Body.Close()
+
+	if len(got) != 50 {
+		t.Errorf("caller read %d bytes", len(got))
+	}
+	if body := entry(t, &buf)[FieldResponseBody]; body != strings.Repeat("x", 10) {
+		t.Errorf("%s = %q, want 10 bytes", FieldResponseBody, body)
+	}
+}
@@ -0,0 +1,145 @@
+package zerowrap
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+)
+
+// truncateWriter cuts oversized top-level string values of JSON entries
+// before passing them on.
+type truncateWriter struct {
+	w        io.Writer
+	maxField int
+	maxMsg   int
+}
+
+// newTruncateWriter wraps w. A limit of 0 disables it.
+func newTruncateWriter(w io.Writer, maxField, maxMsg int) *truncateWriter {
+	return &truncateWriter{w: w, maxField: maxField, maxMsg: maxMsg}
+}
+
+// Write implements io.Writer.
+func (t *truncateWriter) Write(p []byte) (int, error) {
+	return t.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (t *truncateWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if len(p) <= t.minLimit() {
+		return writeLevel(t.w, level, p)
+	}
+	out, ok := t.truncate(p)
+	if !ok {
+		return writeLevel(t.w, level, p)
+	}
+	if _, err := writeLevel(t.w, level, out); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// minLimit returns the smallest enabled limit; shorter entries cannot
+// hold an oversized value.
+func (t *truncateWriter) minLimit() int {
+	switch {
+	case t.maxField == 0:
+		return t.maxMsg
+	case t.maxMsg == 0:
+		return t.maxField
+	default:
+		return min(t.maxField, t.maxMsg)
+	}
+}
+
+// truncate returns p with oversized strings cut and FieldTruncated set,
+// keeping the field order. ok is false when nothing was cut or p is not
+// a JSON object.
+func (t *truncateWriter) truncate(p []byte) (out []byte, ok bool) {
+	buf := bytes.NewBuffer(make([]byte, 0, len(p)))
+	buf.WriteByte('{')
+	cut := false
+	err := eachField(p, func(key string, raw json.RawMessage) {
+		limit := t.maxField
+		switch key {
+		case zerolog.MessageFieldName:
+			limit = t.maxMsg
+		case zerolog.TimestampFieldName, zerolog.LevelFieldName, zerolog.CallerFieldName:
+			limit = 0
+		}
+		if limit > 0 && len(raw) > limit && raw[0] == '"' {
+			var s string
+			if err := json.Unmarshal(raw, &s); err == nil && len(s) > limit {
+				raw, _ = json.Marshal(truncateString(s, limit))
+				cut = true
+			}
+		}
+		appendField(buf, key, raw)
+	})
+	if err != nil || !cut {
+		return nil, false
+	}
+	appendField(buf, FieldTruncated, json.RawMessage("true"))
+	return closeEntry(buf, p), true
+}
+
+// eachField calls fn with the top-level fields of the JSON object p, in
+// order.
+func eachField(p []byte, fn func(key string, raw json.RawMessage)) error {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if tok, err := dec.Token(); err != nil {
+		return err
+	} else if tok != json.Delim('{') {
+		return errors.New("zerowrap: entry is not a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		fn(key, raw)
+	}
+	return nil
+}
+
+// appendField appends "key":raw to buf, which holds an object opened
+// with '{'.
+func appendField(buf *bytes.Buffer, key string, raw json.RawMessage) {
+	if buf.Len() > 1 {
+		buf.WriteByte(',')
+	}
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+	buf.Write(raw)
+}
+
+// closeEntry closes the object in buf, ending it with a newline when the
+// original entry p did.
+func closeEntry(buf *bytes.Buffer, p []byte) []byte {
+	buf.WriteByte('}')
+	if bytes.HasSuffix(p, []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	return buf.Bytes()
+}
+
+// truncateString cuts s to at most n bytes without splitting a rune.
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
@@ -0,0 +1,147 @@
+package zerowrap
+
+import (
+	"encoding/json"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// DurationFormat selects how durations are rendered.
+type DurationFormat int32
+
+// Duration formats for SetDurationFormat.
+const (
+	// DurationMillis renders float milliseconds: "duration_ms": 12.5.
+	DurationMillis DurationFormat = iota
+
+	// DurationString renders Go duration strings: "duration": "12.5ms".
+	DurationString
+
+	// DurationNanos renders integer nanoseconds: "duration_ns": 12500000.
+	DurationNanos
+)
+
+// SizeFormat selects how byte sizes are rendered.
+type SizeFormat int32
+
+// Size formats for SetSizeFormat.
+const (
+	// SizeBytes renders raw bytes: "size_bytes": 1234567.
+	SizeBytes SizeFormat = iota
+
+	// SizeHuman renders human-readable sizes: "size": "1.2MB".
+	SizeHuman
+)
+
+var (
+	durationFormat atomic.Int32
+	sizeFormat     atomic.Int32
+)
+
+// SetDurationFormat sets how durations are rendered by the request,
+// job and task logs, and by time.Duration values passed to the field
+// helpers. Defaults to DurationMillis. Like zerolog's global settings, it
+// should be set once at startup.
+//
+//	zerowrap.SetDurationFormat(zerowrap.DurationString) // "duration": "1.5s"
+func SetDurationFormat(f DurationFormat) {
+	durationFormat.Store(int32(f))
+}
+
+// SetSizeFormat sets how byte sizes are rendered by the request logs.
+// Defaults to SizeBytes.
+func SetSizeFormat(f SizeFormat) {
+	sizeFormat.Store(int32(f))
+}
+
+// AddDuration adds d to e with the field name and rendering of the
+// duration format: duration_ms, duration or duration_ns.
+//
+//	zerowrap.AddDuration(log.Info(), time.Since(start)).Msg("done")
+func AddDuration(e *zerolog.Event, d time.Duration) *zerolog.Event {
+	switch DurationFormat(durationFormat.Load()) {
+	case DurationString:
+		return e.Str(FieldDurationText, d.String())
+	case DurationNanos:
+		return e.Int64(FieldDurationNs, int64(d))
+	default:
+		return e.Float64(FieldDuration, float64(d)/float64(time.Millisecond))
+	}
+}
+
+// AddSize adds n bytes to e with the field name and rendering of the size
+// format: size_bytes or size.
+func AddSize(e *zerolog.Event, n int64) *zerolog.Event {
+	if SizeFormat(sizeFormat.Load()) == SizeHuman {
+		return e.Str(FieldSizeText, FormatSize(n))
+	}
+	return e.Int64(FieldSize, n)
+}
+
+// FormatSize renders n bytes with a decimal unit, e.g. "512B", "1.2MB".
+func FormatSize(n int64) string {
+	const unit = 1000
+	if n < unit && n > -unit {
+		return strconv.FormatInt(n, 10) + "B"
+	}
+	f := float64(n)
+	for _, suffix := range []string{"kB", "MB", "GB", "TB", "PB"} {
+		f /= unit
+		if f < unit && f > -unit {
+			return strconv.FormatFloat(f, 'f', 1, 64) + suffix
+		}
+	}
+	return strconv.FormatFloat(f/unit, 'f', 1, 64) + "EB"
+}
+
+// entryDuration parses a decoded duration field written by AddDuration
+// in any duration format.
+func entryDuration(key string, val any) (time.Duration, bool) {
+	switch v := val.(type) {
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return 0, false
+		}
+		switch key {
+		case FieldDuration:
+			return time.Duration(f * float64(time.Millisecond)), true
+		case FieldDurationNs:
+			return time.Duration(f), true
+		}
+	case string:
+		if key == FieldDurationText {
+			d, err := time.ParseDuration(v)
+			return d, err == nil
+		}
+	}
+	return 0, false
+}
+
+// durToContext adds a duration with a caller-chosen key to c, rendered
+// in the duration format.
+func durToContext(c zerolog.Context, key string, d time.Duration) zerolog.Context {
+	switch DurationFormat(durationFormat.Load()) {
+	case DurationString:
+		return c.Str(key, d.String())
+	case DurationNanos:
+		return c.Int64(key, int64(d))
+	default:
+		return c.Dur(key, d)
+	}
+}
+
+// durToEvent is durToContext for nested objects.
+func durToEvent(e *zerolog.Event, key string, d time.Duration) {
+	switch DurationFormat(durationFormat.Load()) {
+	case DurationString:
+		e.Str(key, d.String())
+	case DurationNanos:
+		e.Int64(key, int64(d))
+	default:
+		e.Dur(key, d)
+	}
+}
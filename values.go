@@ -0,0 +1,48 @@
+package zerowrap
+
+import (
+	"encoding/hex"
+	"net/url"
+	"reflect"
+)
+
+// redactURL renders u without its password and with the values of
+// DefaultRedactQueryKeys query parameters replaced by Redacted.
+func redactURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = RedactQuery(u.RawQuery, nil)
+	if _, ok := u.User.Password(); ok {
+		ReportRedacted(1)
+	}
+	return c.Redacted()
+}
+
+// uuidBytes returns the bytes of v when it is a [16]byte, or a named type
+// based on it such as uuid.UUID.
+func uuidBytes(v any) ([16]byte, bool) {
+	if b, ok := v.([16]byte); ok {
+		return b, true
+	}
+	var b [16]byte
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Array || rv.Len() != 16 || rv.Type().Elem().Kind() != reflect.Uint8 {
+		return b, false
+	}
+	reflect.Copy(reflect.ValueOf(b[:]), rv)
+	return b, true
+}
+
+// formatUUID renders b in the canonical 8-4-4-4-12 hex form.
+func formatUUID(b [16]byte) string {
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
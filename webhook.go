@@ -0,0 +1,305 @@
+package zerowrap
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Webhook payload formats supported by WebhookConfig.Format.
+const (
+	WebhookSlack   = "slack"
+	WebhookDiscord = "discord"
+	WebhookJSON    = "json"
+)
+
+// DefaultWebhookTemplate renders one line per entry.
+const DefaultWebhookTemplate = `[{{.level}}] {{.message}}{{with .error}}: {{.}}{{end}}`
+
+// discordMaxContent is Discord's message content limit.
+const discordMaxContent = 2000
+
+// WebhookConfig holds configuration for webhook notifications.
+type WebhookConfig struct {
+	// URL is the webhook endpoint.
+	URL string
+
+	// Format is the payload format: "slack", "discord" or "json".
+	// Defaults to "json" if empty or invalid.
+	Format string
+
+	// Level is the minimum level notified.
+	// Defaults to "error" if empty.
+	Level string
+
+	// Template renders each entry from its fields (text/template syntax).
+	// Defaults to DefaultWebhookTemplate if empty.
+	Template string
+
+	// Interval is the minimum time between posts; entries logged in
+	// between are batched into one message.
+	// Defaults to 5 seconds if 0.
+	Interval time.Duration
+
+	// MaxBatch caps the entries per message. Extra entries are dropped
+	// and reported as a suppressed count.
+	// Defaults to 20 if 0.
+	MaxBatch int
+
+	// Client sends the webhook requests.
+	// Defaults to an http.Client with a 10 second timeout if nil.
+	Client *http.Client
+
+	// OnError is called when a post fails.
+	// Defaults to reporting the error with ReportError if nil.
+	OnError func(error)
+}
+
+// WebhookWriter is a zerolog.LevelWriter that posts entries at or above a
+// level to a chat or generic webhook, batched and rate-limited.
+type WebhookWriter struct {
+	cfg    WebhookConfig
+	level  zerolog.Level
+	tmpl   *template.Template
+	client *http.Client
+
+	mu         sync.Mutex
+	lines      []string
+	entries    []map[string]any
+	suppressed int
+	closed     bool
+
+	wake chan struct{}
+	stop chan struct{}
+	done chan struct{}
+}
+
+// NewWebhookWriter creates a writer posting entries to cfg.URL.
+// Combine it with the regular output and call Close before exit to send
+// pending entries.
+//
+//	notify, err := zerowrap.NewWebhookWriter(zerowrap.WebhookConfig{
+//	    URL:    os.Getenv("SLACK_WEBHOOK_URL"),
+//	    Format: zerowrap.WebhookSlack,
+//	    Level:  "error",
+//	})
+//	if err != nil {
+//	    return err
+//	}
+//	defer notify.Close()
+//
+//	log := zerowrap.New(zerowrap.Config{
+//	    Format: "json",
+//	    Output: zerolog.MultiLevelWriter(os.Stderr, notify),
+//	})
+func NewWebhookWriter(cfg WebhookConfig) (*WebhookWriter, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("zerowrap: webhook URL is required")
+	}
+	text := cfg.Template
+	if text == "" {
+		text = DefaultWebhookTemplate
+	}
+	tmpl, err := template.New("webhook").Option("missingkey=zero").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("zerowrap: webhook template: %w", err)
+	}
+
+	cfg.Format = strings.ToLower(cfg.Format)
+	if cfg.Interval == 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.MaxBatch == 0 {
+		cfg.MaxBatch = 20
+	}
+	if cfg.OnError == nil {
+		cfg.OnError = func(err error) {
+			ReportError(fmt.Errorf("%w: %w", ErrWrite, err))
+		}
+	}
+	level := zerolog.ErrorLevel
+	if cfg.Level != "" {
+		level = parseLevel(cfg.Level)
+	}
+	client := cfg.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	w := &WebhookWriter{
+		cfg:    cfg,
+		level:  level,
+		tmpl:   tmpl,
+		client: client,
+		wake:   make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go w.run()
+	return w, nil
+}
+
+// Write implements io.Writer. The entry level is read from its JSON.
+func (w *WebhookWriter) Write(p []byte) (int, error) {
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return len(p), nil
+	}
+	level, err := zerolog.ParseLevel(fmt.Sprint(fields[zerolog.LevelFieldName]))
+	if err != nil {
+		return len(p), nil
+	}
+	w.add(level, fields)
+	return len(p), nil
+}
+
+// WriteLevel implements zerolog.LevelWriter.
+func (w *WebhookWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < w.level || level == zerolog.NoLevel {
+		return len(p), nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(p, &fields); err != nil {
+		return len(p), nil
+	}
+	w.add(level, fields)
+	return len(p), nil
+}
+
+// add queues an entry for the next post.
+func (w *WebhookWriter) add(level zerolog.Level, fields map[string]any) {
+	if level < w.level {
+		return
+	}
+
+	var line bytes.Buffer
+	if err := w.tmpl.Execute(&line, fields); err != nil {
+		line.Reset()
+		fmt.Fprintf(&line, "[%s] %v", level, fields[zerolog.MessageFieldName])
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	if len(w.lines) >= w.cfg.MaxBatch {
+		w.suppressed++
+		return
+	}
+	w.lines = append(w.lines, line.String())
+	w.entries = append(w.entries, fields)
+
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// run posts pending entries at most once per interval.
+func (w *WebhookWriter) run() {
+	defer close(w.done)
+	for {
+		select {
+		case <-w.wake:
+		case <-w.stop:
+			w.post()
+			return
+		}
+		w.post()
+
+		select {
+		case <-time.After(w.cfg.Interval):
+		case <-w.stop:
+			w.post()
+			return
+		}
+	}
+}
+
+// post sends the pending batch, if any.
+func (w *WebhookWriter) post() {
+	w.mu.Lock()
+	lines, entries, suppressed := w.lines, w.entries, w.suppressed
+	w.lines, w.entries, w.suppressed = nil, nil, 0
+	w.mu.Unlock()
+	if len(lines) == 0 {
+		return
+	}
+
+	text := strings.Join(lines, "\n")
+	if suppressed > 0 {
+		text += fmt.Sprintf("\n(%d more suppressed)", suppressed)
+	}
+
+	var payload any
+	switch w.cfg.Format {
+	case WebhookSlack:
+		payload = map[string]any{"text": text}
+	case WebhookDiscord:
+		if len(text) > discordMaxContent {
+			text = truncateString(text, discordMaxContent-3) + "..."
+		}
+		payload = map[string]any{"content": text}
+	default:
+		payload = map[string]any{
+			"text":       text,
+			"entries":    entries,
+			"suppressed": suppressed,
+		}
+	}
+
+	if err := w.send(payload); err != nil {
+		w.cfg.OnError(err)
+	}
+}
+
+// send posts payload as JSON.
+func (w *WebhookWriter) send(payload any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("zerowrap: webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// Close sends pending entries and stops the writer.
+// Entries written after Close are discarded.
+func (w *WebhookWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	w.mu.Unlock()
+
+	close(w.stop)
+	<-w.done
+	return nil
+}
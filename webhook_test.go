@@ -0,0 +1,47 @@
+package zerowrap
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestWebhookDiscordTruncate(t *testing.T) {
+	bodies := make(chan []byte, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var raw json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			t.Error(err)
+		}
+		bodies <- raw
+	}))
+	defer srv.Close()
+
+	w, err := NewWebhookWriter(WebhookConfig{
+		URL:      srv.URL,
+		Format:   WebhookDiscord,
+		Template: "{{.message}}",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "é" is two bytes, so the limit falls inside a rune.
+	w.Write([]byte(`{"level":"error","message":"` + strings.Repeat("é", discordMaxContent) + `"}`))
+	w.Close()
+
+	var payload struct {
+		Content string `json:"content"`
+	}
+	if err := json.Unmarshal(<-bodies, &payload); err != nil {
+		t.Fatal(err)
+	}
+	if len(payload.Content) > discordMaxContent {
+		t.Errorf("content is %d bytes, want at most %d", len(payload.Content), discordMaxContent)
+	}
+	if strings.ContainsRune(payload.Content, utf8.RuneError) || !strings.HasSuffix(payload.Content, "é...") {
+		t.Errorf("content ends with %q, want whole runes then ...", payload.Content[len(payload.Content)-8:])
+	}
+}
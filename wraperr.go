@@ -0,0 +1,97 @@
+package zerowrap
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+)
+
+var errorCallers atomic.Bool
+
+// SetErrorCallers enables recording the call site of WrapErr,
+// WrapErrWithFields and WrapErrf. Each wrap then logs the chain of call
+// sites the error passed through (error_trace field, innermost first),
+// and the returned error carries its site, so the outermost log shows the
+// whole path. Disabled by default, as it costs a runtime.Caller per wrap.
+//
+//	zerowrap.SetErrorCallers(true)
+//	// {"error":"...","error_trace":["store/db.go:42 store.(*DB).Get","api/users.go:17 api.getUser"],...}
+func SetErrorCallers(enabled bool) {
+	errorCallers.Store(enabled)
+}
+
+// WrappedError is the error returned by the WrapErr helpers when
+// SetErrorCallers is enabled. It formats like fmt.Errorf("%s: %w").
+type WrappedError struct {
+	Msg    string
+	Err    error
+	Caller string // "file:line function" of the wrap call
+}
+
+// Error implements error.
+func (e *WrappedError) Error() string {
+	return e.Msg + ": " + e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *WrappedError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCallers returns the call sites recorded by the WrapErr helpers in
+// err's chain, innermost first. Returns nil if none were recorded.
+func ErrorCallers(err error) []string {
+	var sites []string
+	for err != nil {
+		var w *WrappedError
+		if !errors.As(err, &w) {
+			break
+		}
+		sites = append(sites, w.Caller)
+		err = w.Err
+	}
+	for i, j := 0, len(sites)-1; i < j; i, j = i+1, j-1 {
+		sites[i], sites[j] = sites[j], sites[i]
+	}
+	return sites
+}
+
+// logAndWrap logs err at error level with msg and returns it wrapped.
+// When error callers are enabled, the call site skip frames above is
+// recorded and the chain of sites is logged.
+func logAndWrap(l *zerolog.Logger, err error, msg string, skip int) error {
+	if !errorCallers.Load() {
+		l.Error().Err(err).Msg(msg)
+		return fmt.Errorf("%s: %w", msg, err)
+	}
+	w := &WrappedError{Msg: msg, Err: err, Caller: callSite(skip + 1)}
+	l.Error().Err(err).Strs(FieldErrorTrace, ErrorCallers(w)).Msg(msg)
+	return w
+}
+
+// callSite returns "file:line function" for the caller skip frames above.
+func callSite(skip int) string {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "unknown"
+	}
+	site := zerolog.CallerMarshalFunc(pc, file, line)
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		site += " " + shortFuncName(fn.Name())
+	}
+	return site
+}
+
+// shortFuncName strips the import path from a function name:
+// "github.com/acme/app/store.(*DB).Get" becomes "store.(*DB).Get".
+func shortFuncName(name string) string {
+	for i := len(name) - 1; i >= 0; i-- {
+		if name[i] == '/' {
+			return name[i+1:]
+		}
+	}
+	return name
+}
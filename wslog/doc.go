@@ -0,0 +1,42 @@
+// Package wslog provides WebSocket connection logging for zerowrap.
+//
+// It does not depend on a WebSocket library: Open is called once the
+// upgrade succeeded, with the connection details from whichever library
+// is used (gorilla/websocket, coder/websocket, ...). The returned context
+// logger carries connection_id, client_ip and subprotocol for the life of
+// the connection, and Close logs the disconnect with its duration, bytes
+// and messages in and out, and the close code and reason.
+//
+// # Usage
+//
+//	func serveWS(w http.ResponseWriter, r *http.Request) {
+//	    ws, err := upgrader.Upgrade(w, r, nil) // gorilla/websocket
+//	    if err != nil {
+//	        return
+//	    }
+//	    ctx, conn := wslog.Open(r.Context(), wslog.Info{
+//	        RemoteAddr:  r.RemoteAddr,
+//	        Subprotocol: ws.Subprotocol(),
+//	    })
+//
+//	    for {
+//	        _, msg, err := ws.ReadMessage()
+//	        if err != nil {
+//	            code, reason := 0, ""
+//	            if ce, ok := err.(*websocket.CloseError); ok {
+//	                code, reason = ce.Code, ce.Text
+//	            }
+//	            conn.Close(code, reason, err)
+//	            return
+//	        }
+//	        conn.Received(len(msg))
+//	        handle(ctx, msg) // zerowrap.FromCtx(ctx) carries connection_id
+//	    }
+//	}
+//
+// # Levels
+//
+// Normal closures (1000, 1001, or no close frame and no error) log at
+// info, internal errors (1011) at error level, and abnormal closures
+// such as 1006 at warn, with the error that ended the connection.
+package wslog
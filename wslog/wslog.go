@@ -0,0 +1,154 @@
+package wslog
+
+import (
+	"context"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bnema/zerowrap"
+	"github.com/rs/zerolog"
+)
+
+// Field names logged for WebSocket connections.
+const (
+	FieldConnectionID = "connection_id"
+	FieldSubprotocol  = "subprotocol"
+	FieldBytesIn      = "bytes_in"
+	FieldBytesOut     = "bytes_out"
+	FieldMsgsIn       = "msgs_in"
+	FieldMsgsOut      = "msgs_out"
+	FieldCloseCode    = "close_code"
+	FieldCloseReason  = "close_reason"
+)
+
+// Close codes from RFC 6455 that affect the disconnect log level.
+const (
+	CloseNormalClosure   = 1000
+	CloseGoingAway       = 1001
+	CloseNoStatus        = 1005
+	CloseAbnormalClosure = 1006
+	CloseInternalError   = 1011
+)
+
+// Info describes an accepted WebSocket connection.
+type Info struct {
+	// ID identifies the connection in logs.
+	// Defaults to a new zerowrap.NewID if empty.
+	ID string
+
+	// RemoteAddr is the client address, logged as client_ip without its
+	// port. Behind proxies, pass the resolved client IP instead.
+	RemoteAddr string
+
+	// Subprotocol is the negotiated subprotocol, if any.
+	Subprotocol string
+}
+
+// Conn tracks the lifecycle of one WebSocket connection. Its counters
+// may be updated from the read and write goroutines concurrently.
+type Conn struct {
+	log   zerowrap.Logger
+	start time.Time
+
+	bytesIn  atomic.Int64
+	bytesOut atomic.Int64
+	msgsIn   atomic.Int64
+	msgsOut  atomic.Int64
+
+	closeOnce sync.Once
+}
+
+// Open returns ctx with a logger carrying connection_id, client_ip and
+// subprotocol, so everything logged while serving the connection carries
+// them, and a Conn logging the connection as established. Call Close
+// when the connection ends:
+//
+//	ctx, conn := wslog.Open(r.Context(), wslog.Info{
+//	    RemoteAddr:  r.RemoteAddr,
+//	    Subprotocol: ws.Subprotocol(),
+//	})
+//	err := serve(ctx, ws, conn) // calls conn.Received and conn.Sent per message
+//	conn.Close(int(websocket.CloseStatus(err)), "", err)
+func Open(ctx context.Context, info Info) (context.Context, *Conn) {
+	if info.ID == "" {
+		info.ID = zerowrap.NewID()
+	}
+	fields := map[string]any{FieldConnectionID: info.ID}
+	if info.RemoteAddr != "" {
+		fields[zerowrap.FieldClientIP] = remoteIP(info.RemoteAddr)
+	}
+	if info.Subprotocol != "" {
+		fields[FieldSubprotocol] = info.Subprotocol
+	}
+	ctx = zerowrap.CtxWithFields(ctx, fields)
+
+	c := &Conn{log: zerowrap.FromCtx(ctx), start: time.Now()}
+	c.log.Info().Msg("websocket connected")
+	return ctx, c
+}
+
+// Received records a message of n bytes read from the client.
+func (c *Conn) Received(n int) {
+	c.msgsIn.Add(1)
+	c.bytesIn.Add(int64(n))
+}
+
+// Sent records a message of n bytes written to the client.
+func (c *Conn) Sent(n int) {
+	c.msgsOut.Add(1)
+	c.bytesOut.Add(int64(n))
+}
+
+// Close logs the disconnect with the connection duration, bytes and
+// messages in and out, and the close code and reason. err is the error
+// that ended the connection, if any, and is only logged for abnormal
+// closures. A code of 0 or less means no close frame was received. Only
+// the first call logs.
+//
+// Normal closures (1000, 1001, or no status without error) log at info,
+// internal errors (1011) at error level, everything else at warn.
+func (c *Conn) Close(code int, reason string, err error) {
+	c.closeOnce.Do(func() {
+		level := Level(code, err)
+		e := c.log.WithLevel(level).
+			Int64(FieldBytesIn, c.bytesIn.Load()).
+			Int64(FieldBytesOut, c.bytesOut.Load()).
+			Int64(FieldMsgsIn, c.msgsIn.Load()).
+			Int64(FieldMsgsOut, c.msgsOut.Load())
+		if code > 0 {
+			e = e.Int(FieldCloseCode, code)
+		}
+		if reason != "" {
+			e = e.Str(FieldCloseReason, reason)
+		}
+		if err != nil && level > zerolog.InfoLevel {
+			e = e.Err(err)
+		}
+		zerowrap.AddDuration(e, time.Since(c.start)).Msg("websocket disconnected")
+	})
+}
+
+// Level returns the disconnect log level for a close code and the error
+// that ended the connection. A code of 0 or less means none was received.
+func Level(code int, err error) zerolog.Level {
+	switch {
+	case code == CloseNormalClosure, code == CloseGoingAway:
+		return zerolog.InfoLevel
+	case code == CloseInternalError:
+		return zerolog.ErrorLevel
+	case (code <= 0 || code == CloseNoStatus) && err == nil:
+		return zerolog.InfoLevel
+	}
+	return zerolog.WarnLevel
+}
+
+// remoteIP strips the port from a remote address.
+func remoteIP(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}